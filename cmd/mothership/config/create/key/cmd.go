@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/spf13/cobra"
@@ -21,11 +22,13 @@ func NewCmd(o *Options) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&o.DataType, "data-type", "string", fmt.Sprintf("Define data-type of the key (supported types are %s, %s, %s)",
-		model.String, model.Integer, model.Boolean))
+	cmd.Flags().StringVar(&o.DataType, "data-type", "string", fmt.Sprintf("Define data-type of the key (supported types are %s)",
+		dataTypes()))
 	cmd.Flags().BoolVar(&o.Encrypted, "encrypted", true, "Key values have to be encrypted")
 	cmd.Flags().StringVar(&o.Validator, "validator", "", "Validator logic executed when setting a new value")
-	cmd.Flags().StringVar(&o.Trigger, "trigger", "", "Trigger function executed when a value was added/changed")
+	cmd.Flags().StringVar(&o.Trigger, "trigger", "", "Trigger function executed for reconciliations which consume a value of the key")
+	cmd.Flags().StringVar(&o.TriggerPhase, "trigger-phase", string(model.TriggerPhaseEnd), fmt.Sprintf("Define when the trigger is executed (supported phases are %s, %s)",
+		model.TriggerPhaseStart, model.TriggerPhaseEnd))
 
 	if err := cobra.MarkFlagRequired(cmd.Flags(), "data-type"); err != nil {
 		panic(err) //would be an obvious bug and has to lead to a panic
@@ -34,6 +37,14 @@ func NewCmd(o *Options) *cobra.Command {
 	return cmd
 }
 
+func dataTypes() string {
+	dataTypes := make([]string, 0, len(model.DataTypes))
+	for _, dataType := range model.DataTypes {
+		dataTypes = append(dataTypes, string(dataType))
+	}
+	return strings.Join(dataTypes, ", ")
+}
+
 func Run(o *Options, keys []string) error {
 	for _, key := range keys {
 		newKey, err := createKey(o, key)
@@ -50,12 +61,17 @@ func createKey(o *Options, key string) (*model.KeyEntity, error) {
 	if err != nil {
 		return nil, err
 	}
+	phase, err := model.NewTriggerPhase(o.TriggerPhase)
+	if err != nil {
+		return nil, err
+	}
 	return o.Registry.KVRepository().CreateKey(&model.KeyEntity{
-		Key:       key,
-		DataType:  dt,
-		Encrypted: o.Encrypted,
-		Validator: o.Validator,
-		Trigger:   o.Trigger,
-		Username:  "!TODO!", //FIXME
+		Key:          key,
+		DataType:     dt,
+		Encrypted:    o.Encrypted,
+		Validator:    o.Validator,
+		Trigger:      o.Trigger,
+		TriggerPhase: phase,
+		Username:     "!TODO!", //FIXME
 	})
 }
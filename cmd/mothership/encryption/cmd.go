@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	rotateCmd "github.com/kyma-incubator/reconciler/cmd/mothership/encryption/rotate"
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *cli.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption",
+		Short: "Manage encryption of the Kyma reconciler database",
+		Long:  "Administrative CLI tool for the encryption keys of the Kyma reconciler database",
+	}
+
+	cmd.AddCommand(rotateCmd.NewCmd(rotateCmd.NewOptions(o)))
+
+	return cmd
+}
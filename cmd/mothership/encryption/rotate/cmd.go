@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+//encryptedEntities are the entities with encrypted columns
+var encryptedEntities = []db.DatabaseEntity{
+	&model.ClusterEntity{},
+	&model.ClusterConfigurationEntity{},
+}
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypt the database with the active encryption key",
+		Long: `Re-encrypt all encrypted database columns with the active encryption key ('db.encryption.keyFile').
+Values encrypted with an old key have to be decryptable by one of the old keys ('db.encryption.oldKeyFiles').
+After the rotation was successful, the old keys can be removed from the configuration.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.InitApplicationRegistry(true); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+	cmd.Flags().IntVar(&o.BatchSize, "batch-size", 100, "Number of values which are re-encrypted per transaction")
+	return cmd
+}
+
+func Run(o *Options) error {
+	rotated, err := db.NewKeyRotator(o.Registry.Connection(), o.Logger()).
+		WithBatchSize(o.BatchSize).
+		WithProgress(func(progress *db.KeyRotationProgress) {
+			fmt.Printf("Table '%s', column '%s': %d/%d values re-encrypted\n",
+				progress.Table, progress.Column, progress.Rotated, progress.Total)
+		}).
+		Rotate(encryptedEntities...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d values re-encrypted with encryption key '%s'\n", rotated, o.Registry.Connection().Encryptor().KeyID())
+	return nil
+}
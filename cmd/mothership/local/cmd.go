@@ -13,11 +13,13 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/config"
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation"
 	scheduler "github.com/kyma-incubator/reconciler/pkg/scheduler/service"
 	schedulerSvc "github.com/kyma-incubator/reconciler/pkg/scheduler/service"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	//Register all reconcilers
 	_ "github.com/kyma-incubator/reconciler/pkg/reconciler/instances"
@@ -98,6 +100,7 @@ func RunLocal(o *Options) error {
 	if err != nil {
 		return err
 	}
+	defer ws.Release()
 	defaultComponentsYaml := filepath.Join(ws.InstallationResourceDir, "components.yaml")
 
 	printStatus := func(component string, msg *reconciler.CallbackMessage) {
@@ -115,7 +118,16 @@ func RunLocal(o *Options) error {
 	cluster.Configuration.Components = comps
 
 	runtimeBuilder := schedulerSvc.NewRuntimeBuilder(reconciliation.NewInMemoryReconciliationRepository(), l)
-	reconResult, err := runtimeBuilder.RunLocal(printStatus).
+	runLocal := runtimeBuilder.RunLocal(printStatus)
+	if o.Registry != nil {
+		//merge the KV buckets of the cluster into the component configurations (like the mothership does)
+		bucketResolver, err := newBucketResolver(o)
+		if err != nil {
+			return err
+		}
+		runLocal.WithConfigurationSource(bucketResolver)
+	}
+	reconResult, err := runLocal.
 		WithSchedulerConfig(
 			&scheduler.SchedulerConfig{
 				PreComponents:            preComps,
@@ -145,6 +157,17 @@ func RunLocal(o *Options) error {
 	return nil
 }
 
+//newBucketResolver resolves the KV buckets of the cluster with the bucket layers and the landscape of the
+//mothership configuration
+func newBucketResolver(o *Options) (*cluster.BucketResolver, error) {
+	var schedulerCfg config.Config
+	if err := viper.UnmarshalKey("mothership", &schedulerCfg); err != nil {
+		return nil, errors.Wrap(err, "failed to read mothership configuration")
+	}
+	return cluster.NewBucketResolver(o.Registry.KVRepository(), schedulerCfg.ConfigBuckets, schedulerCfg.Landscape,
+		o.Logger())
+}
+
 func prepareClusterState(o *Options) (*cluster.State, error) {
 	stateString := clusterStateTemplate
 	if o.clusterState != "" {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	cfgCmd "github.com/kyma-incubator/reconciler/cmd/mothership/config"
+	encCmd "github.com/kyma-incubator/reconciler/cmd/mothership/encryption"
 	localCmd "github.com/kyma-incubator/reconciler/cmd/mothership/local"
 	msCmd "github.com/kyma-incubator/reconciler/cmd/mothership/mothership"
 	"github.com/kyma-incubator/reconciler/internal/cli"
@@ -30,6 +31,7 @@ func main() {
 		"Command line tool to administrate the Kyma reconciler system")
 
 	cmd.AddCommand(cfgCmd.NewCmd(o))
+	cmd.AddCommand(encCmd.NewCmd(o))
 	cmd.AddCommand(msCmd.NewCmd(o))
 	cmd.AddCommand(localCmd.NewCmd(localCmd.NewOptions(o)))
 
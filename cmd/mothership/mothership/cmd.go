@@ -2,6 +2,7 @@ package cmd
 
 import (
 	installCmd "github.com/kyma-incubator/reconciler/cmd/mothership/mothership/install"
+	inventoryCmd "github.com/kyma-incubator/reconciler/cmd/mothership/mothership/inventory"
 	startCmd "github.com/kyma-incubator/reconciler/cmd/mothership/mothership/start"
 	"github.com/kyma-incubator/reconciler/internal/cli"
 	"github.com/spf13/cobra"
@@ -16,6 +17,7 @@ func NewCmd(o *cli.Options) *cobra.Command {
 
 	cmd.AddCommand(startCmd.NewCmd(startCmd.NewOptions(o)))
 	cmd.AddCommand(installCmd.NewCmd(installCmd.NewOptions(o)))
+	cmd.AddCommand(inventoryCmd.NewCmd(o))
 
 	return cmd
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	exportCmd "github.com/kyma-incubator/reconciler/cmd/mothership/mothership/inventory/export"
+	importCmd "github.com/kyma-incubator/reconciler/cmd/mothership/mothership/inventory/import"
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *cli.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Export and import the inventory of the Kyma mothership reconciler",
+		Long:  "Administrative CLI tool to dump the cluster inventory, the configuration entries and the in-flight reconciliations of the mothership reconciler",
+	}
+
+	cmd.AddCommand(exportCmd.NewCmd(exportCmd.NewOptions(o)))
+	cmd.AddCommand(importCmd.NewCmd(importCmd.NewOptions(o)))
+
+	return cmd
+}
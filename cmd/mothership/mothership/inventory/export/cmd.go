@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/kyma-incubator/reconciler/pkg/backup"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the inventory into a dump file",
+		Long: `Export clusters, cluster configurations and statuses, configuration keys and values and the in-flight reconciliations into a versioned JSON dump.
+Encrypted fields are re-encrypted with the key of the key file: a new key is generated if the key file doesn't exist.
+The same key file is required to import the dump.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.InitApplicationRegistry(true); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+	cmd.Flags().StringVar(&o.KeyFile, "key-file", "", "File containing the key which encrypts the encrypted fields of the dump")
+	cmd.Flags().StringVarP(&o.OutputFile, "output", "o", "", "Dump file (the dump is written to STDOUT if undefined)")
+	return cmd
+}
+
+func Run(o *Options) error {
+	if !file.Exists(o.KeyFile) {
+		key, err := db.NewEncryptionKey()
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(o.KeyFile, []byte(key), 0600); err != nil {
+			return err
+		}
+		o.Logger().Infof("New key file '%s' created", o.KeyFile)
+	}
+	encryptor, err := db.NewEncryptorWithKeyProvider(db.NewFileKeyProvider(o.KeyFile, nil))
+	if err != nil {
+		return err
+	}
+
+	dump, err := backup.NewExporter(o.Registry.Connection(), encryptor, o.Logger()).Export()
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = os.Stdout
+	if o.OutputFile != "" {
+		outputFile, err := os.OpenFile(o.OutputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := outputFile.Close(); err != nil {
+				o.Logger().Warnf("Failed to close dump file '%s': %s", o.OutputFile, err)
+			}
+		}()
+		writer = outputFile
+	}
+	if err := dump.Write(writer); err != nil {
+		return err
+	}
+
+	if o.OutputFile != "" {
+		fmt.Printf("Exported %d clusters, %d cluster configurations, %d cluster statuses, %d keys, %d values, "+
+			"%d reconciliations and %d operations to '%s'\n", len(dump.Clusters), len(dump.ClusterConfigs),
+			len(dump.ClusterStatuses), len(dump.Keys), len(dump.Values), len(dump.Reconciliations),
+			len(dump.Operations), o.OutputFile)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+)
+
+type Options struct {
+	*cli.Options
+	KeyFile    string
+	OutputFile string
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, "", ""}
+}
+
+func (o *Options) Validate() error {
+	if o.KeyFile == "" {
+		return fmt.Errorf("Key file is undefined")
+	}
+	return nil
+}
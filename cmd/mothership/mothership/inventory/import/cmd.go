@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kyma-incubator/reconciler/pkg/backup"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import the inventory from a dump file",
+		Long: `Import a dump created by the export command. Entities keep their versions and IDs: entities which exist already are skipped, which allows to repeat an import.
+The import fails without changing the database if the references between the entities of the dump are invalid or an entity of the dump differs from an existing entity with the same key.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.InitApplicationRegistry(true); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+	cmd.Flags().StringVar(&o.KeyFile, "key-file", "", "File containing the key which was used to export the dump")
+	cmd.Flags().StringVarP(&o.InputFile, "file", "f", "", "Dump file")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Validate the dump and show which entities would be imported without changing the database")
+	return cmd
+}
+
+func Run(o *Options) error {
+	encryptor, err := db.NewEncryptorWithKeyProvider(db.NewFileKeyProvider(o.KeyFile, nil))
+	if err != nil {
+		return err
+	}
+
+	inputFile, err := os.Open(o.InputFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := inputFile.Close(); err != nil {
+			o.Logger().Warnf("Failed to close dump file '%s': %s", o.InputFile, err)
+		}
+	}()
+	dump, err := backup.ReadDump(inputFile)
+	if err != nil {
+		return err
+	}
+
+	result, err := backup.NewImporter(o.Registry.Connection(), encryptor, o.Logger()).WithDryRun(o.DryRun).Import(dump)
+	if err != nil {
+		if integrityErr, ok := err.(*backup.IntegrityError); ok {
+			for _, violation := range integrityErr.Violations {
+				fmt.Printf("Invalid reference: %s\n", violation)
+			}
+		}
+		return err
+	}
+
+	imported := "imported"
+	if result.DryRun {
+		imported = "to import"
+	}
+	for _, section := range result.Sections {
+		fmt.Printf("%s: %d %s, %d skipped (existing)\n", section.Section, section.Imported, imported, section.Skipped)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+)
+
+type Options struct {
+	*cli.Options
+	KeyFile   string
+	InputFile string
+	DryRun    bool
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, "", "", false}
+}
+
+func (o *Options) Validate() error {
+	if o.KeyFile == "" {
+		return fmt.Errorf("Key file is undefined")
+	}
+	if !file.Exists(o.KeyFile) {
+		return fmt.Errorf("Key file '%s' not found", o.KeyFile)
+	}
+	if o.InputFile == "" {
+		return fmt.Errorf("Dump file is undefined")
+	}
+	if !file.Exists(o.InputFile) {
+		return fmt.Errorf("Dump file '%s' not found", o.InputFile)
+	}
+	return nil
+}
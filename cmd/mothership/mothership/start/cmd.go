@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/db"
 	"time"
 
 	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/prefetch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -47,6 +49,9 @@ func NewCmd(o *Options) *cobra.Command {
 	cmd.Flags().DurationVarP(&o.OrphanOperationTimeout, "orphan-timeout", "", 10*time.Minute, "Timeout until a processed operation which hasn't received status updates from its worker will be restarted")
 	cmd.Flags().DurationVarP(&o.WatchInterval, "watch-interval", "", 1*time.Minute, "Size of the reconciler worker pool")
 	cmd.Flags().DurationVarP(&o.ClusterReconcileInterval, "reconcile-interval", "", 5*time.Minute, "Defines the time when a cluster will to be reconciled since his last successful reconciliation")
+	cmd.Flags().DurationVar(&o.ConfigChangeDebounce, "config-change-debounce", 1*time.Minute, "Defines the period without further configuration changes after which a cluster with changed configuration values gets reconciled")
+	cmd.Flags().DurationVar(&o.TriggerTimeout, "trigger-timeout", 30*time.Second, "Defines the maximal execution time of a trigger defined in a configuration key")
+	cmd.Flags().IntVar(&o.TriggerConcurrency, "trigger-concurrency", 5, "Defines how many reconciliations execute their triggers in parallel")
 	cmd.Flags().DurationVar(&o.PurgeEntitiesOlderThan, "purge-older-than", 14*24*time.Hour, "[Deprecated] Defines the minimum age of entities like Reconciliations and Operations that will be removed")
 	cmd.Flags().IntVar(&o.KeepLatestEntitiesCount, "cleaner-keep-n-latest", 0, "Defines the count of most recent entities the cleaner won't remove during it's operation")                            //It's set to zero to disable it by default. Change to a proper value once this mechanism is enabled in the environments.
 	cmd.Flags().IntVar(&o.KeepUnsuccessfulEntitiesDays, "cleaner-keep-failed-ops-days", 0, "Defines the number of days for which the cleaner keeps entities with unsuccessful status before removal") //It's set to zero to disable it by default. Change to a proper value once this mechanism is enabled in the environments.
@@ -56,6 +61,10 @@ func NewCmd(o *Options) *cobra.Command {
 	cmd.Flags().BoolVar(&o.AuditLog, "audit-log", false, "Enable audit logging")
 	cmd.Flags().StringVar(&o.AuditLogFile, "audit-log-file", "/var/log/auditlog/mothership-audit.log", "Path for mothership audit log file")
 	cmd.Flags().StringVar(&o.AuditLogTenantID, "audit-log-tenant-id", "", "tenant id for audit logging")
+	cmd.Flags().StringVar(&o.ValuesValidation, "values-validation", valuesValidationNone, "Validate component configurations against the charts when a cluster is created or updated: "+
+		"'none', 'schema' (values.schema.json) or 'strict' (schema and unknown keys)")
+	cmd.Flags().DurationVar(&o.ValuesValidationTimeout, "values-validation-timeout", 30*time.Second, "Maximal duration of the validation of component configurations per request, 0 means unlimited")
+	cmd.Flags().StringVar(&o.Workspace, "workspace", "", "Workspace directory used to cache Kyma sources for the validation of component configurations")
 	cmd.Flags().BoolVar(&o.StopAfterMigration, "stop-after-migrate", false, "Stop mothership after database migration to the latest release")
 	return cmd
 }
@@ -66,12 +75,28 @@ func Run(ctx context.Context, o *Options) error {
 		return err
 	}
 	o.ReconcilerList = getReconcilers(schedulerCfg)
+	o.Landscape = schedulerCfg.Landscape
+	o.Prefetcher = prefetch.NewPrefetcher(schedulerCfg.Scheduler.Reconcilers, o.Logger())
+	if o.ChartProvider, err = newChartProvider(o); err != nil {
+		return err
+	}
+	//bucket values of the effective values API and the values validation aren't cached: only reconciled
+	//configurations are tracked for configuration changes
+	if o.ConfigurationSource, err = cluster.NewBucketResolver(o.Registry.KVRepository(), schedulerCfg.ConfigBuckets,
+		schedulerCfg.Landscape, o.Logger()); err != nil {
+		return err
+	}
 	go func(ctx context.Context, o *Options) {
 		err = startScheduler(ctx, o, schedulerCfg)
 		if err != nil {
 			panic(err)
 		}
 	}(ctx, o)
+	go func(ctx context.Context, o *Options) {
+		if err := startRetention(ctx, o, schedulerCfg); err != nil {
+			panic(err)
+		}
+	}(ctx, o)
 
 	return startWebserver(ctx, o)
 }
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"net/http"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/model"
 )
@@ -27,3 +30,12 @@ func validateStatuses(statuses []string) error {
 	}
 	return nil
 }
+
+//expectedVersion returns the cluster version of the If-Match header (nil if the header isn't set)
+func expectedVersion(r *http.Request) (*cluster.Version, error) {
+	etag := r.Header.Get("If-Match")
+	if etag == "" {
+		return nil, nil
+	}
+	return cluster.ParseETag(etag)
+}
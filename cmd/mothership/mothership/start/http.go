@@ -37,6 +37,7 @@ const (
 	paramOffset          = "offset"
 	paramSchedulingID    = "schedulingID"
 	paramCorrelationID   = "correlationID"
+	paramComponent       = "component"
 
 	paramStatus     = "status"
 	paramRuntimeIDs = "runtimeID"
@@ -90,6 +91,11 @@ func startWebserver(ctx context.Context, o *Options) error {
 		callHandler(o, updateLatestCluster)).
 		Methods(http.MethodPut)
 
+	apiRouter.HandleFunc(
+		fmt.Sprintf("/v{%s}/clusters/{%s}/components/{%s}/values", paramContractVersion, paramRuntimeID, paramComponent),
+		callHandler(o, getComponentValues)).
+		Methods(http.MethodGet)
+
 	apiRouter.HandleFunc(
 		fmt.Sprintf("/v{%s}/clusters/{%s}/statusChanges", paramContractVersion, paramRuntimeID), //supports offset-param
 		callHandler(o, statusChanges)).
@@ -200,6 +206,24 @@ func createOrUpdateCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validateValues(o, clusterModel); err != nil {
+		resp := &keb.HTTPValuesValidationErrorResponse{Error: err.Error()}
+		var validationErr *valuesValidationError
+		if errors.As(err, &validationErr) {
+			resp.Violations = &validationErr.violations
+		}
+		server.SendHTTPError(w, http.StatusBadRequest, resp)
+		return
+	}
+
+	expected, err := expectedVersion(r)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusBadRequest, &keb.HTTPErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
 	clusterStateOld, err := o.Registry.Inventory().GetLatest(clusterModel.RuntimeID)
 	if err != nil && !repository.IsNotFoundError(err) {
 		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
@@ -208,7 +232,11 @@ func createOrUpdateCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clusterStateNew, err := o.Registry.Inventory().CreateOrUpdate(contractV, clusterModel)
+	clusterStateNew, err := o.Registry.Inventory().CreateOrUpdate(contractV, clusterModel, expected)
+	if cluster.IsVersionConflictError(err) {
+		sendVersionConflict(w, err.(*cluster.VersionConflictError))
+		return
+	}
 	if err != nil {
 		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
 			Error: errors.Wrap(err, "Failed to create or update cluster entity").Error(),
@@ -225,8 +253,13 @@ func createOrUpdateCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	//prepare workspaces of Kyma versions which weren't used before
+	if o.Prefetcher != nil {
+		o.Prefetcher.Notify(&clusterModel.KymaConfig)
+	}
+
 	//respond status URL
-	sendResponse(w, r, clusterStateNew, o.Registry.ReconciliationRepository())
+	sendResponse(w, r, clusterStateNew, clusterStateNew.Version(), o.Registry.ReconciliationRepository())
 }
 
 func getClustersState(o *Options, w http.ResponseWriter, r *http.Request) {
@@ -315,7 +348,7 @@ func getCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	sendResponse(w, r, clusterState, o.Registry.ReconciliationRepository())
+	sendResponse(w, r, clusterState, clusterState.Version(), o.Registry.ReconciliationRepository())
 }
 
 func updateLatestCluster(o *Options, w http.ResponseWriter, r *http.Request) {
@@ -350,19 +383,19 @@ func updateLatestCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clusterState, err := o.Registry.Inventory().GetLatest(clusterName)
+	expected, err := expectedVersion(r)
 	if err != nil {
-		httpCode := http.StatusInternalServerError
-		if repository.IsNotFoundError(err) {
-			httpCode = http.StatusNotFound
-		}
-		server.SendHTTPError(w, httpCode, &keb.HTTPErrorResponse{
-			Error: errors.Wrap(err, "Could not update cluster state").Error(),
+		server.SendHTTPError(w, http.StatusBadRequest, &keb.HTTPErrorResponse{
+			Error: err.Error(),
 		})
 		return
 	}
 
-	clusterState, err = o.Registry.Inventory().UpdateStatus(clusterState, model.Status(status.Status))
+	clusterState, err := o.Registry.Inventory().UpdateLatestStatus(clusterName, model.Status(status.Status), expected)
+	if cluster.IsVersionConflictError(err) {
+		sendVersionConflict(w, err.(*cluster.VersionConflictError))
+		return
+	}
 	if err != nil {
 		httpCode := http.StatusInternalServerError
 		if repository.IsNotFoundError(err) {
@@ -374,7 +407,7 @@ func updateLatestCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sendResponse(w, r, clusterState, o.Registry.ReconciliationRepository())
+	sendResponse(w, r, clusterState, clusterState.StatusVersion(), o.Registry.ReconciliationRepository())
 }
 
 func getReconciliations(o *Options, w http.ResponseWriter, r *http.Request) {
@@ -519,7 +552,7 @@ func getLatestCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	sendResponse(w, r, clusterState, o.Registry.ReconciliationRepository())
+	sendResponse(w, r, clusterState, clusterState.StatusVersion(), o.Registry.ReconciliationRepository())
 }
 
 func statusChanges(o *Options, w http.ResponseWriter, r *http.Request) {
@@ -595,20 +628,31 @@ func deleteCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	expected, err := expectedVersion(r)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusBadRequest, &keb.HTTPErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
 	if _, err := o.Registry.Inventory().GetLatest(runtimeID); repository.IsNotFoundError(err) {
 		server.SendHTTPError(w, http.StatusNotFound, &keb.HTTPErrorResponse{
 			Error: errors.Wrap(err, fmt.Sprintf("Deletion impossible: Cluster '%s' not found", runtimeID)).Error(),
 		})
 		return
 	}
-	state, err := o.Registry.Inventory().MarkForDeletion(runtimeID)
+	state, err := o.Registry.Inventory().MarkForDeletion(runtimeID, expected)
+	if cluster.IsVersionConflictError(err) {
+		sendVersionConflict(w, err.(*cluster.VersionConflictError))
+		return
+	}
 	if err != nil {
 		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
 			Error: errors.Wrap(err, fmt.Sprintf("Failed to delete cluster '%s'", runtimeID)).Error(),
 		})
 		return
 	}
-	sendResponse(w, r, state, o.Registry.ReconciliationRepository())
+	sendResponse(w, r, state, state.StatusVersion(), o.Registry.ReconciliationRepository())
 }
 
 func updateOperationStatus(o *Options, w http.ResponseWriter, r *http.Request) {
@@ -774,6 +818,80 @@ func getKymaConfig(o *Options, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getComponentValues(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	runtimeID, err := params.String(paramRuntimeID)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusBadRequest, &keb.HTTPErrorResponse{Error: err.Error()})
+		return
+	}
+	componentName, err := params.String(paramComponent)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusBadRequest, &keb.HTTPErrorResponse{Error: err.Error()})
+		return
+	}
+
+	state, err := o.Registry.Inventory().GetLatest(runtimeID)
+	if err != nil {
+		server.SendHTTPErrorMap(w, err)
+		return
+	}
+	if state.Configuration == nil {
+		server.SendHTTPErrorMap(w, errors.New("state configuration is nil"))
+		return
+	}
+	kymaConfig := converters.ConvertConfig(*state.Configuration)
+
+	var kebComponent *keb.Component
+	for idx := range kymaConfig.Components {
+		if kymaConfig.Components[idx].Component == componentName {
+			kebComponent = &kymaConfig.Components[idx]
+			break
+		}
+	}
+	if kebComponent == nil {
+		server.SendHTTPError(w, http.StatusNotFound, &keb.HTTPErrorResponse{
+			Error: fmt.Sprintf("component '%s' is not part of the configuration of cluster '%s'", componentName, runtimeID),
+		})
+		return
+	}
+
+	buckets, err := bucketValues(o, state)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
+			Error: errors.Wrap(err, "Failed to resolve bucket values of cluster").Error(),
+		})
+		return
+	}
+	component, err := newChartComponent(o, kymaConfig, *kebComponent, buckets)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
+			Error: errors.Wrap(err, "Failed to retrieve profile values of component").Error(),
+		})
+		return
+	}
+	values, err := o.ChartProvider.Configuration(component)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
+			Error: errors.Wrap(err, "Failed to render values of component").Error(),
+		})
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(&keb.HTTPComponentValuesResponse{
+		Component: componentName,
+		Version:   componentVersion(kymaConfig, *kebComponent),
+		Profile:   kymaConfig.Profile,
+		Landscape: o.Landscape,
+		Values:    values,
+	}); err != nil {
+		server.SendHTTPError(w, http.StatusInternalServerError, &keb.HTTPErrorResponse{
+			Error: errors.Wrap(err, "Failed to encode response payload to JSON").Error(),
+		})
+	}
+}
+
 func createOrUpdateComponentWorkerPoolOccupancy(o *Options, w http.ResponseWriter, r *http.Request) {
 
 	params := server.NewParams(r)
@@ -902,7 +1020,9 @@ func getOperationStatus(o *Options, schedulingID, correlationID string) (*model.
 	return op, err
 }
 
-func sendResponse(w http.ResponseWriter, r *http.Request, clusterState *cluster.State, reconciliationRepository reconciliation.Repository) {
+//sendResponse sends the cluster state with the given version as ETag. Endpoints which change or report the status
+//of a cluster include the status ID in the version.
+func sendResponse(w http.ResponseWriter, r *http.Request, clusterState *cluster.State, version *cluster.Version, reconciliationRepository reconciliation.Repository) {
 	respModel, err := newClusterResponse(r, clusterState, reconciliationRepository)
 	if err != nil {
 		server.SendHTTPError(w, http.StatusInternalServerError, &reconciler.HTTPErrorResponse{
@@ -912,6 +1032,7 @@ func sendResponse(w http.ResponseWriter, r *http.Request, clusterState *cluster.
 	}
 
 	w.Header().Set("content-type", "application/json")
+	w.Header().Set("ETag", version.ETag())
 	if err := json.NewEncoder(w).Encode(respModel); err != nil {
 		server.SendHTTPError(w, http.StatusInternalServerError, &reconciler.HTTPErrorResponse{
 			Error: errors.Wrap(err, "Failed to encode response payload to JSON").Error(),
@@ -919,6 +1040,17 @@ func sendResponse(w http.ResponseWriter, r *http.Request, clusterState *cluster.
 	}
 }
 
+//sendVersionConflict responds that the If-Match header doesn't match the current cluster version: the ETag header
+//contains the current version
+func sendVersionConflict(w http.ResponseWriter, err *cluster.VersionConflictError) {
+	if err.Current != nil {
+		w.Header().Set("ETag", err.Current.ETag())
+	}
+	server.SendHTTPError(w, http.StatusConflict, &keb.HTTPErrorResponse{
+		Error: err.Error(),
+	})
+}
+
 func sendClusterStateResponse(w http.ResponseWriter, state *cluster.State) {
 	respModel, err := newClusterStateResponse(state)
 	if err != nil {
@@ -929,6 +1061,7 @@ func sendClusterStateResponse(w http.ResponseWriter, state *cluster.State) {
 	}
 
 	w.Header().Set("content-type", "application/json")
+	w.Header().Set("ETag", state.Version().ETag())
 	if err := json.NewEncoder(w).Encode(respModel); err != nil {
 		server.SendHTTPError(w, http.StatusInternalServerError, &reconciler.HTTPErrorResponse{
 			Error: errors.Wrap(err, "Failed to encode response payload to JSON").Error(),
@@ -7,6 +7,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/invoker"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/prefetch"
 	"github.com/kyma-incubator/reconciler/pkg/ssl"
 )
 
@@ -30,6 +33,16 @@ type Options struct {
 	AuditLogTenantID             string
 	StopAfterMigration           bool
 	ReconcilerList               []string
+	Prefetcher                   *prefetch.Prefetcher
+	ValuesValidation             string
+	ValuesValidationTimeout      time.Duration
+	Workspace                    string
+	ChartProvider                chart.Provider
+	Landscape                    string
+	ConfigChangeDebounce         time.Duration
+	TriggerTimeout               time.Duration
+	TriggerConcurrency           int
+	ConfigurationSource          invoker.ConfigurationSource
 }
 
 func NewOptions(o *cli.Options) *Options {
@@ -52,6 +65,16 @@ func NewOptions(o *cli.Options) *Options {
 		"",                     //AuditLogTenant
 		false,                  //StopAfterMigration
 		[]string{"mothership"}, //ReconcilerList
+		nil,                    //Prefetcher
+		valuesValidationNone,   //ValuesValidation
+		0 * time.Second,        //ValuesValidationTimeout
+		"",                     //Workspace
+		nil,                    //ChartProvider
+		"",                     //Landscape
+		0 * time.Second,        //ConfigChangeDebounce
+		0 * time.Second,        //TriggerTimeout
+		0,                      //TriggerConcurrency
+		nil,                    //ConfigurationSource
 	}
 }
 
@@ -77,9 +100,24 @@ func (o *Options) Validate() error {
 	if o.KeepUnsuccessfulEntitiesDays < 0 {
 		return errors.New("cleaner count of days to keep unsuccessful entities cannot be < 0")
 	}
+	if o.ConfigChangeDebounce < 0 {
+		return errors.New("configuration change debounce period cannot be < 0")
+	}
+	if o.TriggerTimeout < 0 {
+		return errors.New("trigger timeout cannot be < 0")
+	}
+	if o.TriggerConcurrency < 0 {
+		return errors.New("trigger concurrency cannot be < 0")
+	}
 	if o.MaxParallelOperations < 0 {
 		return errors.New("maximal parallel reconciled components per cluster cannot be < 0")
 	}
+	if o.ValuesValidationTimeout < 0 {
+		return errors.New("values validation timeout cannot be < 0")
+	}
+	if err := validateValuesValidationMode(o.ValuesValidation); err != nil {
+		return err
+	}
 	if o.AuditLog {
 		if o.AuditLogFile == "" {
 			return errors.New("audit log file must be set if audit logging is enable")
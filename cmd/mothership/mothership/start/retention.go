@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/kyma-incubator/reconciler/pkg/metrics"
+	"github.com/kyma-incubator/reconciler/pkg/retention"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/config"
+	"github.com/spf13/viper"
+)
+
+func startRetention(ctx context.Context, o *Options, schedulerCfg *config.Config) error {
+	retentionCfg := schedulerCfg.Retention
+	//overwrite S3 credentials if env-vars are defined
+	if viper.IsSet("RETENTION_S3_ACCESS_KEY") {
+		retentionCfg.Archive.S3.AccessKey = viper.GetString("RETENTION_S3_ACCESS_KEY")
+	}
+	if viper.IsSet("RETENTION_S3_SECRET_KEY") {
+		retentionCfg.Archive.S3.SecretKey = viper.GetString("RETENTION_S3_SECRET_KEY")
+	}
+	if !retentionCfg.Enabled() {
+		o.Logger().Info("Retention of reconciliation history is disabled")
+		return nil
+	}
+
+	ret, err := retention.NewRetention(o.Registry.Connection(), &retentionCfg, o.Logger())
+	if err != nil {
+		return err
+	}
+	return ret.WithMetricsCollector(metrics.NewRetentionCollector()).Run(ctx)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/config"
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/service"
@@ -22,6 +23,13 @@ func startScheduler(ctx context.Context, o *Options, schedulerCfg *config.Config
 		return err
 	}
 
+	bucketResolver, err := cluster.NewBucketResolver(o.Registry.KVRepository(), schedulerCfg.ConfigBuckets,
+		schedulerCfg.Landscape, o.Logger())
+	if err != nil {
+		return err
+	}
+	bucketResolver.WithCache(o.Registry.CacheRepository())
+
 	return runtimeBuilder.
 		RunRemote(
 			o.Registry.Connection(),
@@ -44,6 +52,7 @@ func startScheduler(ctx context.Context, o *Options, schedulerCfg *config.Config
 				ClusterQueueSize:         10,
 				DeleteStrategy:           ds,
 				PreComponents:            schedulerCfg.Scheduler.PreComponents,
+				ConfigChangeDebounce:     o.ConfigChangeDebounce,
 			}).
 		WithBookkeeperConfig(&service.BookkeeperConfig{
 			OperationsWatchInterval: 45 * time.Second,
@@ -55,6 +64,15 @@ func startScheduler(ctx context.Context, o *Options, schedulerCfg *config.Config
 			KeepLatestEntitiesCount:      uintOrDie(o.KeepLatestEntitiesCount),
 			KeepUnsuccessfulEntitiesDays: uintOrDie(o.KeepUnsuccessfulEntitiesDays),
 		}).
+		WithProfileValuesSource(o.Registry.KVRepository()).
+		WithConfigurationSource(bucketResolver).
+		WithConfigChangeRepository(o.Registry.CacheRepository()).
+		WithTriggerConfig(&service.TriggerConfig{
+			Keys:          o.Registry.KVRepository(),
+			Configuration: bucketResolver,
+			Timeout:       o.TriggerTimeout,
+			Concurrency:   o.TriggerConcurrency,
+		}).
 		Run(ctx)
 }
 
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/pkg/errors"
+)
+
+const (
+	valuesValidationNone   = "none"
+	valuesValidationSchema = "schema"
+	valuesValidationStrict = "strict"
+)
+
+func validateValuesValidationMode(mode string) error {
+	switch mode {
+	case valuesValidationNone, valuesValidationSchema, valuesValidationStrict:
+		return nil
+	default:
+		return fmt.Errorf("values validation mode '%s' is not supported - choose between '%s'", mode,
+			strings.Join([]string{valuesValidationNone, valuesValidationSchema, valuesValidationStrict}, "', '"))
+	}
+}
+
+//newChartProvider returns the chart provider used to validate component configurations and to render their
+//effective values
+func newChartProvider(o *Options) (chart.Provider, error) {
+	wsFactory, err := chart.NewFactory(nil, o.Workspace, o.Logger())
+	if err != nil {
+		return nil, err
+	}
+	provider, err := chart.NewDefaultProvider(wsFactory, o.Logger())
+	if err != nil {
+		return nil, err
+	}
+	return provider.WithStrictValidation(o.ValuesValidation == valuesValidationStrict), nil
+}
+
+//newChartComponent returns the chart component the same way as it is passed to the component reconciler:
+//including the landscape, the profile values and the bucket values stored in the KV store
+func newChartComponent(o *Options, kymaConfig keb.KymaConfig, kebComponent keb.Component,
+	bucketValues map[string]interface{}) (*chart.Component, error) {
+	var profileValues map[string]interface{}
+	if o.Registry != nil {
+		var err error
+		profileValues, err = o.Registry.KVRepository().ProfileValues(kebComponent.Component, kymaConfig.Profile, o.Landscape)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return chart.NewComponentBuilder(componentVersion(kymaConfig, kebComponent), kebComponent.Component).
+		WithProfile(kymaConfig.Profile).
+		WithLandscape(o.Landscape).
+		WithProfileValues(profileValues).
+		WithNamespace(kebComponent.Namespace).
+		WithConfiguration(componentConfiguration(kebComponent, bucketValues)).
+		WithURL(kebComponent.URL).
+		Build(), nil
+}
+
+//componentConfiguration returns the configuration of the component merged on top of the bucket values
+func componentConfiguration(kebComponent keb.Component, bucketValues map[string]interface{}) map[string]interface{} {
+	configuration := kebComponent.ConfigurationAsMap()
+	result := make(map[string]interface{}, len(bucketValues)+len(configuration))
+	for key, value := range bucketValues {
+		result[key] = value
+	}
+	for key, value := range configuration {
+		result[key] = value
+	}
+	return result
+}
+
+//bucketValues returns the merged values of the KV buckets assigned to the cluster
+func bucketValues(o *Options, state *cluster.State) (map[string]interface{}, error) {
+	if o.ConfigurationSource == nil {
+		return nil, nil
+	}
+	values, _, err := o.ConfigurationSource.Values("", state)
+	return values, err
+}
+
+//valuesValidationError contains the violations of all invalid component configurations of a cluster
+type valuesValidationError struct {
+	violations []keb.ValuesViolation
+}
+
+func (e *valuesValidationError) Error() string {
+	violations := make([]string, 0, len(e.violations))
+	for _, violation := range e.violations {
+		violations = append(violations, fmt.Sprintf("component '%s' '%s': %s",
+			violation.Component, violation.Path, violation.Message))
+	}
+	return fmt.Sprintf("cluster configuration contains invalid component configurations: %s",
+		strings.Join(violations, "; "))
+}
+
+//validateValues verifies the configuration of all components of the cluster against their charts. Only invalid
+//configurations are reported as error: failures of the validation itself (e.g. an unreachable chart) are logged
+//and will surface during the reconciliation. A validation which exceeds the timeout is continued in the background
+//(the downloaded charts are cached for subsequent validations) and doesn't block the request.
+func validateValues(o *Options, kebCluster *keb.Cluster) error {
+	if o.ValuesValidation == "" || o.ValuesValidation == valuesValidationNone || o.ChartProvider == nil {
+		return nil
+	}
+	if o.ValuesValidationTimeout <= 0 {
+		return validateComponentValues(o, kebCluster)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- validateComponentValues(o, kebCluster)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(o.ValuesValidationTimeout):
+		o.Logger().Warnf("Validation of component configurations of cluster '%s' exceeded the timeout of %s "+
+			"and was skipped", kebCluster.RuntimeID, o.ValuesValidationTimeout)
+		return nil
+	}
+}
+
+func validateComponentValues(o *Options, kebCluster *keb.Cluster) error {
+	buckets, err := bucketValues(o, &cluster.State{
+		Cluster: &model.ClusterEntity{RuntimeID: kebCluster.RuntimeID, Metadata: &kebCluster.Metadata},
+	})
+	if err != nil {
+		o.Logger().Warnf("Failed to resolve bucket values for the validation of cluster '%s': %s",
+			kebCluster.RuntimeID, err)
+		return nil
+	}
+
+	var violations []keb.ValuesViolation
+	for _, kebComponent := range kebCluster.KymaConfig.Components {
+		if kebComponent.Component == model.CRDComponent || kebComponent.Component == model.CleanupComponent {
+			continue
+		}
+		component, err := newChartComponent(o, kebCluster.KymaConfig, kebComponent, buckets)
+		if err == nil {
+			err = o.ChartProvider.Validate(component)
+		}
+		if err == nil {
+			continue
+		}
+		var validationErr *chart.ValuesValidationError
+		if errors.As(err, &validationErr) {
+			for _, violation := range validationErr.Violations {
+				violations = append(violations, keb.ValuesViolation{
+					Component: validationErr.Component,
+					Path:      violation.Path,
+					Message:   violation.Reason,
+				})
+			}
+			continue
+		}
+		o.Logger().Warnf("Failed to validate configuration of component '%s' of cluster '%s': %s",
+			kebComponent.Component, kebCluster.RuntimeID, err)
+	}
+
+	if len(violations) > 0 {
+		return &valuesValidationError{violations: violations}
+	}
+	return nil
+}
+
+//componentVersion returns the version of the component the same way as the scheduler resolves it
+func componentVersion(kymaConfig keb.KymaConfig, component keb.Component) string {
+	if component.Version != "" || strings.HasSuffix(component.URL, ".git") {
+		return component.Version
+	}
+	return kymaConfig.Version
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateValues(t *testing.T) {
+	cluster := &keb.Cluster{
+		RuntimeID: "runtime",
+		KymaConfig: keb.KymaConfig{
+			Version: "2.0.0",
+			Components: []keb.Component{
+				{Component: model.CRDComponent},
+				{Component: "valid"},
+				{Component: "invalid", Version: "2.1.0"},
+				{Component: "unavailable"},
+			},
+		},
+	}
+
+	o := NewOptions(&cli.Options{})
+	require.Equal(t, valuesValidationNone, o.ValuesValidation) //validation is disabled by default
+	require.NoError(t, validateValues(o, cluster))
+
+	provider := &mocks.Provider{}
+	provider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(nil).Once()
+	provider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(&chart.ValuesValidationError{
+		Component:  "invalid",
+		Violations: []chart.ValuesViolation{{Path: "replicas", Reason: "Invalid type"}},
+	}).Once()
+	provider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(errors.New("chart not found")).Once()
+	o.ChartProvider = provider
+	require.NoError(t, validateValues(o, cluster)) //provider is only used if the validation is enabled
+
+	o.ValuesValidation = valuesValidationSchema
+	err := validateValues(o, cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "component 'invalid'")
+	require.Contains(t, err.Error(), "'replicas': Invalid type")
+	require.NotContains(t, err.Error(), "chart not found") //validation failures don't reject the cluster
+	provider.AssertNumberOfCalls(t, "Validate", 3)         //CRDs are not validated
+
+	var validationErr *valuesValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []keb.ValuesViolation{{Component: "invalid", Path: "replicas", Message: "Invalid type"}},
+		validationErr.violations)
+
+	//a validation exceeding the timeout doesn't block the request
+	slowProvider := &mocks.Provider{}
+	slowProvider.On("Validate", mock.AnythingOfType("*chart.Component")).
+		Return(&chart.ValuesValidationError{Component: "invalid"}).
+		After(100 * time.Millisecond)
+	o.ChartProvider = slowProvider
+	o.ValuesValidationTimeout = 10 * time.Millisecond
+	require.NoError(t, validateValues(o, cluster))
+}
+
+type testConfigurationSource struct {
+	values map[string]interface{}
+}
+
+func (s *testConfigurationSource) Values(_ string, _ *cluster.State) (map[string]interface{}, []string, error) {
+	return s.values, []string{"default"}, nil
+}
+
+func TestValidateValuesWithBucketValues(t *testing.T) {
+	o := NewOptions(&cli.Options{})
+	o.ValuesValidation = valuesValidationSchema
+	o.ConfigurationSource = &testConfigurationSource{values: map[string]interface{}{
+		"bucket.key":   "bucketValue",
+		"override.key": "bucketValue",
+	}}
+
+	provider := &mocks.Provider{}
+	provider.On("Validate", mock.MatchedBy(func(component *chart.Component) bool {
+		config, err := component.Configuration()
+		return err == nil &&
+			config["bucket"].(map[string]interface{})["key"] == "bucketValue" &&
+			config["override"].(map[string]interface{})["key"] == "componentValue" //component configuration wins
+	})).Return(nil).Once()
+	o.ChartProvider = provider
+
+	require.NoError(t, validateValues(o, &keb.Cluster{
+		RuntimeID: "runtime",
+		KymaConfig: keb.KymaConfig{
+			Version: "2.0.0",
+			Components: []keb.Component{{
+				Component:     "comp",
+				Configuration: []keb.Configuration{{Key: "override.key", Value: "componentValue"}},
+			}},
+		},
+	}))
+	provider.AssertExpectations(t)
+}
+
+func TestValuesValidationMode(t *testing.T) {
+	require.NoError(t, validateValuesValidationMode(valuesValidationStrict))
+	require.Error(t, validateValuesValidationMode("lenient"))
+	require.Equal(t, "2.1.0", componentVersion(keb.KymaConfig{Version: "2.0.0"}, keb.Component{Version: "2.1.0"}))
+	require.Equal(t, "2.0.0", componentVersion(keb.KymaConfig{Version: "2.0.0"}, keb.Component{}))
+	require.Equal(t, "", componentVersion(keb.KymaConfig{Version: "2.0.0"}, keb.Component{URL: "https://github.com/kyma/comp.git"}))
+}
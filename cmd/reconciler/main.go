@@ -69,6 +69,26 @@ func newCmd(o *cli.Options) *cobra.Command {
 	//file cache for Kyma sources
 	cmd.PersistentFlags().StringVar(&reconcilerOpts.Workspace, "workspace", ".",
 		"Workspace directory used to cache Kyma sources")
+	cmd.PersistentFlags().Int64Var(&reconcilerOpts.WorkspaceCacheConfig.MaxBytes, "workspace-max-bytes", 0,
+		"Maximal disk space in bytes used by cached workspaces and OCI blobs before least recently used ones are evicted (0 = unlimited)")
+	cmd.PersistentFlags().IntVar(&reconcilerOpts.WorkspaceCacheConfig.MaxWorkspaces, "workspace-max-count", 0,
+		"Maximal number of cached workspaces before least recently used workspaces are evicted (0 = unlimited)")
+
+	//cache for rendered manifests
+	cmd.PersistentFlags().Int64Var(&reconcilerOpts.RenderCacheConfig.MaxBytes, "render-cache-max-bytes", 64*1024*1024,
+		"Maximal size in bytes of rendered manifests kept in memory to avoid re-rendering the same chart and values (0 = disabled)")
+	cmd.PersistentFlags().StringVar(&reconcilerOpts.RenderCacheConfig.Dir, "render-cache-dir", "",
+		"Directory used to keep rendered manifests across restarts (only used if the render cache is enabled)")
+	cmd.PersistentFlags().Int64Var(&reconcilerOpts.RenderCacheConfig.DirMaxBytes, "render-cache-dir-max-bytes", 512*1024*1024,
+		"Maximal size in bytes of rendered manifests kept in the render cache directory")
+
+	//validation of component configurations
+	cmd.PersistentFlags().BoolVar(&reconcilerOpts.StrictValuesValidation, "strict-values-validation", false,
+		"Reject component configuration keys which are not defined in the values or the values schema of the chart")
+
+	//verification of downloaded component archives
+	cmd.PersistentFlags().StringVar(&reconcilerOpts.TrustedArchiveKeys, "archive-trusted-keys", "",
+		"File or directory (e.g. a mounted secret) with the public keys which have to sign downloaded component archives (PGP or PEM encoded, one key per file)")
 
 	cmd.PersistentFlags().BoolVarP(&reconcilerOpts.Verbose, "verbose", "v", false, "Show detailed information about the executed command actions")
 	cmd.PersistentFlags().BoolVar(&reconcilerOpts.NonInteractive, "non-interactive", false, "Enables the non-interactive shell mode")
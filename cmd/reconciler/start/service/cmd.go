@@ -30,5 +30,5 @@ func Run(o *reconCli.Options, reconcilerName string) error {
 	if err != nil {
 		return err
 	}
-	return StartWebserver(ctx, o, workerPool, tracker)
+	return StartWebserver(ctx, o, reconcilerName, workerPool, tracker)
 }
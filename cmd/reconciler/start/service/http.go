@@ -13,24 +13,25 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
 	"github.com/kyma-incubator/reconciler/pkg/server"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
 	paramContractVersion = "version"
 )
 
-func StartWebserver(ctx context.Context, o *reconCli.Options, workerPool *service.WorkerPool, tracker *service.OccupancyTracker) error {
+func StartWebserver(ctx context.Context, o *reconCli.Options, reconcilerName string, workerPool *service.WorkerPool, tracker *service.OccupancyTracker) error {
 	srv := server.Webserver{
 		Logger:     o.Logger(),
 		Port:       o.ServerConfig.Port,
 		SSLCrtFile: o.ServerConfig.SSLCrtFile,
 		SSLKeyFile: o.ServerConfig.SSLKeyFile,
-		Router:     newRouter(ctx, o, workerPool, tracker),
+		Router:     newRouter(ctx, o, reconcilerName, workerPool, tracker),
 	}
 	return srv.Start(ctx) //blocking until ctx gets closed
 }
 
-func newRouter(ctx context.Context, o *reconCli.Options, workerPool *service.WorkerPool, tracker *service.OccupancyTracker) *mux.Router {
+func newRouter(ctx context.Context, o *reconCli.Options, reconcilerName string, workerPool *service.WorkerPool, tracker *service.OccupancyTracker) *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc(
 		fmt.Sprintf("/v{%s}/run", paramContractVersion),
@@ -38,11 +39,20 @@ func newRouter(ctx context.Context, o *reconCli.Options, workerPool *service.Wor
 			reconcile(ctx, w, r, o, workerPool, tracker)
 		},
 	).Methods("PUT", "POST")
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/prefetch", paramContractVersion),
+		func(w http.ResponseWriter, r *http.Request) {
+			prefetch(w, r, o, reconcilerName)
+		},
+	).Methods("POST")
 
 	//liveness and readiness checks
 	router.HandleFunc("/health/live", live)
 	router.HandleFunc("/health/ready", ready(workerPool))
 
+	//metrics endpoint
+	router.Handle("/metrics", promhttp.Handler())
+
 	return router
 }
 
@@ -129,6 +139,39 @@ func reconcile(ctx context.Context, w http.ResponseWriter, req *http.Request, o
 	sendResponse(w)
 }
 
+func prefetch(w http.ResponseWriter, req *http.Request, o *reconCli.Options, reconcilerName string) {
+	model := &reconciler.PrefetchRequest{}
+	if err := json.NewDecoder(req.Body).Decode(model); err != nil {
+		o.Logger().Warnf("Unmarshalling of prefetch request failed: %s", err)
+		server.SendHTTPError(w, http.StatusBadRequest, &reconciler.HTTPErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if err := model.Validate(); err != nil {
+		server.SendHTTPError(w, http.StatusBadRequest, &reconciler.HTTPErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	recon, err := service.GetReconciler(reconcilerName)
+	if err != nil {
+		server.SendHTTPError(w, http.StatusInternalServerError, &reconciler.HTTPErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if _, err := recon.Prefetch(model.Version, model.Repository); err != nil {
+		server.SendHTTPError(w, http.StatusBadRequest, &reconciler.HTTPErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	o.Logger().Debugf("Prefetching workspace of version '%s'", model.Version)
+	w.WriteHeader(http.StatusAccepted)
+}
+
 func sendResponse(w http.ResponseWriter) {
 	w.Header().Set("content-type", "application/json")
 	if err := json.NewEncoder(w).Encode(&reconciler.HTTPReconciliationResponse{}); err != nil {
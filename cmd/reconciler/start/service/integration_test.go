@@ -117,7 +117,7 @@ func startReconciler(ctx context.Context, t *testing.T) {
 		workerPool, tracker, err := StartComponentReconciler(ctx, o, componentReconcilerName)
 		require.NoError(t, err)
 
-		require.NoError(t, StartWebserver(ctx, o, workerPool, tracker))
+		require.NoError(t, StartWebserver(ctx, o, componentReconcilerName, workerPool, tracker))
 	}()
 	cliTest.WaitForTCPSocket(t, "localhost", serverPort, 15*time.Second)
 }
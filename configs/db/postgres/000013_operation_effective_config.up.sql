@@ -0,0 +1 @@
+ALTER TABLE scheduler_operations ADD COLUMN "effective_config" text;
@@ -0,0 +1,9 @@
+--DDL for configuration changes which require a reconciliation of the affected cluster:
+CREATE TABLE IF NOT EXISTS config_changes (
+	"id" SERIAL UNIQUE,
+	"runtime_id" text NOT NULL,
+	"created" TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+	CONSTRAINT config_changes_pk PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS config_changes_idx_runtime_id ON config_changes ("runtime_id");
@@ -0,0 +1,2 @@
+ALTER TABLE config_keys ADD COLUMN "trigger_phase" text;
+ALTER TABLE scheduler_operations ADD COLUMN "trigger_results" text;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scheduler_reconciliations;
+
+DROP TABLE IF EXISTS scheduler_operations;
\ No newline at end of file
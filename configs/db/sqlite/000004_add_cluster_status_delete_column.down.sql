@@ -0,0 +1 @@
+ALTER TABLE inventory_cluster_config_statuses DROP COLUMN "deleted";
\ No newline at end of file
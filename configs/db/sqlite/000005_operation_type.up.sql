@@ -0,0 +1,2 @@
+--SQLite cannot add a NOT NULL constraint to an existing column: existing operations get the type by the column default
+ALTER TABLE scheduler_operations ADD COLUMN "type" VARCHAR(255) NOT NULL DEFAULT 'reconcile';
@@ -0,0 +1 @@
+ALTER TABLE scheduler_reconciliations DROP COLUMN "status";
\ No newline at end of file
@@ -0,0 +1,6 @@
+--SQLite cannot add a NOT NULL constraint to an existing column: the status is set by the default and updated afterwards
+ALTER TABLE scheduler_reconciliations ADD COLUMN "status" text NOT NULL DEFAULT '';
+
+UPDATE scheduler_reconciliations SET status=(
+    SELECT s.status FROM inventory_cluster_config_statuses AS s WHERE s.id = scheduler_reconciliations.cluster_config_status
+) WHERE cluster_config_status IN (SELECT id FROM inventory_cluster_config_statuses);
@@ -0,0 +1 @@
+UPDATE inventory_cluster_config_statuses SET status = 'reconcile_error_retryable' WHERE status = 'error_retryable'
\ No newline at end of file
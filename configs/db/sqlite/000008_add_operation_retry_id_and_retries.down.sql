@@ -0,0 +1,2 @@
+ALTER TABLE scheduler_operations DROP COLUMN "retry_id";
+ALTER TABLE scheduler_operations DROP COLUMN "retries";
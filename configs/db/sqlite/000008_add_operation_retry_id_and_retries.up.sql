@@ -0,0 +1,8 @@
+--SQLite cannot add a NOT NULL constraint to an existing column: the columns are initialized by their defaults
+ALTER TABLE scheduler_operations
+    ADD COLUMN "retry_id" VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE scheduler_operations SET retry_id = scheduling_id || correlation_id;
+
+ALTER TABLE scheduler_operations
+    ADD COLUMN "retries" int NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS worker_pool_occupancy;
\ No newline at end of file
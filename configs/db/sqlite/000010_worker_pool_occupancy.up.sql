@@ -0,0 +1,9 @@
+--DDL for worker-pool occupancy
+CREATE TABLE IF NOT EXISTS worker_pool_occupancy
+(
+    "worker_pool_id"       varchar(255) NOT NULL PRIMARY KEY,
+    "component"            varchar(255) NOT NULL,
+    "running_workers"      int          NOT NULL,
+    "worker_pool_capacity" int          NOT NULL,
+    "created"              TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+ALTER TABLE scheduler_operations DROP COLUMN "picked_up";
+ALTER TABLE scheduler_operations DROP COLUMN "processing_duration";
\ No newline at end of file
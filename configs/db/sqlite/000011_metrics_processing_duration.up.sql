@@ -0,0 +1,3 @@
+--SQLite does not allow non-constant defaults for added columns: existing operations get no pick-up time
+ALTER TABLE scheduler_operations ADD COLUMN "picked_up" TIMESTAMP;
+ALTER TABLE scheduler_operations ADD COLUMN "processing_duration" int;
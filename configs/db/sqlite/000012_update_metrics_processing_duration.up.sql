@@ -0,0 +1,2 @@
+--SQLite cannot change the default of an existing column: only the missing values are initialized
+UPDATE scheduler_operations SET processing_duration=0 WHERE processing_duration is NULL;
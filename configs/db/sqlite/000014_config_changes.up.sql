@@ -0,0 +1,8 @@
+--DDL for configuration changes which require a reconciliation of the affected cluster:
+CREATE TABLE IF NOT EXISTS config_changes (
+	"id" integer PRIMARY KEY AUTOINCREMENT,
+	"runtime_id" text NOT NULL,
+	"created" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS config_changes_idx_runtime_id ON config_changes ("runtime_id");
@@ -0,0 +1,2 @@
+ALTER TABLE config_keys DROP COLUMN "trigger_phase";
+ALTER TABLE scheduler_operations DROP COLUMN "trigger_results";
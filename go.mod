@@ -3,9 +3,12 @@ module github.com/kyma-incubator/reconciler
 go 1.16
 
 require (
+	github.com/ProtonMail/go-crypto v0.0.0-20210428141323-04723f9f07d7
 	github.com/SAP/sap-btp-service-operator v0.1.21
 	github.com/alcortesm/tgz v0.0.0-20161220082320-9c5fe88206d7
 	github.com/avast/retry-go v3.0.0+incompatible
+	github.com/aws/aws-sdk-go-v2 v1.16.16
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.27.11
 	github.com/coreos/go-semver v0.3.0
 	github.com/fatih/color v1.10.0 // indirect
 	github.com/fatih/structs v1.1.0
@@ -36,7 +39,9 @@ require (
 	github.com/stretchr/testify v1.7.0
 	github.com/tidwall/gjson v1.13.0 // indirect
 	github.com/traefik/yaegi v0.9.17
+	github.com/xeipuuv/gojsonschema v1.2.0
 	go.uber.org/zap v1.17.0
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
 	golang.org/x/sys v0.0.0-20211031064116-611d5d643895 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
@@ -50,5 +55,7 @@ require (
 	k8s.io/client-go v0.22.4
 	k8s.io/kubectl v0.22.4
 	sigs.k8s.io/controller-runtime v0.9.0
+	sigs.k8s.io/kustomize/api v0.8.11
+	sigs.k8s.io/kustomize/kyaml v0.11.0
 	sigs.k8s.io/yaml v1.2.0
 )
@@ -24,6 +24,15 @@ func NewEncryptionKey(backup bool) (string, error) {
 	if err != nil {
 		return keyFile, err
 	}
+	//key providers can require a wrapped key (e.g. encrypted by a KMS)
+	keyProvider, err := db.NewKeyProvider()
+	if err != nil {
+		return keyFile, err
+	}
+	encKey, err = keyProvider.WrapKey(encKey)
+	if err != nil {
+		return keyFile, err
+	}
 
 	if file.Exists(keyFile) && backup {
 		keyFileBackup := fmt.Sprintf("%s.%d.bak", keyFile, time.Now().Unix())
@@ -6,18 +6,26 @@ import (
 
 type Options struct {
 	*cli.Options
-	Workspace             string
-	ServerConfig          *ServerConfig
-	WorkerConfig          *WorkerConfig
-	RetryConfig           *RetryConfig
-	HeartbeatSenderConfig *RecurringTaskConfig
-	ProgressTrackerConfig *RecurringTaskConfig
+	Workspace              string
+	WorkspaceCacheConfig   *WorkspaceCacheConfig
+	RenderCacheConfig      *RenderCacheConfig
+	StrictValuesValidation bool
+	TrustedArchiveKeys     string
+	ServerConfig           *ServerConfig
+	WorkerConfig           *WorkerConfig
+	RetryConfig            *RetryConfig
+	HeartbeatSenderConfig  *RecurringTaskConfig
+	ProgressTrackerConfig  *RecurringTaskConfig
 }
 
 func NewOptions(o *cli.Options) *Options {
 	return &Options{
 		o,
 		".",
+		&WorkspaceCacheConfig{},
+		&RenderCacheConfig{},
+		false,
+		"",
 		&ServerConfig{},
 		&WorkerConfig{},
 		&RetryConfig{},
@@ -30,6 +38,12 @@ func (o *Options) Validate() error {
 	if o.Workspace == "" {
 		o.Workspace = "."
 	}
+	if err := o.WorkspaceCacheConfig.validate(); err != nil {
+		return err
+	}
+	if err := o.RenderCacheConfig.validate(); err != nil {
+		return err
+	}
 	if err := o.ServerConfig.validate(); err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package reconciler
 
 import (
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
 )
 
@@ -14,7 +15,18 @@ func NewComponentReconciler(o *Options, reconcilerName string) (*service.Compone
 		recon.Debug()
 	}
 
+	if o.TrustedArchiveKeys != "" {
+		keys, err := chart.LoadTrustedKeys(o.TrustedArchiveKeys)
+		if err != nil {
+			return nil, err
+		}
+		recon.WithTrustedArchiveKeys(keys)
+	}
+
 	recon.WithWorkspace(o.Workspace).
+		WithWorkspaceCacheQuota(o.WorkspaceCacheConfig.MaxBytes, o.WorkspaceCacheConfig.MaxWorkspaces).
+		WithRenderCache(o.RenderCacheConfig.MaxBytes, o.RenderCacheConfig.Dir, o.RenderCacheConfig.DirMaxBytes).
+		WithStrictValuesValidation(o.StrictValuesValidation).
 		//configure reconciliation worker pool + retry-behaviour
 		WithWorkers(o.WorkerConfig.Workers, o.WorkerConfig.Timeout).
 		WithRetryDelay(o.RetryConfig.RetryDelay).
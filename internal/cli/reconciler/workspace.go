@@ -0,0 +1,36 @@
+package reconciler
+
+import (
+	"fmt"
+)
+
+type WorkspaceCacheConfig struct {
+	MaxBytes      int64
+	MaxWorkspaces int
+}
+
+func (c *WorkspaceCacheConfig) validate() error {
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("max-bytes of workspace cache cannot be < 0")
+	}
+	if c.MaxWorkspaces < 0 {
+		return fmt.Errorf("max-workspaces of workspace cache cannot be < 0")
+	}
+	return nil
+}
+
+type RenderCacheConfig struct {
+	MaxBytes    int64
+	Dir         string
+	DirMaxBytes int64
+}
+
+func (c *RenderCacheConfig) validate() error {
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("max-bytes of render cache cannot be < 0")
+	}
+	if c.Dir != "" && c.DirMaxBytes <= 0 {
+		return fmt.Errorf("dir-max-bytes of render cache has to be > 0 if a directory is configured")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package persistency
 
 import (
+	"github.com/kyma-incubator/reconciler/pkg/cache"
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/db"
 	"github.com/kyma-incubator/reconciler/pkg/kv"
@@ -17,6 +18,7 @@ type Registry struct {
 	connection        db.Connection
 	inventory         cluster.Inventory
 	kvRepository      *kv.Repository
+	cacheRepository   *cache.Repository
 	reconRepository   reconciliation.Repository
 	occupancyRepo     occupancy.Repository
 	occupancyTracking bool
@@ -49,6 +51,9 @@ func (or *Registry) init() error {
 	if or.kvRepository, err = or.initRepository(); err != nil {
 		return err
 	}
+	if or.cacheRepository, err = or.initCacheRepository(); err != nil {
+		return err
+	}
 	if or.reconRepository, err = or.initReconciliationRepository(); err != nil {
 		return err
 	}
@@ -80,6 +85,10 @@ func (or *Registry) KVRepository() *kv.Repository {
 	return or.kvRepository
 }
 
+func (or *Registry) CacheRepository() *cache.Repository {
+	return or.cacheRepository
+}
+
 func (or *Registry) ReconciliationRepository() reconciliation.Repository {
 	return or.reconRepository
 }
@@ -96,6 +105,14 @@ func (or *Registry) initRepository() (*kv.Repository, error) {
 	return repository, err
 }
 
+func (or *Registry) initCacheRepository() (*cache.Repository, error) {
+	repository, err := cache.NewRepository(or.connection, or.debug)
+	if err != nil {
+		or.logger.Errorf("Failed to create cache repository: %s", err)
+	}
+	return repository, err
+}
+
 func (or *Registry) initInventory() (cluster.Inventory, error) {
 	collector := metrics.NewReconciliationStatusCollector()
 	inventory, err := cluster.NewInventory(or.connection, or.debug, collector)
@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	kebTest "github.com/kyma-incubator/reconciler/pkg/keb/test"
+	"github.com/kyma-incubator/reconciler/pkg/kv"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	exportEncryptor := newEncryptor(t)
+
+	t.Run("Export and import inventory", func(t *testing.T) {
+		source := db.NewTestSqliteConnection(t)
+		runtimeIDs := createInventory(t, source)
+		dump := exportDump(t, source, exportEncryptor)
+
+		require.Len(t, dump.Clusters, 3)
+		require.Len(t, dump.ClusterConfigs, 3)
+		require.Len(t, dump.ClusterStatuses, 5)
+		require.Len(t, dump.Keys, 1)
+		require.Len(t, dump.Values, 1)
+		require.Len(t, dump.Reconciliations, 1) //finished reconciliations are not exported
+		require.Len(t, dump.Operations, 3)
+		for _, record := range dump.Clusters { //encrypted with the export key
+			require.True(t, strings.HasPrefix(strings.Trim(string(record["Kubeconfig"]), `"`), exportEncryptor.KeyID()))
+		}
+
+		target := db.NewTestSqliteConnection(t)
+		result, err := NewImporter(target, exportEncryptor, logger.NewLogger(true)).Import(dump)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{
+			"clusters": 3, "clusterConfigs": 3, "clusterStatuses": 5, "keys": 1, "values": 1,
+			"reconciliations": 1, "operations": 3,
+		}, imported(result))
+
+		//imported entities are equal to the exported entities
+		for _, runtimeID := range runtimeIDs {
+			requireEqualState(t, source, target, runtimeID)
+		}
+		require.Equal(t, dump.Reconciliations, exportDump(t, target, exportEncryptor).Reconciliations)
+
+		//sequences continue after the imported keys
+		inventory, err := cluster.NewInventory(target, true, cluster.MetricsCollectorMock{})
+		require.NoError(t, err)
+		state, err := inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "new", 1, false, kebTest.OneComponentDummy), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), state.Cluster.Version)
+	})
+
+	t.Run("Import is idempotent", func(t *testing.T) {
+		source := db.NewTestSqliteConnection(t)
+		createInventory(t, source)
+		dump := exportDump(t, source, exportEncryptor)
+
+		target := db.NewTestSqliteConnection(t)
+		importer := NewImporter(target, exportEncryptor, logger.NewLogger(true))
+		_, err := importer.Import(dump)
+		require.NoError(t, err)
+		result, err := importer.Import(dump)
+		require.NoError(t, err)
+		for _, section := range result.Sections {
+			require.Zero(t, section.Imported)
+			require.Equal(t, len(*sectionByName(section.Section).records(dump)), section.Skipped)
+		}
+	})
+
+	t.Run("Dry-run doesn't change the database", func(t *testing.T) {
+		source := db.NewTestSqliteConnection(t)
+		createInventory(t, source)
+		dump := exportDump(t, source, exportEncryptor)
+
+		target := db.NewTestSqliteConnection(t)
+		result, err := NewImporter(target, exportEncryptor, logger.NewLogger(true)).WithDryRun(true).Import(dump)
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Equal(t, 3, imported(result)["clusters"])
+		require.Empty(t, exportDump(t, target, exportEncryptor).Clusters)
+	})
+
+	t.Run("Dry-run detects violations of the referential integrity", func(t *testing.T) {
+		source := db.NewTestSqliteConnection(t)
+		createInventory(t, source)
+		dump := exportDump(t, source, exportEncryptor)
+		dump.Clusters = dump.Clusters[1:]                        //configuration and statuses of first cluster are orphaned
+		dump.Operations[0]["SchedulingID"] = []byte(`"unknown"`) //operation of unknown reconciliation
+
+		_, err := NewImporter(db.NewTestSqliteConnection(t), exportEncryptor, logger.NewLogger(true)).
+			WithDryRun(true).Import(dump)
+		require.Error(t, err)
+		require.True(t, IsIntegrityError(err))
+		require.Len(t, err.(*IntegrityError).Violations, 2)
+		require.Contains(t, err.Error(), "cluster configuration 1 references missing cluster version 1")
+		require.Contains(t, err.Error(), "references missing reconciliation 'unknown'")
+	})
+
+	t.Run("Import fails for conflicting entities", func(t *testing.T) {
+		source := db.NewTestSqliteConnection(t)
+		createInventory(t, source)
+		dump := exportDump(t, source, exportEncryptor)
+
+		target := db.NewTestSqliteConnection(t)
+		inventory, err := cluster.NewInventory(target, true, cluster.MetricsCollectorMock{})
+		require.NoError(t, err)
+		_, err = inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "other", 1, false, kebTest.OneComponentDummy), nil)
+		require.NoError(t, err)
+
+		_, err = NewImporter(target, exportEncryptor, logger.NewLogger(true)).Import(dump)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "clusters with key Version=1")
+		require.Len(t, exportDump(t, target, exportEncryptor).Clusters, 1) //import was rolled back
+	})
+
+	t.Run("Import requires the export key", func(t *testing.T) {
+		source := db.NewTestSqliteConnection(t)
+		createInventory(t, source)
+		dump := exportDump(t, source, exportEncryptor)
+
+		_, err := NewImporter(db.NewTestSqliteConnection(t), newEncryptor(t), logger.NewLogger(true)).Import(dump)
+		require.Error(t, err)
+	})
+
+	t.Run("Reject unsupported format version", func(t *testing.T) {
+		_, err := ReadDump(strings.NewReader(`{"version": 99}`))
+		require.Error(t, err)
+	})
+}
+
+func newEncryptor(t *testing.T) *db.Encryptor {
+	key, err := db.NewEncryptionKey()
+	require.NoError(t, err)
+	encryptor, err := db.NewEncryptor(key)
+	require.NoError(t, err)
+	return encryptor
+}
+
+//exportDump exports the database and returns the dump after writing and reading it
+func exportDump(t *testing.T, conn db.Connection, encryptor *db.Encryptor) *Dump {
+	dump, err := NewExporter(conn, encryptor, logger.NewLogger(true)).Export()
+	require.NoError(t, err)
+	buffer := &bytes.Buffer{}
+	require.NoError(t, dump.Write(buffer))
+	dump, err = ReadDump(buffer)
+	require.NoError(t, err)
+	return dump
+}
+
+//createInventory creates two clusters (one of them with two versions), a configuration key with a value,
+//a finished reconciliation and an in-flight reconciliation
+func createInventory(t *testing.T, conn db.Connection) []string {
+	inventory, err := cluster.NewInventory(conn, true, cluster.MetricsCollectorMock{})
+	require.NoError(t, err)
+	reconRepo, err := reconciliation.NewPersistedReconciliationRepository(conn, true)
+	require.NoError(t, err)
+
+	cluster1 := kebTest.NewCluster(t, "1", 1, false, kebTest.OneComponentDummy)
+	state1, err := inventory.CreateOrUpdate(1, cluster1, nil)
+	require.NoError(t, err)
+	recon1, err := reconRepo.CreateReconciliation(state1, &model.ReconciliationSequenceConfig{})
+	require.NoError(t, err)
+	state1, err = inventory.UpdateStatus(state1, model.ClusterStatusReady)
+	require.NoError(t, err)
+	require.NoError(t, reconRepo.FinishReconciliation(recon1.SchedulingID, state1.Status))
+	state1, err = inventory.CreateOrUpdate(1, kebTest.NewClusterFromExisting(*cluster1, 2, true), nil)
+	require.NoError(t, err)
+
+	state2, err := inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "2", 1, false, kebTest.OneComponentDummy), nil)
+	require.NoError(t, err)
+	_, err = inventory.UpdateStatus(state2, model.ClusterStatusReady)
+	require.NoError(t, err)
+	state2, err = inventory.GetLatest(state2.Cluster.RuntimeID)
+	require.NoError(t, err)
+	_, err = reconRepo.CreateReconciliation(state2, &model.ReconciliationSequenceConfig{})
+	require.NoError(t, err)
+
+	kvRepo, err := kv.NewRepository(conn, true)
+	require.NoError(t, err)
+	key, err := kvRepo.CreateKey(&model.KeyEntity{Key: "global.domain", DataType: model.String, Username: "test"})
+	require.NoError(t, err)
+	_, err = kvRepo.CreateValue(&model.ValueEntity{
+		Key: key.Key, KeyVersion: key.Version, Bucket: model.DefaultBucket, Value: "example.com",
+		DataType: model.String, Username: "test",
+	})
+	require.NoError(t, err)
+
+	return []string{state1.Cluster.RuntimeID, state2.Cluster.RuntimeID}
+}
+
+func requireEqualState(t *testing.T, source, target db.Connection, runtimeID string) {
+	sourceInventory, err := cluster.NewInventory(source, true, cluster.MetricsCollectorMock{})
+	require.NoError(t, err)
+	targetInventory, err := cluster.NewInventory(target, true, cluster.MetricsCollectorMock{})
+	require.NoError(t, err)
+	expected, err := sourceInventory.GetLatest(runtimeID)
+	require.NoError(t, err)
+	got, err := targetInventory.GetLatest(runtimeID)
+	require.NoError(t, err)
+	require.Equal(t, expected.Cluster.Version, got.Cluster.Version)
+	require.Equal(t, expected.Cluster.Kubeconfig, got.Cluster.Kubeconfig)
+	require.True(t, expected.Cluster.Equal(got.Cluster))
+	require.True(t, expected.Configuration.Equal(got.Configuration))
+	require.Equal(t, expected.Status.ID, got.Status.ID)
+	require.Equal(t, expected.Status.Status, got.Status.Status)
+}
+
+func imported(result *ImportResult) map[string]int {
+	counts := make(map[string]int, len(result.Sections))
+	for _, section := range result.Sections {
+		counts[section.Section] = section.Imported
+	}
+	return counts
+}
+
+func sectionByName(name string) *section {
+	for _, section := range sections {
+		if section.name == name {
+			return section
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/pkg/errors"
+)
+
+//FormatVersion is the version of the dump format: it has to be increased for incompatible changes of the format
+const FormatVersion = 1
+
+//Record is an exported entity: the JSON values of the entity fields by field name. Encrypted fields contain
+//the JSON value encrypted with the key of the dump.
+type Record map[string]json.RawMessage
+
+//Dump contains the exported inventory
+type Dump struct {
+	Version         int       `json:"version"`
+	Created         time.Time `json:"created"`
+	KeyID           string    `json:"keyID"` //ID of the key which encrypted the encrypted fields
+	Clusters        []Record  `json:"clusters"`
+	ClusterConfigs  []Record  `json:"clusterConfigs"`
+	ClusterStatuses []Record  `json:"clusterStatuses"`
+	Keys            []Record  `json:"keys"`
+	Values          []Record  `json:"values"`
+	Reconciliations []Record  `json:"reconciliations"` //in-flight reconciliations
+	Operations      []Record  `json:"operations"`      //operations of the in-flight reconciliations
+}
+
+func ReadDump(reader io.Reader) (*Dump, error) {
+	dump := &Dump{}
+	if err := json.NewDecoder(reader).Decode(dump); err != nil {
+		return nil, errors.Wrap(err, "failed to decode dump")
+	}
+	if dump.Version != FormatVersion {
+		return nil, fmt.Errorf("dump format version %d is not supported (supported version is %d)",
+			dump.Version, FormatVersion)
+	}
+	return dump, nil
+}
+
+func (d *Dump) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+//section describes how the entities of a table are exported and imported
+type section struct {
+	name    string
+	entity  db.DatabaseEntity
+	keys    []string //fields which identify an entity
+	serial  string   //field whose values are generated by a sequence (empty if the table has no sequence)
+	records func(dump *Dump) *[]Record
+	filter  func(query *db.Select) *db.Select //restricts the exported entities (all entities are exported if nil)
+}
+
+//sections are ordered by their dependencies: entities are imported after the entities they are referencing
+var sections = []*section{
+	{
+		name:    "clusters",
+		entity:  &model.ClusterEntity{},
+		keys:    []string{"Version"},
+		serial:  "Version",
+		records: func(dump *Dump) *[]Record { return &dump.Clusters },
+	},
+	{
+		name:    "clusterConfigs",
+		entity:  &model.ClusterConfigurationEntity{},
+		keys:    []string{"Version"},
+		serial:  "Version",
+		records: func(dump *Dump) *[]Record { return &dump.ClusterConfigs },
+	},
+	{
+		name:    "clusterStatuses",
+		entity:  &model.ClusterStatusEntity{},
+		keys:    []string{"ID"},
+		serial:  "ID",
+		records: func(dump *Dump) *[]Record { return &dump.ClusterStatuses },
+	},
+	{
+		name:    "keys",
+		entity:  &model.KeyEntity{},
+		keys:    []string{"Version"},
+		serial:  "Version",
+		records: func(dump *Dump) *[]Record { return &dump.Keys },
+	},
+	{
+		name:    "values",
+		entity:  &model.ValueEntity{},
+		keys:    []string{"Version"},
+		serial:  "Version",
+		records: func(dump *Dump) *[]Record { return &dump.Values },
+	},
+	{
+		name:    "reconciliations",
+		entity:  &model.ReconciliationEntity{},
+		keys:    []string{"SchedulingID"},
+		records: func(dump *Dump) *[]Record { return &dump.Reconciliations },
+		filter: func(query *db.Select) *db.Select {
+			return query.Where(map[string]interface{}{"Finished": false})
+		},
+	},
+	{
+		name:    "operations",
+		entity:  &model.OperationEntity{},
+		keys:    []string{"SchedulingID", "CorrelationID"},
+		records: func(dump *Dump) *[]Record { return &dump.Operations },
+		filter: func(query *db.Select) *db.Select {
+			return query.WhereIn("SchedulingID",
+				"SELECT scheduling_id FROM scheduler_reconciliations WHERE finished = $1", false)
+		},
+	},
+}
+
+//encodeRecord converts an entity into a record and encrypts its encrypted fields
+func encodeRecord(entity db.DatabaseEntity, encryptor *db.Encryptor) (Record, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	record := Record{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	for _, field := range db.EncryptedFieldNames(entity) {
+		encValue, err := encryptor.Encrypt(string(record[field]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encrypt field '%s' of %s", field, entity)
+		}
+		if record[field], err = json.Marshal(encValue); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+//decodeRecord decrypts the encrypted fields of a record and converts it into an entity
+func decodeRecord(record Record, prototype db.DatabaseEntity, encryptor *db.Encryptor) (db.DatabaseEntity, error) {
+	fields := make(Record, len(record))
+	for field, value := range record {
+		fields[field] = value
+	}
+	for _, field := range db.EncryptedFieldNames(prototype) {
+		var encValue string
+		if err := json.Unmarshal(fields[field], &encValue); err != nil {
+			return nil, errors.Wrapf(err, "encrypted field '%s' is not a string", field)
+		}
+		value, err := encryptor.Decrypt(encValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt field '%s'", field)
+		}
+		fields[field] = json.RawMessage(value)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	entity := prototype.New()
+	return entity, json.Unmarshal(data, entity)
+}
@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+//Exporter dumps the inventory (clusters, cluster configurations and statuses), the configuration keys and values
+//and the in-flight reconciliations. Encrypted fields are decrypted with the keys of the database and
+//re-encrypted with the key of the dump.
+type Exporter struct {
+	conn      db.Connection
+	encryptor *db.Encryptor
+	logger    *zap.SugaredLogger
+}
+
+func NewExporter(conn db.Connection, encryptor *db.Encryptor, logger *zap.SugaredLogger) *Exporter {
+	return &Exporter{
+		conn:      conn,
+		encryptor: encryptor,
+		logger:    logger,
+	}
+}
+
+func (e *Exporter) Export() (*Dump, error) {
+	dump := &Dump{
+		Version: FormatVersion,
+		Created: time.Now().UTC(),
+		KeyID:   e.encryptor.KeyID(),
+	}
+	//all entities are read in one transaction to get a consistent snapshot
+	err := db.Transaction(e.conn, func(tx *db.TxConnection) error {
+		for _, section := range sections {
+			records, err := e.exportSection(tx, section)
+			if err != nil {
+				return errors.Wrapf(err, "failed to export %s", section.name)
+			}
+			*section.records(dump) = records
+			e.logger.Debugf("Exported %d %s", len(records), section.name)
+		}
+		return nil
+	}, e.logger)
+	if err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+func (e *Exporter) exportSection(tx *db.TxConnection, section *section) ([]Record, error) {
+	q, err := db.NewQuery(tx, section.entity.New(), e.logger)
+	if err != nil {
+		return nil, err
+	}
+	query := q.Select()
+	if section.filter != nil {
+		query = section.filter(query)
+	}
+	orderBy := make(map[string]string, len(section.keys))
+	for _, key := range section.keys {
+		orderBy[key] = "ASC"
+	}
+	entities, err := query.OrderBy(orderBy).GetMany()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(entities))
+	for _, entity := range entities {
+		record, err := encodeRecord(entity, e.encryptor)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,169 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/structs"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+//SectionResult counts the entities of a section which were processed by an import
+type SectionResult struct {
+	Section  string
+	Imported int //entities which were inserted (or would be inserted by a dry-run)
+	Skipped  int //entities which exist already
+}
+
+type ImportResult struct {
+	DryRun   bool
+	Sections []*SectionResult
+}
+
+//Importer inserts the entities of a dump into the database. Entities keep their keys (versions and IDs) and
+//entities which exist already are skipped: importing a dump twice doesn't change the database.
+type Importer struct {
+	conn      db.Connection
+	encryptor *db.Encryptor
+	dryRun    bool
+	logger    *zap.SugaredLogger
+}
+
+func NewImporter(conn db.Connection, encryptor *db.Encryptor, logger *zap.SugaredLogger) *Importer {
+	return &Importer{
+		conn:      conn,
+		encryptor: encryptor,
+		logger:    logger,
+	}
+}
+
+//WithDryRun validates the dump and checks which entities would be imported without changing the database
+func (i *Importer) WithDryRun(dryRun bool) *Importer {
+	i.dryRun = dryRun
+	return i
+}
+
+func (i *Importer) Import(dump *Dump) (*ImportResult, error) {
+	if dump.KeyID != i.encryptor.KeyID() {
+		return nil, fmt.Errorf("dump is encrypted with key '%s' but the provided key has the ID '%s'",
+			dump.KeyID, i.encryptor.KeyID())
+	}
+
+	entities := make(entitySet, len(sections))
+	for _, section := range sections {
+		for idx, record := range *section.records(dump) {
+			entity, err := decodeRecord(record, section.entity, i.encryptor)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode entry %d of %s", idx, section.name)
+			}
+			entities[section.name] = append(entities[section.name], entity)
+		}
+	}
+	if err := validate(entities); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{DryRun: i.dryRun}
+	err := db.Transaction(i.conn, func(tx *db.TxConnection) error {
+		var conflicts []string
+		for _, section := range sections {
+			sectionResult := &SectionResult{Section: section.name}
+			result.Sections = append(result.Sections, sectionResult)
+			for _, entity := range entities[section.name] {
+				existing, err := i.find(tx, section, entity)
+				if err != nil {
+					return errors.Wrapf(err, "failed to check whether %s exists", entity)
+				}
+				if existing != nil {
+					if !existing.Equal(entity) {
+						conflicts = append(conflicts, fmt.Sprintf("%s with key %s",
+							section.name, entityKey(section, entity)))
+					}
+					sectionResult.Skipped++
+					continue
+				}
+				if !i.dryRun {
+					if err := i.insert(tx, entity); err != nil {
+						return errors.Wrapf(err, "failed to import %s", entity)
+					}
+				}
+				sectionResult.Imported++
+			}
+			if !i.dryRun && sectionResult.Imported > 0 && section.serial != "" && tx.Type() == db.Postgres {
+				if err := i.updateSequence(tx, section); err != nil {
+					return errors.Wrapf(err, "failed to update sequence of %s", section.name)
+				}
+			}
+			i.logger.Debugf("Processed %s: %d imported, %d skipped", section.name,
+				sectionResult.Imported, sectionResult.Skipped)
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("entities of the dump differ from existing entities with the same key: %s",
+				strings.Join(conflicts, ", "))
+		}
+		return nil
+	}, i.logger)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//find returns the entity stored with the key of the given entity (nil if no such entity exists)
+func (i *Importer) find(tx *db.TxConnection, section *section, entity db.DatabaseEntity) (db.DatabaseEntity, error) {
+	q, err := db.NewQuery(tx, section.entity.New(), i.logger)
+	if err != nil {
+		return nil, err
+	}
+	fields := structs.New(entity)
+	conditions := make(map[string]interface{}, len(section.keys))
+	for _, key := range section.keys {
+		conditions[key] = fields.Field(key).Value()
+	}
+	existing, err := q.Select().Where(conditions).GetMany()
+	if err != nil || len(existing) == 0 {
+		return nil, err
+	}
+	return existing[0], nil
+}
+
+//insert stores an entity including its read-only fields (keys generated by sequences and creation timestamps)
+func (i *Importer) insert(tx *db.TxConnection, entity db.DatabaseEntity) error {
+	colHdlr, err := db.NewColumnHandler(entity, tx, i.logger)
+	if err != nil {
+		return err
+	}
+	if err := colHdlr.Validate(); err != nil {
+		return err
+	}
+	placeholders, err := colHdlr.ColumnValuesPlaceholderCsv(false)
+	if err != nil {
+		return err
+	}
+	values, err := colHdlr.ColumnValues(false)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		entity.Table(), colHdlr.ColumnNamesCsv(false), placeholders), values...)
+	return err
+}
+
+//updateSequence continues the sequence of a Postgres table after the highest imported key
+//(SQLite continues AUTOINCREMENT columns after the highest key automatically)
+func (i *Importer) updateSequence(tx *db.TxConnection, section *section) error {
+	colHdlr, err := db.NewColumnHandler(section.entity.New(), tx, i.logger)
+	if err != nil {
+		return err
+	}
+	column, err := colHdlr.ColumnName(section.serial)
+	if err != nil {
+		return err
+	}
+	table := section.entity.Table()
+	_, err = tx.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), MAX(%s)) FROM %s",
+		table, column, column, table))
+	return err
+}
@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/structs"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+)
+
+//IntegrityError lists the violations of the referential integrity of a dump
+type IntegrityError struct {
+	Violations []string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("dump violates the referential integrity (%d violations): %s",
+		len(e.Violations), strings.Join(e.Violations, "; "))
+}
+
+func IsIntegrityError(err error) bool {
+	_, ok := err.(*IntegrityError)
+	return ok
+}
+
+//entitySet contains the decoded entities of a dump by section name
+type entitySet map[string][]db.DatabaseEntity
+
+//validate verifies that the keys of the entities are unique and that all references between the entities
+//can be resolved within the dump
+func validate(entities entitySet) error {
+	var violations []string
+	addViolation := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	for _, section := range sections {
+		keys := make(map[string]bool, len(entities[section.name]))
+		for _, entity := range entities[section.name] {
+			key := entityKey(section, entity)
+			if keys[key] {
+				addViolation("%s contains duplicate key %s", section.name, key)
+			}
+			keys[key] = true
+		}
+	}
+
+	clusters := make(map[int64]*model.ClusterEntity)
+	for _, entity := range entities["clusters"] {
+		cluster := entity.(*model.ClusterEntity)
+		clusters[cluster.Version] = cluster
+	}
+
+	configs := make(map[int64]*model.ClusterConfigurationEntity)
+	for _, entity := range entities["clusterConfigs"] {
+		config := entity.(*model.ClusterConfigurationEntity)
+		configs[config.Version] = config
+		cluster, ok := clusters[config.ClusterVersion]
+		if !ok {
+			addViolation("cluster configuration %d references missing cluster version %d",
+				config.Version, config.ClusterVersion)
+		} else if cluster.RuntimeID != config.RuntimeID {
+			addViolation("cluster configuration %d of runtime '%s' references cluster version %d of runtime '%s'",
+				config.Version, config.RuntimeID, cluster.Version, cluster.RuntimeID)
+		}
+	}
+
+	statuses := make(map[int64]*model.ClusterStatusEntity)
+	for _, entity := range entities["clusterStatuses"] {
+		status := entity.(*model.ClusterStatusEntity)
+		statuses[status.ID] = status
+		config, ok := configs[status.ConfigVersion]
+		if !ok {
+			addViolation("cluster status %d references missing cluster configuration %d",
+				status.ID, status.ConfigVersion)
+		} else if config.RuntimeID != status.RuntimeID || config.ClusterVersion != status.ClusterVersion {
+			addViolation("cluster status %d (runtime '%s', cluster version %d) references cluster configuration %d "+
+				"of runtime '%s' and cluster version %d", status.ID, status.RuntimeID, status.ClusterVersion,
+				config.Version, config.RuntimeID, config.ClusterVersion)
+		}
+	}
+
+	keys := make(map[string]bool)
+	for _, entity := range entities["keys"] {
+		key := entity.(*model.KeyEntity)
+		keys[fmt.Sprintf("%s:%d", key.Key, key.Version)] = true
+	}
+	for _, entity := range entities["values"] {
+		value := entity.(*model.ValueEntity)
+		if !keys[fmt.Sprintf("%s:%d", value.Key, value.KeyVersion)] {
+			addViolation("value %d references missing version %d of key '%s'", value.Version, value.KeyVersion, value.Key)
+		}
+	}
+
+	reconciliations := make(map[string]bool)
+	for _, entity := range entities["reconciliations"] {
+		reconciliation := entity.(*model.ReconciliationEntity)
+		reconciliations[reconciliation.SchedulingID] = true
+		if _, ok := configs[reconciliation.ClusterConfig]; !ok {
+			addViolation("reconciliation '%s' references missing cluster configuration %d",
+				reconciliation.SchedulingID, reconciliation.ClusterConfig)
+		}
+		if _, ok := statuses[reconciliation.ClusterConfigStatus]; !ok {
+			addViolation("reconciliation '%s' references missing cluster status %d",
+				reconciliation.SchedulingID, reconciliation.ClusterConfigStatus)
+		}
+	}
+	for _, entity := range entities["operations"] {
+		operation := entity.(*model.OperationEntity)
+		if !reconciliations[operation.SchedulingID] {
+			addViolation("operation '%s' references missing reconciliation '%s'",
+				operation.CorrelationID, operation.SchedulingID)
+		}
+		if _, ok := configs[operation.ClusterConfig]; !ok {
+			addViolation("operation '%s' references missing cluster configuration %d",
+				operation.CorrelationID, operation.ClusterConfig)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &IntegrityError{Violations: violations}
+	}
+	return nil
+}
+
+//entityKey renders the values of the key fields of an entity, e.g. 'Version=3'
+func entityKey(section *section, entity db.DatabaseEntity) string {
+	fields := structs.New(entity)
+	values := make([]string, len(section.keys))
+	for i, key := range section.keys {
+		values[i] = fmt.Sprintf("%s=%v", key, fields.Field(key).Value())
+	}
+	return strings.Join(values, ",")
+}
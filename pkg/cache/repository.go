@@ -1,6 +1,9 @@
 package cache
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/kyma-incubator/reconciler/pkg/db"
 	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/kyma-incubator/reconciler/pkg/repository"
@@ -153,3 +156,47 @@ func (cr *Repository) InvalidateByID(id int64) error {
 	}
 	return cr.Transactional(dbOps)
 }
+
+//ConfigChanges returns the recorded configuration changes ordered by their creation
+func (cr *Repository) ConfigChanges() ([]*model.ConfigChangeEntity, error) {
+	q, err := db.NewQuery(cr.Conn, &model.ConfigChangeEntity{}, cr.Logger)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := q.Select().
+		OrderBy(map[string]string{"ID": "ASC"}).
+		GetMany()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*model.ConfigChangeEntity
+	for _, entity := range entities {
+		result = append(result, entity.(*model.ConfigChangeEntity))
+	}
+	return result, nil
+}
+
+//DeleteConfigChanges drops processed configuration changes
+func (cr *Repository) DeleteConfigChanges(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var buffer bytes.Buffer
+	for _, id := range ids {
+		if buffer.Len() > 0 {
+			buffer.WriteRune(',')
+		}
+		buffer.WriteString(fmt.Sprintf("%d", id))
+	}
+
+	q, err := db.NewQuery(cr.Conn, &model.ConfigChangeEntity{}, cr.Logger)
+	if err != nil {
+		return err
+	}
+	deleted, err := q.Delete().
+		WhereIn("ID", buffer.String()).
+		Exec()
+	cr.Logger.Debugf("Deleted %d configuration changes", deleted)
+	return err
+}
@@ -154,6 +154,26 @@ func TestRepository(t *testing.T) {
 		_, err = repo.GetByID(entry2.ID)
 		require.True(t, repository.IsNotFoundError(err)) //ensure entry2 no longer exist
 	})
+
+	t.Run("Get and delete configuration changes", func(t *testing.T) {
+		_, err := repo.Add(&model.CacheEntryEntity{
+			Label:     "cacheentry4",
+			RuntimeID: "bar",
+			Data:      "The fourth cached data goes here",
+		}, cacheDeps)
+		require.NoError(t, err)
+		require.NoError(t, repo.CacheDep.Invalidate().WithRuntimeID("bar").WithReconciliation().Exec(repo.Conn))
+
+		changes, err := repo.ConfigChanges()
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "bar", changes[0].RuntimeID)
+
+		require.NoError(t, repo.DeleteConfigChanges([]int64{changes[0].ID}))
+		changes, err = repo.ConfigChanges()
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
 }
 
 //nolint:unused
@@ -55,10 +55,11 @@ func (bm *bucketMerger) Get(value string) (interface{}, error) {
 	return valueEntity.Get()
 }
 
+//GetAll returns the merged values converted for the configuration of a component
 func (bm *bucketMerger) GetAll() (map[string]interface{}, error) {
 	result := make(map[string]interface{}, len(bm.result))
 	for key, value := range bm.result {
-		typedValue, err := value.Get()
+		typedValue, err := value.ConfigurationValue()
 		if err != nil {
 			return result, errors.Wrap(err,
 				fmt.Sprintf("potential data inconsistency detected: failed to get typed value of value-entity %s", value))
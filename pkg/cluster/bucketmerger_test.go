@@ -52,4 +52,23 @@ func TestBucketMerger(t *testing.T) {
 		require.Equal(t, map[string]interface{}{"key1": "xyz", "key2": int64(123), "key3": true}, values)
 	})
 
+	t.Run("Convert values for component configuration", func(t *testing.T) {
+		bm := &bucketMerger{}
+
+		err := bm.Add("bucket1", []*model.ValueEntity{
+			{Key: "key1", DataType: model.Duration, Value: "90s"},
+			{Key: "key2", DataType: model.JSON, Value: `{"a":[1,"b"]}`},
+			{Key: "key3", DataType: model.SecretRef, Value: "my-secret:password"},
+		})
+		require.NoError(t, err)
+
+		values, err := bm.GetAll()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"key1": "1m30s",
+			"key2": map[string]interface{}{"a": []interface{}{float64(1), "b"}},
+			"key3": map[string]interface{}{"name": "my-secret", "key": "password"},
+		}, values)
+	})
+
 }
@@ -0,0 +1,209 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+//variables which can be used in bucket layers (e.g. 'landscape-${landscape}')
+const (
+	BucketVarLandscape       = "landscape"
+	BucketVarGlobalAccountID = "globalAccountID"
+	BucketVarSubAccountID    = "subAccountID"
+	BucketVarRuntimeID       = "runtimeID"
+	BucketVarShootName       = "shootName"
+)
+
+//DefaultBucketLayers defines the default ordering of merged buckets: values of a bucket override the values
+//of the buckets before it
+var DefaultBucketLayers = []string{
+	model.DefaultBucket,
+	"landscape-${" + BucketVarLandscape + "}",
+	"${" + BucketVarGlobalAccountID + "}",
+	"${" + BucketVarRuntimeID + "}",
+}
+
+//BucketValuesCacheLabel is the label of the cache entries which track the bucket values merged for a cluster
+const BucketValuesCacheLabel = "bucket-values"
+
+var bucketVarPattern = regexp.MustCompile(`\$\{([A-Za-z]+)\}`)
+
+//BucketValuesRepository returns the latest values stored in a bucket
+type BucketValuesRepository interface {
+	ValuesByBucket(bucket string) ([]*model.ValueEntity, error)
+}
+
+//BucketValuesCache stores the merged values of a cluster and tracks the values they depend on
+type BucketValuesCache interface {
+	Add(cacheEntry *model.CacheEntryEntity, cacheDeps []*model.ValueEntity) (*model.CacheEntryEntity, error)
+}
+
+//BucketResolver resolves the buckets assigned to a cluster and merges their values
+type BucketResolver struct {
+	repo      BucketValuesRepository
+	cache     BucketValuesCache
+	cached    map[string]string //scheduling ID of the reconciliation whose values were cached last (per cluster)
+	layers    []string
+	landscape string
+	logger    *zap.SugaredLogger
+	mu        sync.Mutex
+}
+
+func NewBucketResolver(repo BucketValuesRepository, layers []string, landscape string, logger *zap.SugaredLogger) (*BucketResolver, error) {
+	if len(layers) == 0 {
+		layers = DefaultBucketLayers
+	}
+	for _, layer := range layers {
+		for _, match := range bucketVarPattern.FindAllStringSubmatch(layer, -1) {
+			if _, ok := bucketVars(&State{}, "")[match[1]]; !ok {
+				return nil, fmt.Errorf("bucket layer '%s' contains unknown variable '%s'", layer, match[0])
+			}
+		}
+	}
+	return &BucketResolver{
+		repo:      repo,
+		cached:    make(map[string]string),
+		layers:    layers,
+		landscape: landscape,
+		logger:    logger,
+	}, nil
+}
+
+//WithCache caches the merged values of each cluster once per reconciliation: a change of one of the merged
+//buckets invalidates the cache entry and causes a reconciliation of the cluster
+func (r *BucketResolver) WithCache(cache BucketValuesCache) *BucketResolver {
+	r.cache = cache
+	return r
+}
+
+//Buckets returns the names of the buckets of the cluster in their layering order. Layers which refer to a
+//variable the cluster has no value for, or which don't result in a valid bucket name, are skipped.
+func (r *BucketResolver) Buckets(state *State) []string {
+	vars := bucketVars(state, r.landscape)
+
+	var buckets []string
+	for _, layer := range r.layers {
+		unresolved := false
+		bucket := bucketVarPattern.ReplaceAllStringFunc(layer, func(variable string) string {
+			value := vars[bucketVarPattern.FindStringSubmatch(variable)[1]]
+			if value == "" {
+				unresolved = true
+			}
+			return value
+		})
+		if unresolved {
+			continue
+		}
+		bucket = strings.ToLower(bucket)
+		if err := model.ValidateBucketName(bucket); err != nil {
+			r.logger.Debugf("Skipping bucket layer '%s' of cluster '%s': %s", layer, state.Cluster.RuntimeID, err)
+			continue
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+//Values returns the merged values of the buckets of the cluster and the names of the merged buckets.
+//The scheduling ID identifies the reconciliation which consumes the values (empty if the values aren't
+//consumed by a reconciliation).
+func (r *BucketResolver) Values(schedulingID string, state *State) (map[string]interface{}, []string, error) {
+	buckets := r.Buckets(state)
+	merger := &bucketMerger{}
+	var deps []*model.ValueEntity
+	for _, bucket := range buckets {
+		values, err := r.repo.ValuesByBucket(bucket)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, fmt.Sprintf("failed to retrieve values of bucket '%s'", bucket))
+		}
+		if err := merger.Add(bucket, values); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, bucketDeps(bucket, values)...)
+	}
+	values, err := merger.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.cache != nil && schedulingID != "" && r.markCached(state.Cluster.RuntimeID, schedulingID) {
+		if err := r.cacheValues(state, values, deps); err != nil {
+			//values are still valid: the cluster won't be reconciled when one of its buckets changes
+			r.logger.Warnf("Failed to cache merged bucket values of cluster '%s': %s", state.Cluster.RuntimeID, err)
+			r.unmarkCached(state.Cluster.RuntimeID, schedulingID)
+		}
+	}
+	return values, buckets, nil
+}
+
+//markCached returns true if the values of the reconciliation weren't cached yet
+func (r *BucketResolver) markCached(runtimeID, schedulingID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cached[runtimeID] == schedulingID {
+		return false
+	}
+	r.cached[runtimeID] = schedulingID
+	return true
+}
+
+//unmarkCached allows further attempts to cache the values of the reconciliation
+func (r *BucketResolver) unmarkCached(runtimeID, schedulingID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cached[runtimeID] == schedulingID {
+		delete(r.cached, runtimeID)
+	}
+}
+
+func (r *BucketResolver) cacheValues(state *State, values map[string]interface{}, deps []*model.ValueEntity) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	_, err = r.cache.Add(&model.CacheEntryEntity{
+		Label:     BucketValuesCacheLabel,
+		RuntimeID: state.Cluster.RuntimeID,
+		Data:      string(data),
+	}, deps)
+	return err
+}
+
+//bucketDeps returns the cache dependencies of a bucket: its values and any value added to the bucket later
+func bucketDeps(bucket string, values []*model.ValueEntity) []*model.ValueEntity {
+	deps := []*model.ValueEntity{{Bucket: bucket, Key: model.CacheDependencyAnyKey}}
+	keys := make(map[string]interface{}, len(values))
+	for _, value := range values {
+		if _, ok := keys[value.Key]; ok {
+			continue
+		}
+		keys[value.Key] = nil
+		deps = append(deps, &model.ValueEntity{Bucket: bucket, Key: value.Key})
+	}
+	return deps
+}
+
+func bucketVars(state *State, landscape string) map[string]string {
+	vars := map[string]string{
+		BucketVarLandscape:       landscape,
+		BucketVarGlobalAccountID: "",
+		BucketVarSubAccountID:    "",
+		BucketVarRuntimeID:       "",
+		BucketVarShootName:       "",
+	}
+	if state.Cluster != nil {
+		vars[BucketVarRuntimeID] = state.Cluster.RuntimeID
+		if state.Cluster.Metadata != nil {
+			vars[BucketVarGlobalAccountID] = state.Cluster.Metadata.GlobalAccountID
+			vars[BucketVarSubAccountID] = state.Cluster.Metadata.SubAccountID
+			vars[BucketVarShootName] = state.Cluster.Metadata.ShootName
+		}
+	}
+	return vars
+}
@@ -0,0 +1,126 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+type testBucketRepository map[string][]*model.ValueEntity
+
+func (r testBucketRepository) ValuesByBucket(bucket string) ([]*model.ValueEntity, error) {
+	return r[bucket], nil
+}
+
+type testBucketValuesCache struct {
+	entries []*model.CacheEntryEntity
+	deps    [][]*model.ValueEntity
+}
+
+func (c *testBucketValuesCache) Add(cacheEntry *model.CacheEntryEntity, cacheDeps []*model.ValueEntity) (*model.CacheEntryEntity, error) {
+	c.entries = append(c.entries, cacheEntry)
+	c.deps = append(c.deps, cacheDeps)
+	return cacheEntry, nil
+}
+
+func TestBucketResolver(t *testing.T) {
+	state := &State{
+		Cluster: &model.ClusterEntity{
+			RuntimeID: "9d6e8b8f-2b07-4d32-b6c2-2d0a7d4c9a9e",
+			Metadata: &keb.Metadata{
+				GlobalAccountID: "3E64EBAE-38B5-46A0-B1ED-9CCEE153A0AE",
+			},
+		},
+	}
+
+	t.Run("Resolve buckets in default layering order", func(t *testing.T) {
+		resolver, err := NewBucketResolver(testBucketRepository{}, nil, "dev", logger.NewLogger(true))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"default",
+			"landscape-dev",
+			"3e64ebae-38b5-46a0-b1ed-9ccee153a0ae",
+			"9d6e8b8f-2b07-4d32-b6c2-2d0a7d4c9a9e",
+		}, resolver.Buckets(state))
+	})
+
+	t.Run("Skip layers with unresolved variables or invalid bucket names", func(t *testing.T) {
+		resolver, err := NewBucketResolver(testBucketRepository{}, []string{
+			"default",
+			"landscape-${landscape}",
+			"${subAccountID}",
+			"invalid_bucket",
+			"shoot-${runtimeID}",
+		}, "", logger.NewLogger(true))
+		require.NoError(t, err)
+		require.Equal(t, []string{"default", "shoot-9d6e8b8f-2b07-4d32-b6c2-2d0a7d4c9a9e"}, resolver.Buckets(state))
+	})
+
+	t.Run("Reject unknown variables", func(t *testing.T) {
+		_, err := NewBucketResolver(testBucketRepository{}, []string{"${customer}"}, "dev", logger.NewLogger(true))
+		require.Error(t, err)
+	})
+
+	t.Run("Merge values of buckets", func(t *testing.T) {
+		repo := testBucketRepository{
+			"default": {
+				{Key: "key1", DataType: model.String, Value: "default"},
+				{Key: "key2", DataType: model.Integer, Value: "1"},
+			},
+			"landscape-dev": {
+				{Key: "key1", DataType: model.String, Value: "dev"},
+			},
+			"9d6e8b8f-2b07-4d32-b6c2-2d0a7d4c9a9e": {
+				{Key: "key3", DataType: model.Boolean, Value: "true"},
+			},
+		}
+		resolver, err := NewBucketResolver(repo, nil, "dev", logger.NewLogger(true))
+		require.NoError(t, err)
+
+		values, buckets, err := resolver.Values("", state)
+		require.NoError(t, err)
+		require.Equal(t, resolver.Buckets(state), buckets)
+		require.Equal(t, map[string]interface{}{"key1": "dev", "key2": int64(1), "key3": true}, values)
+	})
+
+	t.Run("Cache merged values with their dependencies", func(t *testing.T) {
+		repo := testBucketRepository{
+			"default": {
+				{Key: "key1", DataType: model.String, Value: "default"},
+			},
+		}
+		cache := &testBucketValuesCache{}
+		resolver, err := NewBucketResolver(repo, nil, "dev", logger.NewLogger(true))
+		require.NoError(t, err)
+		resolver.WithCache(cache)
+
+		//values are cached once per reconciliation
+		for i := 0; i < 2; i++ {
+			_, _, err = resolver.Values("scheduling1", state)
+			require.NoError(t, err)
+		}
+		require.Len(t, cache.entries, 1)
+		require.Equal(t, BucketValuesCacheLabel, cache.entries[0].Label)
+		require.Equal(t, state.Cluster.RuntimeID, cache.entries[0].RuntimeID)
+		require.JSONEq(t, `{"key1":"default"}`, cache.entries[0].Data)
+		require.ElementsMatch(t, []*model.ValueEntity{
+			{Bucket: "default", Key: model.CacheDependencyAnyKey},
+			{Bucket: "default", Key: "key1"},
+			{Bucket: "landscape-dev", Key: model.CacheDependencyAnyKey},
+			{Bucket: "3e64ebae-38b5-46a0-b1ed-9ccee153a0ae", Key: model.CacheDependencyAnyKey},
+			{Bucket: "9d6e8b8f-2b07-4d32-b6c2-2d0a7d4c9a9e", Key: model.CacheDependencyAnyKey},
+		}, cache.deps[0])
+
+		_, _, err = resolver.Values("scheduling2", state)
+		require.NoError(t, err)
+		require.Len(t, cache.entries, 2)
+
+		//values which aren't consumed by a reconciliation aren't cached
+		_, _, err = resolver.Values("", state)
+		require.NoError(t, err)
+		require.Len(t, cache.entries, 2)
+	})
+}
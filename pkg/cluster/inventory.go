@@ -14,9 +14,12 @@ import (
 )
 
 type Inventory interface {
-	CreateOrUpdate(contractVersion int64, cluster *keb.Cluster) (*State, error)
+	//CreateOrUpdate, UpdateLatestStatus and MarkForDeletion fail with a VersionConflictError if an expected version
+	//is given and the latest state of the cluster has a different version
+	CreateOrUpdate(contractVersion int64, cluster *keb.Cluster, expected *Version) (*State, error)
 	UpdateStatus(State *State, status model.Status) (*State, error)
-	MarkForDeletion(runtimeID string) (*State, error)
+	UpdateLatestStatus(runtimeID string, status model.Status, expected *Version) (*State, error)
+	MarkForDeletion(runtimeID string, expected *Version) (*State, error)
 	Delete(runtimeID string) error
 	Get(runtimeID string, configVersion int64) (*State, error)
 	GetLatest(runtimeID string) (*State, error)
@@ -92,7 +95,7 @@ func statusInSlice(status model.Status, statusList []model.Status) bool {
 	return false
 }
 
-func (i *DefaultInventory) CreateOrUpdate(contractVersion int64, cluster *keb.Cluster) (*State, error) {
+func (i *DefaultInventory) CreateOrUpdate(contractVersion int64, cluster *keb.Cluster, expected *Version) (*State, error) {
 	if len(cluster.KymaConfig.Components) == 0 {
 		return nil, fmt.Errorf("error creating cluster with RuntimeID: %s, component list is empty", cluster.RuntimeID)
 	}
@@ -103,6 +106,9 @@ func (i *DefaultInventory) CreateOrUpdate(contractVersion int64, cluster *keb.Cl
 			return nil, err
 		}
 		iTx = tmpiTx.(*DefaultInventory)
+		if err := iTx.checkVersion(cluster.RuntimeID, expected); err != nil {
+			return nil, err
+		}
 		clusterEntity, err := iTx.createCluster(contractVersion, cluster)
 		if err != nil {
 			return nil, err
@@ -264,12 +270,95 @@ func (i *DefaultInventory) UpdateStatus(state *State, status model.Status) (*Sta
 	return state, nil
 }
 
-func (i *DefaultInventory) MarkForDeletion(runtimeID string) (*State, error) {
-	clusterState, err := i.GetLatest(runtimeID)
+//UpdateLatestStatus updates the status of the latest state of the cluster. The expected version is verified in the
+//same transaction.
+func (i *DefaultInventory) UpdateLatestStatus(runtimeID string, status model.Status, expected *Version) (*State, error) {
+	dbOps := func(tx *db.TxConnection) (interface{}, error) {
+		tmpiTx, err := i.WithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		iTx := tmpiTx.(*DefaultInventory)
+		if err := iTx.checkVersion(runtimeID, expected); err != nil {
+			return nil, err
+		}
+		clusterState, err := iTx.GetLatest(runtimeID)
+		if err != nil {
+			return nil, err
+		}
+		return iTx.UpdateStatus(clusterState, status)
+	}
+	state, err := db.TransactionResult(i.Conn, dbOps, i.Logger)
 	if err != nil {
 		return nil, err
 	}
-	return i.UpdateStatus(clusterState, model.ClusterStatusDeletePending)
+	return state.(*State), nil
+}
+
+func (i *DefaultInventory) MarkForDeletion(runtimeID string, expected *Version) (*State, error) {
+	dbOps := func(tx *db.TxConnection) (interface{}, error) {
+		tmpiTx, err := i.WithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		iTx := tmpiTx.(*DefaultInventory)
+		if err := iTx.checkVersion(runtimeID, expected); err != nil {
+			return nil, err
+		}
+		clusterState, err := iTx.GetLatest(runtimeID)
+		if err != nil {
+			return nil, err
+		}
+		return iTx.UpdateStatus(clusterState, model.ClusterStatusDeletePending)
+	}
+	state, err := db.TransactionResult(i.Conn, dbOps, i.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return state.(*State), nil
+}
+
+//checkVersion verifies that the latest state of a cluster has the expected version (nothing is checked if no
+//version is expected). It has to be called within a transaction: the cluster entities are locked until the
+//transaction ends, which prevents concurrent updates between the check and the update.
+func (i *DefaultInventory) checkVersion(runtimeID string, expected *Version) error {
+	if expected == nil {
+		return nil
+	}
+	if err := i.lockCluster(runtimeID); err != nil {
+		return err
+	}
+	clusterState, err := i.GetLatest(runtimeID)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return &VersionConflictError{RuntimeID: runtimeID, Expected: expected}
+		}
+		return err
+	}
+	if current := clusterState.versionOf(expected); *current != *expected {
+		return &VersionConflictError{RuntimeID: runtimeID, Expected: expected, Current: current}
+	}
+	return nil
+}
+
+//lockCluster locks the cluster entities of a runtime until the transaction ends. Only required for Postgres:
+//SQLite allows just one write transaction at the same time.
+func (i *DefaultInventory) lockCluster(runtimeID string) error {
+	if i.Conn.Type() != db.Postgres {
+		return nil
+	}
+	clusterEntity := &model.ClusterEntity{}
+	colHandler, err := db.NewColumnHandler(clusterEntity, i.Conn, i.Logger)
+	if err != nil {
+		return err
+	}
+	runtimeIDColName, err := colHandler.ColumnName("RuntimeID")
+	if err != nil {
+		return err
+	}
+	_, err = i.Conn.Exec(fmt.Sprintf("SELECT %s FROM %s WHERE %s=$1 FOR UPDATE",
+		runtimeIDColName, clusterEntity.Table(), runtimeIDColName), runtimeID)
+	return err
 }
 
 func (i *DefaultInventory) Delete(runtimeID string) error {
@@ -40,12 +40,12 @@ func TestInventory(t *testing.T) {
 
 	t.Run("Create expectedCluster", func(t *testing.T) {
 		//create cluster1
-		clusterState, err := inventory.CreateOrUpdate(1, expectedCluster)
+		clusterState, err := inventory.CreateOrUpdate(1, expectedCluster, nil)
 		require.NoError(t, err)
 		compareState(t, clusterState, expectedCluster)
 
 		//create same entry again (no new version should be created)
-		clusterStateNew, err := inventory.CreateOrUpdate(1, expectedCluster)
+		clusterStateNew, err := inventory.CreateOrUpdate(1, expectedCluster, nil)
 		require.NoError(t, err)
 		require.Equal(t, clusterState.Cluster.Version, clusterStateNew.Cluster.Version)
 		require.Equal(t, clusterState.Configuration.Version, clusterStateNew.Configuration.Version)
@@ -57,7 +57,7 @@ func TestInventory(t *testing.T) {
 		//update cluster1 multiple times (will create multiple versions of it)
 		for i := uint64(2); i <= maxVersion; i++ { //"i" reflects cluster version
 			updatedCluster := test.NewClusterFromExisting(*expectedCluster, i, false)
-			clusterState, err := inventory.CreateOrUpdate(1, updatedCluster)
+			clusterState, err := inventory.CreateOrUpdate(1, updatedCluster, nil)
 			require.NoError(t, err)
 			compareState(t, clusterState, updatedCluster)
 		}
@@ -105,7 +105,7 @@ func TestInventory(t *testing.T) {
 		require.Len(t, clustersOld, 1)
 
 		//add a new cluster
-		newCluster, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "2", 1, false, test.Production))
+		newCluster, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "2", 1, false, test.Production), nil)
 		require.NoError(t, err)
 
 		//check that both clusters are now returned
@@ -142,7 +142,7 @@ func TestInventoryForClusterStatues(t *testing.T) {
 		// //create for each cluster-status a new cluster
 		for idx, clusterStatus := range clusterStatuses {
 			newCluster := test.NewCluster(t, strconv.Itoa(idx+1), 1, false, test.Production)
-			clusterState, err := inventory.CreateOrUpdate(1, newCluster)
+			clusterState, err := inventory.CreateOrUpdate(1, newCluster, nil)
 			require.NoError(t, err)
 			expectedClusters = append(expectedClusters, newCluster)
 			//add another status to verify that SQL query works correctly
@@ -184,7 +184,7 @@ func TestInventoryForClusterStatues(t *testing.T) {
 	})
 	t.Run("Get status changes", func(t *testing.T) {
 		newCluster := test.NewCluster(t, "1", 1, false, test.Production)
-		clusterState, err := inventory.CreateOrUpdate(1, newCluster)
+		clusterState, err := inventory.CreateOrUpdate(1, newCluster, nil)
 		require.NoError(t, err)
 		// //create for each cluster-status a new cluster
 		for _, clusterStatus := range clusterStatuses {
@@ -214,7 +214,7 @@ func TestInventoryForReconcile(t *testing.T) {
 	t.Run("Get clusters to reconcile", func(t *testing.T) {
 		//create cluster1, clusterVersion1, clusterConfigVersion1-1, status: Ready
 		cluster1v1v1 := test.NewCluster(t, "1", 1, false, test.Production)
-		clusterState1v1v1a, err := inventory.CreateOrUpdate(1, cluster1v1v1)
+		clusterState1v1v1a, err := inventory.CreateOrUpdate(1, cluster1v1v1, nil)
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStatusReconcilePending, clusterState1v1v1a.Status.Status)
 		clusterState1v1v1b, err := inventory.UpdateStatus(clusterState1v1v1a, model.ClusterStatusReady)
@@ -223,19 +223,19 @@ func TestInventoryForReconcile(t *testing.T) {
 
 		//create cluster1, clusterVersion2, clusterConfigVersion2-2, status: ReconcilePending
 		cluster1v2v2 := test.NewClusterFromExisting(*cluster1v1v1, 2, true)
-		expectedClusterState1v2v2, err := inventory.CreateOrUpdate(1, cluster1v2v2) //<- EXPECTED STATE
+		expectedClusterState1v2v2, err := inventory.CreateOrUpdate(1, cluster1v2v2, nil) //<- EXPECTED STATE
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStatusReconcilePending, expectedClusterState1v2v2.Status.Status)
 
 		//create cluster2, clusterVersion1, clusterConfigVersion1-1, status: ReconcilePending
 		cluster2v1v1 := test.NewCluster(t, "2", 1, false, test.Production)
-		clusterState2v1v1, err := inventory.CreateOrUpdate(1, cluster2v1v1)
+		clusterState2v1v1, err := inventory.CreateOrUpdate(1, cluster2v1v1, nil)
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStatusReconcilePending, clusterState2v1v1.Status.Status)
 
 		//create cluster2, clusterVersion1, clusterConfigVersion1-2, status: Error
 		cluster2v1v2 := test.NewClusterFromExisting(*cluster2v1v1, 1, true)
-		clusterState2v1v2a, err := inventory.CreateOrUpdate(1, cluster2v1v2)
+		clusterState2v1v2a, err := inventory.CreateOrUpdate(1, cluster2v1v2, nil)
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStatusReconcilePending, clusterState2v1v2a.Status.Status)
 		clusterState2v1v2b, err := inventory.UpdateStatus(clusterState2v1v2a, model.ClusterStatusReconcileError)
@@ -243,7 +243,7 @@ func TestInventoryForReconcile(t *testing.T) {
 		require.Equal(t, model.ClusterStatusReconcileError, clusterState2v1v2b.Status.Status)
 
 		//delete cluster2, status: DeletePending -> Deleting
-		cluster2State2a, err := inventory.MarkForDeletion(cluster2v1v2.RuntimeID)
+		cluster2State2a, err := inventory.MarkForDeletion(cluster2v1v2.RuntimeID, nil)
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStatusDeletePending, cluster2State2a.Status.Status)
 		expectedCluster2State2b, err := inventory.UpdateStatus(cluster2State2a, model.ClusterStatusDeleting) //<- EXPECTED STATE
@@ -252,7 +252,7 @@ func TestInventoryForReconcile(t *testing.T) {
 
 		//create cluster3, clusterVersion1, clusterConfigVersion1-1, status: Error
 		cluster3v1v1 := test.NewCluster(t, "3", 1, false, test.Production)
-		clusterState3v1v1a, err := inventory.CreateOrUpdate(1, cluster3v1v1)
+		clusterState3v1v1a, err := inventory.CreateOrUpdate(1, cluster3v1v1, nil)
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStatusReconcilePending, clusterState3v1v1a.Status.Status)
 		clusterState3v1v1b, err := inventory.UpdateStatus(clusterState3v1v1a, model.ClusterStatusReady)
@@ -264,26 +264,26 @@ func TestInventoryForReconcile(t *testing.T) {
 
 		//create cluster4, clusterVersion1, clusterConfigVersion1-1, status: ReconcilePending
 		cluster4v1v1 := test.NewCluster(t, "4", 1, false, test.Production)
-		_, err = inventory.CreateOrUpdate(1, cluster4v1v1)
+		_, err = inventory.CreateOrUpdate(1, cluster4v1v1, nil)
 		require.NoError(t, err)
 
 		//create cluster4, clusterVersion1, clusterConfigVersion1-2, status: Ready
 		cluster4v1v2 := test.NewClusterFromExisting(*cluster4v1v1, 1, true)
-		clusterState4v1v2, err := inventory.CreateOrUpdate(1, cluster4v1v2)
+		clusterState4v1v2, err := inventory.CreateOrUpdate(1, cluster4v1v2, nil)
 		require.NoError(t, err)
 		_, err = inventory.UpdateStatus(clusterState4v1v2, model.ClusterStatusReady)
 		require.NoError(t, err)
 
 		//create cluster4, clusterVersion2, clusterConfigVersion1-1, status: ReconcilePending
 		cluster4v2v1 := test.NewClusterFromExisting(*cluster4v1v1, 2, false)
-		clusterState4v2v1, err := inventory.CreateOrUpdate(1, cluster4v2v1)
+		clusterState4v2v1, err := inventory.CreateOrUpdate(1, cluster4v2v1, nil)
 		require.NoError(t, err)
 		_, err = inventory.UpdateStatus(clusterState4v2v1, model.ClusterStatusReady)
 		require.NoError(t, err)
 
 		//create cluster4, clusterVersion2, clusterConfigVersion1-2, status: Ready
 		cluster4v2v2 := test.NewClusterFromExisting(*cluster4v2v1, 2, true)
-		clusterState4v2v2a, err := inventory.CreateOrUpdate(1, cluster4v2v2)
+		clusterState4v2v2a, err := inventory.CreateOrUpdate(1, cluster4v2v2, nil)
 		require.NoError(t, err)
 		expectedClusterState4v2v2b, err := inventory.UpdateStatus(clusterState4v2v2a, model.ClusterStatusReady) //<-EXPECTED STATE
 		require.NoError(t, err)
@@ -330,7 +330,7 @@ func TestCountRetries(t *testing.T) {
 	}()
 
 	t.Run("When there are retry before status ready, expect to be skipped and not take into count.", func(t *testing.T) {
-		clusterState, err := inventory.CreateOrUpdate(1, expectedCluster1)
+		clusterState, err := inventory.CreateOrUpdate(1, expectedCluster1, nil)
 		require.NoError(t, err)
 
 		const errorStatus = model.ClusterStatusReconcileErrorRetryable
@@ -351,7 +351,7 @@ func TestCountRetries(t *testing.T) {
 	t.Run("When there are retry after status ready, expect to be counted.", func(t *testing.T) {
 		expectedErrRetryable := 50
 
-		clusterState, err := inventory.CreateOrUpdate(2, expectedCluster2)
+		clusterState, err := inventory.CreateOrUpdate(2, expectedCluster2, nil)
 		require.NoError(t, err)
 
 		const errorStatusTobeCounted = model.ClusterStatusReconcileErrorRetryable
@@ -374,7 +374,7 @@ func TestCountRetries(t *testing.T) {
 	t.Run("When there are not expected error status, expect them to be skipped and only count expected status", func(t *testing.T) {
 		expectedErrRetryable := 50
 
-		clusterState, err := inventory.CreateOrUpdate(3, expectedCluster3)
+		clusterState, err := inventory.CreateOrUpdate(3, expectedCluster3, nil)
 		require.NoError(t, err)
 
 		const errorStatusTobeCounted = model.ClusterStatusReconcileErrorRetryable
@@ -416,9 +416,9 @@ func TestTransaction(t *testing.T) {
 			require.NoError(t, err)
 
 			//create two clusters
-			clusterState, err = inventory.CreateOrUpdate(1, test.NewCluster(t, "neverExist1", 1, false, test.OneComponentDummy))
+			clusterState, err = inventory.CreateOrUpdate(1, test.NewCluster(t, "neverExist1", 1, false, test.OneComponentDummy), nil)
 			require.NoError(t, err)
-			clusterState2, err = inventory.CreateOrUpdate(1, test.NewCluster(t, "neverExist2", 1, false, test.OneComponentDummy))
+			clusterState2, err = inventory.CreateOrUpdate(1, test.NewCluster(t, "neverExist2", 1, false, test.OneComponentDummy), nil)
 			require.NoError(t, err)
 
 			//check if clusters are created
@@ -454,6 +454,74 @@ func listStatuses(states []*State) []model.Status {
 	return result
 }
 
+func TestInventoryVersionCheck(t *testing.T) {
+	inventory, err := NewInventory(db.NewTestSqliteConnection(t), true, MetricsCollectorMock{})
+	require.NoError(t, err)
+
+	cluster := test.NewCluster(t, "1", 1, false, test.OneComponentDummy)
+
+	t.Run("Create cluster with expected version fails if cluster doesn't exist", func(t *testing.T) {
+		_, err := inventory.CreateOrUpdate(1, cluster, &Version{ClusterVersion: 1, ConfigVersion: 1})
+		require.True(t, IsVersionConflictError(err))
+		require.Nil(t, err.(*VersionConflictError).Current)
+	})
+
+	t.Run("Update cluster with expected version", func(t *testing.T) {
+		clusterState, err := inventory.CreateOrUpdate(1, cluster, nil)
+		require.NoError(t, err)
+
+		//update based on the latest version succeeds
+		clusterStateNew, err := inventory.CreateOrUpdate(1, test.NewClusterFromExisting(*cluster, 1, true),
+			clusterState.Version())
+		require.NoError(t, err)
+		require.NotEqual(t, clusterState.Version(), clusterStateNew.Version())
+
+		//update based on the outdated version fails and returns the latest version
+		_, err = inventory.CreateOrUpdate(1, test.NewClusterFromExisting(*cluster, 2, false), clusterState.Version())
+		require.True(t, IsVersionConflictError(err))
+		require.Equal(t, clusterStateNew.Version(), err.(*VersionConflictError).Current)
+
+		//failed update didn't create a new version
+		clusterStateLatest, err := inventory.GetLatest(cluster.RuntimeID)
+		require.NoError(t, err)
+		require.Equal(t, clusterStateNew.Version(), clusterStateLatest.Version())
+	})
+
+	t.Run("Update status with expected version", func(t *testing.T) {
+		clusterState, err := inventory.GetLatest(cluster.RuntimeID)
+		require.NoError(t, err)
+		statusVersion := clusterState.StatusVersion()
+
+		//status updates change the version including the status
+		clusterStateNew, err := inventory.UpdateLatestStatus(cluster.RuntimeID, model.ClusterStatusReconciling, statusVersion)
+		require.NoError(t, err)
+		require.Equal(t, clusterState.Version(), clusterStateNew.Version())
+		require.NotEqual(t, statusVersion, clusterStateNew.StatusVersion())
+
+		//update based on the outdated status fails and returns the latest version including the status
+		_, err = inventory.UpdateLatestStatus(cluster.RuntimeID, model.ClusterStatusReady, statusVersion)
+		require.True(t, IsVersionConflictError(err))
+		require.Equal(t, clusterStateNew.StatusVersion(), err.(*VersionConflictError).Current)
+
+		//versions without status ignore status updates
+		_, err = inventory.UpdateLatestStatus(cluster.RuntimeID, model.ClusterStatusReady, clusterState.Version())
+		require.NoError(t, err)
+	})
+
+	t.Run("Mark cluster for deletion with expected version", func(t *testing.T) {
+		clusterState, err := inventory.GetLatest(cluster.RuntimeID)
+		require.NoError(t, err)
+
+		_, err = inventory.MarkForDeletion(cluster.RuntimeID, &Version{ClusterVersion: 99, ConfigVersion: 99})
+		require.True(t, IsVersionConflictError(err))
+		require.Equal(t, clusterState.Version(), err.(*VersionConflictError).Current)
+
+		clusterState, err = inventory.MarkForDeletion(cluster.RuntimeID, clusterState.Version())
+		require.NoError(t, err)
+		require.Equal(t, model.ClusterStatusDeletePending, clusterState.Status.Status)
+	})
+}
+
 func listStatusesForStatusChanges(states []*StatusChange) []model.Status {
 	var result []model.Status
 	for _, state := range states {
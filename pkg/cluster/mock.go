@@ -31,7 +31,7 @@ func (i *MockInventory) WithTx(_ *db.TxConnection) (Inventory, error) {
 	return i, nil
 }
 
-func (i *MockInventory) CreateOrUpdate(_ int64, _ *keb.Cluster) (*State, error) {
+func (i *MockInventory) CreateOrUpdate(_ int64, _ *keb.Cluster, _ *Version) (*State, error) {
 	return i.CreateOrUpdateResult, nil
 }
 
@@ -39,7 +39,11 @@ func (i *MockInventory) UpdateStatus(_ *State, _ model.Status) (*State, error) {
 	return i.UpdateStatusResult, nil
 }
 
-func (i *MockInventory) MarkForDeletion(_ string) (*State, error) {
+func (i *MockInventory) UpdateLatestStatus(_ string, _ model.Status, _ *Version) (*State, error) {
+	return i.UpdateStatusResult, nil
+}
+
+func (i *MockInventory) MarkForDeletion(_ string, _ *Version) (*State, error) {
 	return i.MarkForDeletionResult, nil
 }
 
@@ -2,10 +2,15 @@ package cluster
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/kyma-incubator/reconciler/pkg/model"
 )
 
+var etagPattern = regexp.MustCompile(`^"(\d+)-(\d+)(?:-(\d+))?"$`)
+
 type State struct {
 	Cluster       *model.ClusterEntity
 	Configuration *model.ClusterConfigurationEntity
@@ -16,3 +21,92 @@ func (s *State) String() string {
 	return fmt.Sprintf("State [RuntimeID=%s,ClusterVersion=%d,ConfigVersion=%d,Status=%s]",
 		s.Cluster.RuntimeID, s.Cluster.Version, s.Configuration.Version, s.Status.Status)
 }
+
+//Version returns the cluster and configuration version of the state
+func (s *State) Version() *Version {
+	return &Version{
+		ClusterVersion: s.Cluster.Version,
+		ConfigVersion:  s.Configuration.Version,
+	}
+}
+
+//StatusVersion returns the version of the state including its status: it changes also with each status update
+func (s *State) StatusVersion() *Version {
+	version := s.Version()
+	version.StatusID = s.Status.ID
+	return version
+}
+
+//versionOf returns the version of the state in the same format as the expected version (including the status ID
+//only if it's expected)
+func (s *State) versionOf(expected *Version) *Version {
+	if expected.StatusID > 0 {
+		return s.StatusVersion()
+	}
+	return s.Version()
+}
+
+//Version identifies a cluster state by its cluster and configuration version: each update of a cluster
+//changes at least one of them. Status updates are only reflected if the version includes the status ID.
+type Version struct {
+	ClusterVersion int64
+	ConfigVersion  int64
+	StatusID       int64 //0 if the status isn't part of the version
+}
+
+func (v *Version) String() string {
+	if v.StatusID > 0 {
+		return fmt.Sprintf("%d-%d-%d", v.ClusterVersion, v.ConfigVersion, v.StatusID)
+	}
+	return fmt.Sprintf("%d-%d", v.ClusterVersion, v.ConfigVersion)
+}
+
+//ETag renders the version as HTTP entity tag, e.g. '"3-7"' or '"3-7-12"' if the status is included
+func (v *Version) ETag() string {
+	return fmt.Sprintf(`"%s"`, v)
+}
+
+//ParseETag converts an HTTP entity tag created by Version.ETag() into a version
+func ParseETag(etag string) (*Version, error) {
+	match := etagPattern.FindStringSubmatch(strings.TrimSpace(etag))
+	if match == nil {
+		return nil, fmt.Errorf("entity tag '%s' is invalid: expected format is "+
+			"'\"<cluster version>-<config version>[-<status ID>]\"'", etag)
+	}
+	var numbers [3]int64
+	for idx, value := range match[1:] {
+		if value == "" {
+			continue
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		numbers[idx] = number
+	}
+	return &Version{
+		ClusterVersion: numbers[0],
+		ConfigVersion:  numbers[1],
+		StatusID:       numbers[2],
+	}, nil
+}
+
+//VersionConflictError is returned if a cluster was changed since the version an update is based on
+type VersionConflictError struct {
+	RuntimeID string
+	Expected  *Version
+	Current   *Version //nil if the cluster doesn't exist
+}
+
+func (e *VersionConflictError) Error() string {
+	if e.Current == nil {
+		return fmt.Sprintf("cluster '%s' was expected in version %s but doesn't exist", e.RuntimeID, e.Expected)
+	}
+	return fmt.Sprintf("cluster '%s' was expected in version %s but current version is %s",
+		e.RuntimeID, e.Expected, e.Current)
+}
+
+func IsVersionConflictError(err error) bool {
+	_, ok := err.(*VersionConflictError)
+	return ok
+}
@@ -0,0 +1,31 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	t.Run("Render and parse entity tag", func(t *testing.T) {
+		version := &Version{ClusterVersion: 3, ConfigVersion: 7}
+		require.Equal(t, `"3-7"`, version.ETag())
+
+		parsed, err := ParseETag(version.ETag())
+		require.NoError(t, err)
+		require.Equal(t, version, parsed)
+
+		version.StatusID = 12
+		require.Equal(t, `"3-7-12"`, version.ETag())
+		parsed, err = ParseETag(version.ETag())
+		require.NoError(t, err)
+		require.Equal(t, version, parsed)
+	})
+
+	t.Run("Reject invalid entity tags", func(t *testing.T) {
+		for _, etag := range []string{"", "3-7", `"3"`, `"a-7"`, `W/"3-7"`, `"3-7-1-1"`, `"99999999999999999999-1"`} {
+			_, err := ParseETag(etag)
+			require.Error(t, err, etag)
+		}
+	})
+}
@@ -89,6 +89,17 @@ func NewColumnHandler(entity DatabaseEntity, conn Connection, logger *zap.Sugare
 	return colHdlr, nil
 }
 
+//EncryptedFieldNames returns the names of the entity fields which are stored encrypted
+func EncryptedFieldNames(entity DatabaseEntity) []string {
+	var result []string
+	for _, field := range structs.Fields(entity) {
+		if hasTag(field, dbTagEncrypt) {
+			result = append(result, field.Name())
+		}
+	}
+	return result
+}
+
 func hasTag(field *structs.Field, tag string) bool {
 	tags := strings.Split(field.Tag(dbTag), ",")
 	for _, t := range tags {
@@ -169,6 +180,17 @@ func (ch *ColumnHandler) ColumnNamesCsv(onlyWriteable bool) string {
 	return buffer.String()
 }
 
+//EncryptedColumnNames returns the names of the encrypted columns
+func (ch *ColumnHandler) EncryptedColumnNames() []string {
+	var result []string
+	for _, col := range ch.columns {
+		if col.encrypt {
+			result = append(result, col.name)
+		}
+	}
+	return result
+}
+
 func (ch *ColumnHandler) ColumnValues(onlyWriteable bool) ([]interface{}, error) {
 	var result []interface{}
 	for _, col := range ch.columns {
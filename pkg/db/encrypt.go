@@ -17,12 +17,15 @@ import (
 const keyIDLength = 15
 const KeyLength = 32
 
+//Encryptor encrypts data with the active key and decrypts data with any key of its keyring. The keyring contains
+//the active key and old keys, which allows to decrypt data encrypted before the active key was rotated.
 type Encryptor struct {
-	keyID [16]byte
-	aead  cipher.AEAD
+	keyID   [16]byte
+	aead    cipher.AEAD
+	keyring map[string]cipher.AEAD //AEAD per key ID
 }
 
-func NewEncryptor(key string) (*Encryptor, error) {
+func NewEncryptor(key string, oldKeys ...string) (*Encryptor, error) {
 	if len(key) == 0 {
 		return nil, fmt.Errorf("cannot create new encryptor instance because encryption key was an empty string")
 	}
@@ -32,10 +35,22 @@ func NewEncryptor(key string) (*Encryptor, error) {
 		return nil, err
 	}
 
-	return &Encryptor{
-		aead:  aead,
-		keyID: md5.Sum([]byte(key)), //nolint: gosec //using MD5 just for generating a checksum of the key
-	}, nil
+	encryptor := &Encryptor{
+		aead:    aead,
+		keyID:   md5.Sum([]byte(key)), //nolint: gosec //using MD5 just for generating a checksum of the key
+		keyring: make(map[string]cipher.AEAD, len(oldKeys)+1),
+	}
+	encryptor.keyring[encryptor.KeyID()] = aead
+
+	for _, oldKey := range oldKeys {
+		oldAEAD, err := newAEAD(oldKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid decryption key")
+		}
+		encryptor.keyring[keyID(oldKey)] = oldAEAD
+	}
+
+	return encryptor, nil
 }
 
 //NewEncryptionKey generates a random 32 byte key for AES-256
@@ -62,6 +77,10 @@ func (e *Encryptor) KeyID() string {
 	return fmt.Sprintf("%x", e.keyID)[:keyIDLength]
 }
 
+func keyID(key string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(key)))[:keyIDLength] //nolint: gosec //using MD5 just for generating a checksum of the key
+}
+
 func (e *Encryptor) Encrypt(data string) (string, error) {
 	nonce := make([]byte, e.aead.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
@@ -72,19 +91,23 @@ func (e *Encryptor) Encrypt(data string) (string, error) {
 }
 
 func (e *Encryptor) Decrypt(encData string) (string, error) {
-	if !e.Decryptable(encData) {
+	aead, ok := e.keyringAEAD(encData)
+	if !ok {
 		return "", fmt.Errorf("data cannot be decrypted because encryption key does not match")
 	}
 
-	enc, err := hex.DecodeString(strings.TrimPrefix(encData, e.KeyID())) //remove keyID from encrypted data
+	enc, err := hex.DecodeString(encData[keyIDLength:]) //remove keyID from encrypted data
 	if err != nil {
 		return "", fmt.Errorf("failed to decode HEX string to bytes")
 	}
 
-	nonceSize := e.aead.NonceSize()
+	nonceSize := aead.NonceSize()
+	if len(enc) < nonceSize {
+		return "", fmt.Errorf("encrypted data is too short")
+	}
 	nonce, cipherText := enc[:nonceSize], enc[nonceSize:]
 
-	data, err := e.aead.Open(nil, nonce, cipherText, nil)
+	data, err := aead.Open(nil, nonce, cipherText, nil)
 	if err != nil {
 		return "", err
 	}
@@ -94,9 +117,25 @@ func (e *Encryptor) Decrypt(encData string) (string, error) {
 
 //Decryptable verifies whether the encrypted data can be decrypted by this Encryptor instance
 func (e *Encryptor) Decryptable(encData string) bool {
+	_, ok := e.keyringAEAD(encData)
+	return ok
+}
+
+//EncryptedWithActiveKey verifies whether the data was encrypted with the active key (data encrypted with an
+//old key has to be re-encrypted before the old key can be removed from the keyring)
+func (e *Encryptor) EncryptedWithActiveKey(encData string) bool {
 	return strings.HasPrefix(encData, e.KeyID()) //KeyID prefix of encrypted data has to match with current KeyID
 }
 
+//keyringAEAD returns the AEAD of the key the data was encrypted with
+func (e *Encryptor) keyringAEAD(encData string) (cipher.AEAD, bool) {
+	if len(encData) < keyIDLength {
+		return nil, false
+	}
+	aead, ok := e.keyring[encData[:keyIDLength]]
+	return aead, ok
+}
+
 func readKeyFile(encKeyFile string) (string, error) {
 	if !file.Exists(encKeyFile) {
 		return "", fmt.Errorf("encryption key file '%s' not found", encKeyFile)
@@ -105,12 +144,20 @@ func readKeyFile(encKeyFile string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, fmt.Sprintf("failed to read encryption key file '%s'", encKeyFile))
 	}
-	length, err := hex.Decode(make([]byte, KeyLength), encKeyBytes)
+	if err := validateKey(string(encKeyBytes)); err != nil {
+		return "", err
+	}
+	return string(encKeyBytes), nil
+}
+
+//validateKey verifies that the key is a HEX string of an AES-256 key
+func validateKey(key string) error {
+	keyBytes, err := hex.DecodeString(key)
 	if err != nil {
-		return "", errors.Wrap(err, "encryption key is not a valid HEX string")
+		return errors.Wrap(err, "encryption key is not a valid HEX string")
 	}
-	if length != KeyLength {
-		return "", fmt.Errorf("encryption key has to be %d bytes long (was %d)", KeyLength, length)
+	if len(keyBytes) != KeyLength {
+		return fmt.Errorf("encryption key has to be %d bytes long (was %d)", KeyLength, len(keyBytes))
 	}
-	return string(encKeyBytes), nil
+	return nil
 }
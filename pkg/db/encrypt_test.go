@@ -72,6 +72,39 @@ func TestEncryptor(t *testing.T) {
 		require.Equal(t, decData1, decData2)
 	})
 
+	t.Run("Decrypt with old keys", func(t *testing.T) {
+		oldKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		oldEnc, err := NewEncryptor(oldKey)
+		require.NoError(t, err)
+		encData, err := oldEnc.Encrypt(data)
+		require.NoError(t, err)
+
+		newKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		enc, err := NewEncryptor(newKey, oldKey)
+		require.NoError(t, err)
+
+		require.True(t, enc.Decryptable(encData))
+		require.False(t, enc.EncryptedWithActiveKey(encData))
+		decData, err := enc.Decrypt(encData)
+		require.NoError(t, err)
+		require.Equal(t, data, decData)
+
+		//new data is encrypted with active key
+		encData, err = enc.Encrypt(data)
+		require.NoError(t, err)
+		require.True(t, enc.EncryptedWithActiveKey(encData))
+		require.False(t, oldEnc.Decryptable(encData))
+	})
+
+	t.Run("Works not with non-HEX old key", func(t *testing.T) {
+		key, err := NewEncryptionKey()
+		require.NoError(t, err)
+		_, err = NewEncryptor(key, "abc123!")
+		require.Error(t, err)
+	})
+
 }
 
 func TestReadKeyFile(t *testing.T) {
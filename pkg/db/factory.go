@@ -16,10 +16,14 @@ func NewConnectionFactory(configFile string, migrate bool, debug bool) (Connecti
 		return nil, err
 	}
 
-	encKey, err := readEncryptionKey()
+	keyProvider, err := NewKeyProvider()
 	if err != nil {
 		return nil, err
 	}
+	//verify the keys are available (keys are cached by the provider)
+	if _, err := NewEncryptorWithKeyProvider(keyProvider); err != nil {
+		return nil, err
+	}
 
 	dbToUse := viper.GetString("db.driver")
 	blockQueries := viper.GetBool("db.blockQueries")
@@ -27,11 +31,11 @@ func NewConnectionFactory(configFile string, migrate bool, debug bool) (Connecti
 
 	switch dbToUse {
 	case "postgres":
-		connFact := createPostgresConnectionFactory(encKey, debug, blockQueries, logQueries)
+		connFact := createPostgresConnectionFactory(keyProvider, debug, blockQueries, logQueries)
 		return connFact, connFact.Init(migrate)
 
 	case "sqlite":
-		connFact, err := createSqliteConnectionFactory(encKey, debug, blockQueries, logQueries)
+		connFact, err := createSqliteConnectionFactory(keyProvider, debug, blockQueries, logQueries)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating sqliteConnectionFactory")
 		}
@@ -47,24 +51,7 @@ func MigrateDatabase(configFile string, debug bool) error {
 	return err
 }
 
-func readEncryptionKey() (string, error) {
-	encKeyFile := viper.GetString("db.encryption.keyFile")
-	if encKeyFile != "" {
-		if !filepath.IsAbs(encKeyFile) {
-			//define absolute path relative to config-file directory
-			encKeyFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), encKeyFile)
-		}
-	}
-
-	//overwrite encKeyFile if env-var if defined
-	if viper.IsSet("DATABASE_ENCRYPTION_KEYFILE") {
-		encKeyFile = viper.GetString("DATABASE_ENCRYPTION_KEYFILE")
-	}
-
-	return readKeyFile(encKeyFile)
-}
-
-func createSqliteConnectionFactory(encKey string, debug bool, blockQueries, logQueries bool) (*sqliteConnectionFactory, error) {
+func createSqliteConnectionFactory(keyProvider KeyProvider, debug bool, blockQueries, logQueries bool) (*sqliteConnectionFactory, error) {
 	dbFile := viper.GetString("db.sqlite.file")
 	//ensure directory structure of db-file exists
 	dbFileDir := filepath.Dir(dbFile)
@@ -73,21 +60,23 @@ func createSqliteConnectionFactory(encKey string, debug bool, blockQueries, logQ
 			return nil, err
 		}
 	}
-	connFact := &sqliteConnectionFactory{
+	migrationsDir := viper.GetString("db.sqlite.migrationsDir")
+	if migrationsDir == "" {
+		migrationsDir = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), "db", "sqlite")
+	}
+	return &sqliteConnectionFactory{
 		file:          dbFile,
 		debug:         debug,
 		reset:         viper.GetBool("db.sqlite.resetDatabase"),
-		encryptionKey: encKey,
+		deploySchema:  viper.GetBool("db.sqlite.deploySchema"),
+		migrationsDir: migrationsDir,
+		keyProvider:   keyProvider,
 		blockQueries:  blockQueries,
 		logQueries:    logQueries,
-	}
-	if viper.GetBool("db.sqlite.deploySchema") {
-		connFact.schemaFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), "db", "sqlite", "reconciler.sql")
-	}
-	return connFact, nil
+	}, nil
 }
 
-func createPostgresConnectionFactory(encKey string, debug bool, blockQueries, logQueries bool) *postgresConnectionFactory {
+func createPostgresConnectionFactory(keyProvider KeyProvider, debug bool, blockQueries, logQueries bool) *postgresConnectionFactory {
 	host := viper.GetString("db.postgres.host")
 	port := viper.GetInt("db.postgres.port")
 	database := viper.GetString("db.postgres.database")
@@ -125,7 +114,7 @@ func createPostgresConnectionFactory(encKey string, debug bool, blockQueries, lo
 		user:          user,
 		password:      password,
 		sslMode:       sslMode,
-		encryptionKey: encKey,
+		keyProvider:   keyProvider,
 		migrationsDir: migrationsDir,
 		blockQueries:  blockQueries,
 		logQueries:    logQueries,
@@ -46,4 +46,6 @@ type DataRow interface {
 type DataRows interface {
 	Scan(dest ...interface{}) error
 	Next() bool
+	Err() error
+	Close() error
 }
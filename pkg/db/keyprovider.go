@@ -0,0 +1,163 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+//supported key providers
+const (
+	KeyProviderFile  = "file"
+	KeyProviderVault = "vault"
+)
+
+//KeyProvider provides the data encryption keys of the Encryptor: the active key used for encryption and old keys
+//which are only used for decryption
+type KeyProvider interface {
+	EncryptionKey() (string, error)
+	DecryptionKeys() ([]string, error)
+	//WrapKey converts a new data encryption key into the format stored in the key file
+	WrapKey(key string) (string, error)
+}
+
+func NewEncryptorWithKeyProvider(keyProvider KeyProvider) (*Encryptor, error) {
+	encKey, err := keyProvider.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	decKeys, err := keyProvider.DecryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptor(encKey, decKeys...)
+}
+
+//NewKeyProvider creates the key provider defined in the configuration ('db.encryption.provider')
+func NewKeyProvider() (KeyProvider, error) {
+	keyFile := keyFilePath(viper.GetString("db.encryption.keyFile"))
+	//overwrite keyFile if env-var is defined
+	if viper.IsSet("DATABASE_ENCRYPTION_KEYFILE") {
+		keyFile = viper.GetString("DATABASE_ENCRYPTION_KEYFILE")
+	}
+
+	oldKeyFiles := viper.GetStringSlice("db.encryption.oldKeyFiles")
+	//overwrite oldKeyFiles if env-var is defined (comma separated list of files)
+	if viper.IsSet("DATABASE_ENCRYPTION_OLD_KEYFILES") {
+		oldKeyFiles = strings.Split(viper.GetString("DATABASE_ENCRYPTION_OLD_KEYFILES"), ",")
+	}
+	var oldKeyFilePaths []string
+	for _, oldKeyFile := range oldKeyFiles {
+		if strings.TrimSpace(oldKeyFile) != "" {
+			oldKeyFilePaths = append(oldKeyFilePaths, keyFilePath(strings.TrimSpace(oldKeyFile)))
+		}
+	}
+
+	provider := viper.GetString("db.encryption.provider")
+	if viper.IsSet("DATABASE_ENCRYPTION_PROVIDER") {
+		provider = viper.GetString("DATABASE_ENCRYPTION_PROVIDER")
+	}
+
+	switch provider {
+	case "", KeyProviderFile:
+		return NewCachedKeyProvider(NewFileKeyProvider(keyFile, oldKeyFilePaths)), nil
+	case KeyProviderVault:
+		vaultProvider, err := NewVaultKeyProvider(vaultConfig(), keyFile, oldKeyFilePaths)
+		if err != nil {
+			return nil, err
+		}
+		return NewCachedKeyProvider(vaultProvider), nil
+	default:
+		return nil, fmt.Errorf("encryption key provider '%s' not supported (supported providers are '%s' and '%s')",
+			provider, KeyProviderFile, KeyProviderVault)
+	}
+}
+
+func keyFilePath(keyFile string) string {
+	if keyFile != "" && !filepath.IsAbs(keyFile) {
+		//define absolute path relative to config-file directory
+		return filepath.Join(filepath.Dir(viper.ConfigFileUsed()), keyFile)
+	}
+	return keyFile
+}
+
+//FileKeyProvider reads the keys as HEX strings from files
+type FileKeyProvider struct {
+	keyFile     string
+	oldKeyFiles []string
+}
+
+func NewFileKeyProvider(keyFile string, oldKeyFiles []string) *FileKeyProvider {
+	return &FileKeyProvider{
+		keyFile:     keyFile,
+		oldKeyFiles: oldKeyFiles,
+	}
+}
+
+func (p *FileKeyProvider) EncryptionKey() (string, error) {
+	return readKeyFile(p.keyFile)
+}
+
+func (p *FileKeyProvider) DecryptionKeys() ([]string, error) {
+	var keys []string
+	for _, oldKeyFile := range p.oldKeyFiles {
+		key, err := readKeyFile(oldKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (p *FileKeyProvider) WrapKey(key string) (string, error) {
+	return key, nil
+}
+
+//CachedKeyProvider keeps the keys of a key provider in memory: keys are only loaded once
+type CachedKeyProvider struct {
+	KeyProvider
+	mu      sync.Mutex
+	encKey  string
+	decKeys []string
+	loaded  bool
+}
+
+func NewCachedKeyProvider(keyProvider KeyProvider) *CachedKeyProvider {
+	return &CachedKeyProvider{KeyProvider: keyProvider}
+}
+
+func (p *CachedKeyProvider) EncryptionKey() (string, error) {
+	if err := p.load(); err != nil {
+		return "", err
+	}
+	return p.encKey, nil
+}
+
+func (p *CachedKeyProvider) DecryptionKeys() ([]string, error) {
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p.decKeys, nil
+}
+
+func (p *CachedKeyProvider) load() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return nil
+	}
+	encKey, err := p.KeyProvider.EncryptionKey()
+	if err != nil {
+		return err
+	}
+	decKeys, err := p.KeyProvider.DecryptionKeys()
+	if err != nil {
+		return err
+	}
+	p.encKey, p.decKeys, p.loaded = encKey, decKeys, true
+	return nil
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testKeyProvider struct {
+	key     string
+	oldKeys []string
+	loaded  int
+}
+
+func (p *testKeyProvider) EncryptionKey() (string, error) {
+	p.loaded++
+	return p.key, nil
+}
+
+func (p *testKeyProvider) DecryptionKeys() ([]string, error) {
+	return p.oldKeys, nil
+}
+
+func (p *testKeyProvider) WrapKey(key string) (string, error) {
+	return key, nil
+}
+
+const testVaultToken = "s.token"
+
+//newTestVaultServer simulates the transit secrets engine of Vault: the wrapped key is the reversed plaintext
+func newTestVaultServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testVaultToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var resp map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/reconciler":
+			resp = map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + reverse(req["plaintext"])}}
+		case "/v1/transit/decrypt/reconciler":
+			if !strings.HasPrefix(req["ciphertext"], "vault:v1:") {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid ciphertext: no prefix"]}`))
+				return
+			}
+			resp = map[string]interface{}{"data": map[string]string{"plaintext": reverse(strings.TrimPrefix(req["ciphertext"], "vault:v1:"))}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func reverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func TestKeyProvider(t *testing.T) {
+	t.Run("Read keys from files", func(t *testing.T) {
+		provider := NewFileKeyProvider(filepath.Join("test", "valid.key"), []string{filepath.Join("test", "valid.key")})
+		key, err := provider.EncryptionKey()
+		require.NoError(t, err)
+		require.NotEmpty(t, key)
+		oldKeys, err := provider.DecryptionKeys()
+		require.NoError(t, err)
+		require.Equal(t, []string{key}, oldKeys)
+
+		_, err = NewFileKeyProvider(filepath.Join("test", "donexist.key"), nil).EncryptionKey()
+		require.Error(t, err)
+	})
+
+	t.Run("Cache keys", func(t *testing.T) {
+		key, err := NewEncryptionKey()
+		require.NoError(t, err)
+		provider := &testKeyProvider{key: key}
+		cachedProvider := NewCachedKeyProvider(provider)
+		for i := 0; i < 3; i++ {
+			_, err := NewEncryptorWithKeyProvider(cachedProvider)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 1, provider.loaded)
+	})
+
+	t.Run("Wrap and unwrap keys with Vault", func(t *testing.T) {
+		server := newTestVaultServer(t)
+		keyFile := filepath.Join(t.TempDir(), "reconciler.key")
+
+		provider, err := NewVaultKeyProvider(&VaultConfig{
+			Address: server.URL,
+			Token:   testVaultToken,
+			KeyName: "reconciler",
+		}, keyFile, []string{keyFile})
+		require.NoError(t, err)
+
+		key, err := NewEncryptionKey()
+		require.NoError(t, err)
+		wrappedKey, err := provider.WrapKey(key)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(wrappedKey, "vault:v1:"))
+		require.NoError(t, ioutil.WriteFile(keyFile, []byte(wrappedKey), 0600))
+
+		unwrappedKey, err := provider.EncryptionKey()
+		require.NoError(t, err)
+		require.Equal(t, key, unwrappedKey)
+		oldKeys, err := provider.DecryptionKeys()
+		require.NoError(t, err)
+		require.Equal(t, []string{key}, oldKeys)
+	})
+
+	t.Run("Fail if Vault rejects request", func(t *testing.T) {
+		server := newTestVaultServer(t)
+		keyFile := filepath.Join(t.TempDir(), "reconciler.key")
+		require.NoError(t, ioutil.WriteFile(keyFile, []byte("vault:v1:abc"), 0600))
+
+		provider, err := NewVaultKeyProvider(&VaultConfig{
+			Address: server.URL,
+			Token:   "invalid",
+			KeyName: "reconciler",
+		}, keyFile, nil)
+		require.NoError(t, err)
+
+		_, err = provider.EncryptionKey()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "permission denied")
+	})
+
+	t.Run("Reject incomplete Vault configuration", func(t *testing.T) {
+		_, err := NewVaultKeyProvider(&VaultConfig{Address: "http://localhost:8200", KeyName: "reconciler"}, "", nil)
+		require.Error(t, err)
+	})
+}
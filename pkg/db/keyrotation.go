@@ -0,0 +1,157 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const defaultKeyRotationBatchSize = 100
+
+//KeyRotationProgress reports the re-encrypted values of an encrypted column
+type KeyRotationProgress struct {
+	Table   string
+	Column  string
+	Rotated int64 //values of the column which were re-encrypted so far
+	Total   int64 //values of the column which were not encrypted with the active key when the rotation started
+}
+
+//KeyRotator re-encrypts the values of encrypted columns with the active encryption key. Values encrypted with an
+//old key of the keyring are decrypted and encrypted again. Each batch of values is updated in a transaction.
+type KeyRotator struct {
+	conn      Connection
+	batchSize int
+	progress  func(progress *KeyRotationProgress)
+	logger    *zap.SugaredLogger
+}
+
+func NewKeyRotator(conn Connection, logger *zap.SugaredLogger) *KeyRotator {
+	return &KeyRotator{
+		conn:      conn,
+		batchSize: defaultKeyRotationBatchSize,
+		logger:    logger,
+	}
+}
+
+//WithBatchSize defines how many values are re-encrypted per transaction
+func (r *KeyRotator) WithBatchSize(batchSize int) *KeyRotator {
+	if batchSize > 0 {
+		r.batchSize = batchSize
+	}
+	return r
+}
+
+//WithProgress registers a callback which is called after each batch
+func (r *KeyRotator) WithProgress(progress func(progress *KeyRotationProgress)) *KeyRotator {
+	r.progress = progress
+	return r
+}
+
+//Rotate re-encrypts all encrypted columns of the entities and returns the number of re-encrypted values
+func (r *KeyRotator) Rotate(entities ...DatabaseEntity) (int64, error) {
+	var rotated int64
+	for _, entity := range entities {
+		colHdlr, err := NewColumnHandler(entity, r.conn, r.logger)
+		if err != nil {
+			return rotated, err
+		}
+		for _, column := range colHdlr.EncryptedColumnNames() {
+			count, err := r.rotateColumn(entity.Table(), column)
+			rotated += count
+			if err != nil {
+				return rotated, errors.Wrap(err, fmt.Sprintf("failed to re-encrypt column '%s' of table '%s'",
+					column, entity.Table()))
+			}
+		}
+	}
+	return rotated, nil
+}
+
+func (r *KeyRotator) rotateColumn(table, column string) (int64, error) {
+	progress := &KeyRotationProgress{
+		Table:  table,
+		Column: column,
+	}
+
+	//raw queries are executed in transactions: the query validation of connections would reject them
+	err := Transaction(r.conn, func(tx *TxConnection) error {
+		row, err := tx.QueryRow(
+			fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", table, rotationCondition(column)), r.activeKeyPattern())
+		if err != nil {
+			return err
+		}
+		return row.Scan(&progress.Total)
+	}, r.logger)
+	if err != nil {
+		return 0, err
+	}
+	r.logger.Infof("Re-encrypting %d values of column '%s' of table '%s'", progress.Total, column, table)
+
+	for {
+		var count int64
+		err := Transaction(r.conn, func(tx *TxConnection) error {
+			var err error
+			count, err = r.rotateBatch(tx, table, column)
+			return err
+		}, r.logger)
+		if err != nil {
+			return progress.Rotated, err
+		}
+		if count == 0 {
+			return progress.Rotated, nil
+		}
+		progress.Rotated += count
+		if r.progress != nil {
+			r.progress(progress)
+		}
+	}
+}
+
+func (r *KeyRotator) rotateBatch(tx *TxConnection, table, column string) (int64, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT %s FROM %s WHERE %s LIMIT %d",
+		column, table, rotationCondition(column), r.batchSize), r.activeKeyPattern())
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var encValues []string
+	for rows.Next() { //all rows have to be read before the updates are executed
+		var encValue string
+		if err := rows.Scan(&encValue); err != nil {
+			return 0, err
+		}
+		encValues = append(encValues, encValue)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	encryptor := tx.Encryptor()
+	for _, encValue := range encValues {
+		value, err := encryptor.Decrypt(encValue)
+		if err != nil {
+			return 0, errors.Wrap(err, "value cannot be decrypted with any key of the keyring")
+		}
+		newEncValue, err := encryptor.Encrypt(value)
+		if err != nil {
+			return 0, err
+		}
+		//encrypted values are unique (random nonce) and identify the row
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s=$1 WHERE %s=$2", table, column, column),
+			newEncValue, encValue); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(encValues)), nil
+}
+
+//rotationCondition selects the values of the column which aren't encrypted with the active key (empty values
+//aren't encrypted and are skipped)
+func rotationCondition(column string) string {
+	return fmt.Sprintf("%s NOT LIKE $1 AND %s <> ''", column, column)
+}
+
+func (r *KeyRotator) activeKeyPattern() string {
+	return r.conn.Encryptor().KeyID() + "%"
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRotator(t *testing.T) {
+	oldKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+	newKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "rotation.db"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sqlDB.Close())
+	}()
+	_, err = sqlDB.Exec("CREATE TABLE mockTable (col_1 text, col_2 boolean, col_3 text)")
+	require.NoError(t, err)
+
+	//store values encrypted with the old key
+	oldConn, err := newSqliteConnection(sqlDB, &testKeyProvider{key: oldKey}, true, false)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		encValue, err := oldConn.Encryptor().Encrypt(fmt.Sprintf("value%d", i))
+		require.NoError(t, err)
+		_, err = sqlDB.Exec("INSERT INTO mockTable (col_1, col_2, col_3) VALUES ($1, $2, $3)", "abc", true, encValue)
+		require.NoError(t, err)
+	}
+
+	//empty values aren't encrypted and have to be skipped
+	_, err = sqlDB.Exec("INSERT INTO mockTable (col_1, col_2, col_3) VALUES ($1, $2, $3)", "abc", true, "")
+	require.NoError(t, err)
+
+	newConn, err := newSqliteConnection(sqlDB, &testKeyProvider{key: newKey, oldKeys: []string{oldKey}}, true, false)
+	require.NoError(t, err)
+
+	t.Run("Re-encrypt values with active key", func(t *testing.T) {
+		var progress []KeyRotationProgress
+		rotated, err := NewKeyRotator(newConn, logger.NewLogger(true)).
+			WithBatchSize(2).
+			WithProgress(func(p *KeyRotationProgress) {
+				progress = append(progress, *p)
+			}).
+			Rotate(&MockDbEntity{})
+		require.NoError(t, err)
+		require.Equal(t, int64(5), rotated)
+		require.Equal(t, []KeyRotationProgress{
+			{Table: "mockTable", Column: "col_3", Rotated: 2, Total: 5},
+			{Table: "mockTable", Column: "col_3", Rotated: 4, Total: 5},
+			{Table: "mockTable", Column: "col_3", Rotated: 5, Total: 5},
+		}, progress)
+
+		//values are decryptable without old key
+		encryptor, err := NewEncryptor(newKey)
+		require.NoError(t, err)
+		rows, err := sqlDB.Query("SELECT col_3 FROM mockTable")
+		require.NoError(t, err)
+		var values []string
+		for rows.Next() {
+			var encValue string
+			require.NoError(t, rows.Scan(&encValue))
+			if encValue == "" {
+				continue
+			}
+			require.True(t, encryptor.EncryptedWithActiveKey(encValue))
+			value, err := encryptor.Decrypt(encValue)
+			require.NoError(t, err)
+			values = append(values, value)
+		}
+		require.ElementsMatch(t, []string{"value0", "value1", "value2", "value3", "value4"}, values)
+
+		//nothing left to rotate
+		rotated, err = NewKeyRotator(newConn, logger.NewLogger(true)).Rotate(&MockDbEntity{})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), rotated)
+	})
+
+	t.Run("Fail for values encrypted with unknown key", func(t *testing.T) {
+		encValue, err := newEncryptor(t).Encrypt("unknown")
+		require.NoError(t, err)
+		_, err = sqlDB.Exec("INSERT INTO mockTable (col_1, col_2, col_3) VALUES ($1, $2, $3)", "abc", true, encValue)
+		require.NoError(t, err)
+
+		_, err = NewKeyRotator(newConn, logger.NewLogger(true)).Rotate(&MockDbEntity{})
+		require.Error(t, err)
+	})
+}
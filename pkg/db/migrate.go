@@ -0,0 +1,82 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/pkg/errors"
+
+	//add migrator source:
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.uber.org/zap"
+)
+
+type migrateLogger struct {
+	logger  *zap.SugaredLogger
+	verbose bool
+}
+
+func newMigrateLogger(debug bool) *migrateLogger {
+	logger := log.NewLogger(debug)
+	return &migrateLogger{
+		logger:  logger,
+		verbose: debug,
+	}
+}
+
+func (ml *migrateLogger) Printf(format string, v ...interface{}) {
+	if ml.verbose {
+		ml.logger.Debugf(format, v...)
+	} else {
+		ml.logger.Infof(format, v...)
+	}
+}
+
+func (ml *migrateLogger) Verbose() bool {
+	return ml.verbose
+}
+
+//migrateDatabase applies all pending migrations of the migrations directory and closes the connection afterwards
+func migrateDatabase(dbConn Connection, migrationsDir string, debug bool) error {
+	migrateLogger := newMigrateLogger(debug)
+	defer func() {
+		if err := dbConn.Close(); err != nil {
+			migrateLogger.logger.Warnf("Failed to close DB connection which was used to perform migration: %s", err)
+		}
+	}()
+	m, err := newMigrator(dbConn, migrationsDir)
+	if err != nil {
+		return err
+	}
+	m.Log = migrateLogger
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return errors.Wrapf(err, "not able to execute migrations: %s", err)
+	}
+	migrateLogger.logger.Info("Database migrated")
+	return nil
+}
+
+func newMigrator(dbConn Connection, migrationsDir string) (*migrate.Migrate, error) {
+	var driver database.Driver
+	var err error
+	switch dbConn.Type() {
+	case Postgres:
+		driver, err = postgres.WithInstance(dbConn.DB(), &postgres.Config{})
+	case SQLite:
+		driver, err = sqlite3.WithInstance(dbConn.DB(), &sqlite3.Config{})
+	default:
+		return nil, fmt.Errorf("migration of DB type '%s' not supported", dbConn.Type())
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "not able to instantiate %s driver for migration", dbConn.Type())
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationsDir, string(dbConn.Type()), driver)
+	if err != nil {
+		return nil, errors.Wrap(err, "not able to instantiate migrator with database instance")
+	}
+	return m, nil
+}
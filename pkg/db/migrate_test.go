@@ -0,0 +1,280 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kyma-incubator/reconciler/pkg/test"
+	"github.com/stretchr/testify/require"
+)
+
+//dbSchema contains the aspects of a DB schema which are comparable between Postgres and SQLite
+//(data types and defaults are DB specific)
+type dbSchema struct {
+	tables  map[string][]string //table name -> column names with nullability
+	indexes []string            //explicitly created indexes (indexes of constraints are DB specific)
+}
+
+func migrationsDir(t *testing.T, dbType Type) string {
+	configFile, err := test.GetConfigFile()
+	require.NoError(t, err)
+	return filepath.Join(filepath.Dir(configFile), "db", string(dbType))
+}
+
+func newTestSqliteConnectionFactory(t *testing.T, dbFile string) *sqliteConnectionFactory {
+	key, err := NewEncryptionKey()
+	require.NoError(t, err)
+	return &sqliteConnectionFactory{
+		file:          dbFile,
+		debug:         true,
+		deploySchema:  true,
+		migrationsDir: migrationsDir(t, SQLite),
+		keyProvider:   &testKeyProvider{key: key},
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	t.Run("SQLite migrations match Postgres migrations", func(t *testing.T) {
+		require.Equal(t, migrationFiles(t, Postgres), migrationFiles(t, SQLite))
+	})
+
+	t.Run("Migrate SQLite DB up and down", func(t *testing.T) {
+		connFact := newTestSqliteConnectionFactory(t, filepath.Join(t.TempDir(), "migration.db"))
+		require.NoError(t, connFact.Init(false))
+
+		conn, err := connFact.NewConnection()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		m, err := newMigrator(conn, connFact.migrationsDir)
+		require.NoError(t, err)
+		version, dirty, err := m.Version()
+		require.NoError(t, err)
+		require.False(t, dirty)
+		require.Equal(t, uint(len(migrationFiles(t, SQLite))/2), version) //up and down file per migration
+
+		require.NoError(t, m.Down())
+		require.Empty(t, sqliteSchema(t, conn.DB()).tables)
+		require.NoError(t, m.Up())
+		require.NotEmpty(t, sqliteSchema(t, conn.DB()).tables)
+	})
+
+	t.Run("Keep data of existing SQLite DB", func(t *testing.T) {
+		dbFile := filepath.Join(t.TempDir(), "persistent.db")
+		require.NoError(t, newTestSqliteConnectionFactory(t, dbFile).Init(false))
+
+		conn, err := newTestSqliteConnectionFactory(t, dbFile).NewConnection()
+		require.NoError(t, err)
+		_, err = conn.DB().Exec("INSERT INTO config_changes (runtime_id) VALUES ('abc')")
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+
+		//initialize the DB again (e.g. by the next run of the local mode)
+		connFact := newTestSqliteConnectionFactory(t, dbFile)
+		require.NoError(t, connFact.Init(true))
+		conn, err = connFact.NewConnection()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		var count int
+		require.NoError(t, conn.DB().QueryRow("SELECT COUNT(*) FROM config_changes").Scan(&count))
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("Migrate SQLite DB created by the former DDL file", func(t *testing.T) {
+		for ddlFile, expectedBaseline := range map[string]int{
+			"reconciler-v12.sql": 12, //DDL file before the configuration management changes
+			"reconciler.sql":     15, //latest DDL file before the migration chain was introduced
+		} {
+			t.Run(ddlFile, func(t *testing.T) {
+				dbFile := filepath.Join(t.TempDir(), "legacy.db")
+				connFact := newTestSqliteConnectionFactory(t, dbFile)
+				connFact.deploySchema = false
+				require.NoError(t, connFact.Init(false)) //creates the DB file without schema
+
+				ddl, err := ioutil.ReadFile(filepath.Join("test", ddlFile))
+				require.NoError(t, err)
+				conn, err := connFact.NewConnection()
+				require.NoError(t, err)
+				_, err = conn.DB().Exec(string(ddl))
+				require.NoError(t, err)
+				_, err = conn.DB().Exec("INSERT INTO inventory_clusters (runtime_id, runtime, metadata, kubeconfig, contract) " +
+					"VALUES ('abc', '{}', '{}', 'kubeconfig', 1)")
+				require.NoError(t, err)
+				version, err := legacySchemaVersion(conn.DB())
+				require.NoError(t, err)
+				require.Equal(t, expectedBaseline, version)
+				require.NoError(t, conn.Close())
+
+				//initialize the DB (e.g. by the local mode)
+				require.NoError(t, connFact.Init(true))
+				conn, err = connFact.NewConnection()
+				require.NoError(t, err)
+				defer func() {
+					require.NoError(t, conn.Close())
+				}()
+				m, err := newMigrator(conn, connFact.migrationsDir)
+				require.NoError(t, err)
+				version2, dirty, err := m.Version()
+				require.NoError(t, err)
+				require.False(t, dirty)
+				require.Equal(t, uint(len(migrationFiles(t, SQLite))/2), version2)
+
+				var count int
+				require.NoError(t, conn.DB().QueryRow("SELECT COUNT(*) FROM inventory_clusters").Scan(&count))
+				require.Equal(t, 1, count)
+
+				//tables and columns equal the schema created by the migrations (the former DDL file
+				//defined some nullability constraints differently)
+				migratedConnFact := newTestSqliteConnectionFactory(t, filepath.Join(t.TempDir(), "migrated.db"))
+				require.NoError(t, migratedConnFact.Init(false))
+				migratedConn, err := migratedConnFact.NewConnection()
+				require.NoError(t, err)
+				defer func() {
+					require.NoError(t, migratedConn.Close())
+				}()
+				require.Equal(t, columnNames(sqliteSchema(t, migratedConn.DB())), columnNames(sqliteSchema(t, conn.DB())))
+			})
+		}
+	})
+
+	t.Run("SQLite and Postgres schemas are equal", func(t *testing.T) {
+		test.IntegrationTest(t)
+
+		//apply SQLite migrations
+		sqliteConnFact := newTestSqliteConnectionFactory(t, filepath.Join(t.TempDir(), "schema.db"))
+		require.NoError(t, sqliteConnFact.Init(false))
+		sqliteConn, err := sqliteConnFact.NewConnection()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, sqliteConn.Close())
+		}()
+
+		//apply Postgres migrations in a separate schema
+		pgConnFact, ok := NewTestConnectionFactory(t).(*postgresConnectionFactory)
+		require.True(t, ok, "Postgres is required to compare the schemas")
+		pgConn, err := pgConnFact.NewConnection()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, pgConn.Close())
+		}()
+
+		schema := fmt.Sprintf("migration_%s", uuid.NewString()[:8])
+		_, err = pgConn.DB().Exec(fmt.Sprintf("CREATE SCHEMA %s", schema))
+		require.NoError(t, err)
+		defer func() {
+			_, err := pgConn.DB().Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+			require.NoError(t, err)
+		}()
+
+		pgSchemaDB, err := sql.Open("postgres", fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable search_path=%s",
+			pgConnFact.host, pgConnFact.port, pgConnFact.user, pgConnFact.password, pgConnFact.database, schema))
+		require.NoError(t, err)
+		pgSchemaConn, err := newPostgresConnection(pgSchemaDB, pgConnFact.keyProvider, true, false)
+		require.NoError(t, err)
+		require.NoError(t, migrateDatabase(pgSchemaConn, migrationsDir(t, Postgres), true))
+
+		require.Equal(t, postgresSchema(t, pgConn.DB(), schema), sqliteSchema(t, sqliteConn.DB()))
+	})
+}
+
+func migrationFiles(t *testing.T, dbType Type) []string {
+	files, err := ioutil.ReadDir(migrationsDir(t, dbType))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func sqliteSchema(t *testing.T, db *sql.DB) *dbSchema {
+	schema := &dbSchema{tables: make(map[string][]string)}
+
+	tables := queryStrings(t, db, "SELECT name FROM sqlite_master "+
+		"WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_migrations'")
+	for _, table := range tables {
+		rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+		require.NoError(t, err)
+		for rows.Next() {
+			var cid, notNull, pk int
+			var name, dataType string
+			var dflt sql.NullString
+			require.NoError(t, rows.Scan(&cid, &name, &dataType, &notNull, &dflt, &pk))
+			//primary key columns are implicitly NOT NULL (like in Postgres)
+			schema.tables[table] = append(schema.tables[table], columnNullability(name, notNull == 1 || pk > 0))
+		}
+		require.NoError(t, rows.Err())
+		sort.Strings(schema.tables[table])
+	}
+
+	schema.indexes = queryStrings(t, db, "SELECT tbl_name || '.' || name FROM sqlite_master "+
+		"WHERE type='index' AND sql IS NOT NULL")
+	return schema
+}
+
+func postgresSchema(t *testing.T, db *sql.DB, schemaName string) *dbSchema {
+	schema := &dbSchema{tables: make(map[string][]string)}
+
+	rows, err := db.Query("SELECT table_name, column_name, is_nullable FROM information_schema.columns "+
+		"WHERE table_schema=$1 AND table_name != 'schema_migrations'", schemaName)
+	require.NoError(t, err)
+	for rows.Next() {
+		var table, name, nullable string
+		require.NoError(t, rows.Scan(&table, &name, &nullable))
+		schema.tables[table] = append(schema.tables[table], columnNullability(name, nullable == "NO"))
+	}
+	require.NoError(t, rows.Err())
+	for table := range schema.tables {
+		sort.Strings(schema.tables[table])
+	}
+
+	schema.indexes = queryStrings(t, db, "SELECT t.relname || '.' || i.relname FROM pg_index x "+
+		"JOIN pg_class i ON i.oid = x.indexrelid "+
+		"JOIN pg_class t ON t.oid = x.indrelid "+
+		"JOIN pg_namespace n ON n.oid = t.relnamespace "+
+		"WHERE n.nspname = $1 AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = x.indexrelid)",
+		schemaName)
+	return schema
+}
+
+//columnNames returns the column names per table of a schema (without nullability)
+func columnNames(schema *dbSchema) map[string][]string {
+	result := make(map[string][]string, len(schema.tables))
+	for table, columns := range schema.tables {
+		for _, column := range columns {
+			result[table] = append(result[table], strings.Fields(column)[0])
+		}
+	}
+	return result
+}
+
+func columnNullability(name string, notNull bool) string {
+	if notNull {
+		return name + " NOT NULL"
+	}
+	return name + " NULL"
+}
+
+func queryStrings(t *testing.T, db *sql.DB, query string, args ...interface{}) []string {
+	rows, err := db.Query(query, args...)
+	require.NoError(t, err)
+	var result []string
+	for rows.Next() {
+		var value string
+		require.NoError(t, rows.Scan(&value))
+		result = append(result, value)
+	}
+	require.NoError(t, rows.Err())
+	sort.Strings(result)
+	return result
+}
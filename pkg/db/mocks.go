@@ -37,6 +37,14 @@ func (dr *MockDataRows) Next() bool {
 	return false
 }
 
+func (dr *MockDataRows) Err() error {
+	return nil
+}
+
+func (dr *MockDataRows) Close() error {
+	return nil
+}
+
 type MockResult struct {
 }
 
@@ -12,39 +12,9 @@ import (
 	//add Postgres driver:
 	_ "github.com/lib/pq"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-
-	//add migrator source:
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"go.uber.org/zap"
 )
 
-type migrateLogger struct {
-	logger  *zap.SugaredLogger
-	verbose bool
-}
-
-func newMigrateLogger(debug bool) *migrateLogger {
-	logger := log.NewLogger(debug)
-	return &migrateLogger{
-		logger:  logger,
-		verbose: debug,
-	}
-}
-
-func (ml *migrateLogger) Printf(format string, v ...interface{}) {
-	if ml.verbose {
-		ml.logger.Debugf(format, v...)
-	} else {
-		ml.logger.Infof(format, v...)
-	}
-}
-
-func (ml *migrateLogger) Verbose() bool {
-	return ml.verbose
-}
-
 type postgresConnection struct {
 	id        string
 	db        *sql.DB
@@ -53,10 +23,10 @@ type postgresConnection struct {
 	logger    *zap.SugaredLogger
 }
 
-func newPostgresConnection(db *sql.DB, encryptionKey string, debug bool, blockQueries bool) (*postgresConnection, error) {
+func newPostgresConnection(db *sql.DB, keyProvider KeyProvider, debug bool, blockQueries bool) (*postgresConnection, error) {
 	logger := log.NewLogger(debug)
 
-	encryptor, err := NewEncryptor(encryptionKey)
+	encryptor, err := NewEncryptorWithKeyProvider(keyProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +116,7 @@ type postgresConnectionFactory struct {
 	user          string
 	password      string
 	sslMode       bool
-	encryptionKey string
+	keyProvider   KeyProvider
 	migrationsDir string
 	debug         bool
 	blockQueries  bool
@@ -184,7 +154,7 @@ func (pcf *postgresConnectionFactory) NewConnection() (Connection, error) {
 		return nil, err
 	}
 
-	return newPostgresConnection(db, pcf.encryptionKey, pcf.logQueries, pcf.blockQueries)
+	return newPostgresConnection(db, pcf.keyProvider, pcf.logQueries, pcf.blockQueries)
 }
 
 func (pcf *postgresConnectionFactory) checkPostgresIsolationLevel() error {
@@ -226,28 +196,9 @@ func (pcf *postgresConnectionFactory) checkPostgresIsolationLevel() error {
 }
 
 func (pcf *postgresConnectionFactory) migrateDatabase() error {
-	migrateLogger := newMigrateLogger(pcf.debug)
 	dbConn, err := pcf.NewConnection()
 	if err != nil {
 		return errors.Wrap(err, "not able to open DB connection to perform migration")
 	}
-	defer func() {
-		if err := dbConn.Close(); err != nil {
-			migrateLogger.logger.Warnf("Failed to close DB connection which was used to perform migration: %s", err)
-		}
-	}()
-	driver, err := postgres.WithInstance(dbConn.DB(), &postgres.Config{})
-	if err != nil {
-		return errors.Wrap(err, "not able to instantiate postgres driver for migration")
-	}
-	m, err := migrate.NewWithDatabaseInstance("file://"+pcf.migrationsDir, "postgres", driver)
-	if err != nil {
-		return errors.Wrap(err, "not able to instantiate migrator with database instance")
-	}
-	m.Log = migrateLogger
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return errors.Wrapf(err, "not able to execute migrations: %s", err)
-	}
-	migrateLogger.logger.Info("Database migrated")
-	return nil
+	return migrateDatabase(dbConn, pcf.migrationsDir, pcf.debug)
 }
@@ -6,7 +6,6 @@ import (
 	"github.com/google/uuid"
 	log "github.com/kyma-incubator/reconciler/pkg/logger"
 	"github.com/pkg/errors"
-	"io/ioutil"
 	"os"
 
 	//add SQlite driver:
@@ -23,10 +22,10 @@ type sqliteConnection struct {
 	logger    *zap.SugaredLogger
 }
 
-func newSqliteConnection(db *sql.DB, encKey string, debug bool, blockQueries bool) (*sqliteConnection, error) {
+func newSqliteConnection(db *sql.DB, keyProvider KeyProvider, debug bool, blockQueries bool) (*sqliteConnection, error) {
 	logger := log.NewLogger(debug)
 
-	encryptor, err := NewEncryptor(encKey)
+	encryptor, err := NewEncryptorWithKeyProvider(keyProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -113,38 +112,49 @@ type sqliteConnectionFactory struct {
 	file          string
 	debug         bool
 	reset         bool
-	schemaFile    string
-	encryptionKey string
+	deploySchema  bool
+	migrationsDir string
+	keyProvider   KeyProvider
 	blockQueries  bool
 	logQueries    bool
 }
 
-func (scf *sqliteConnectionFactory) Init(_ bool) error {
+func (scf *sqliteConnectionFactory) Init(migrate bool) error {
 	if scf.reset {
 		if err := scf.resetFile(); err != nil {
 			return err
 		}
 	}
-	if scf.schemaFile != "" {
-		//read DDL (test-table structure)
-		ddl, err := ioutil.ReadFile(scf.schemaFile)
-		if err != nil {
-			return errors.Wrapf(err, "error reading file DDL schema file '%s'", scf.schemaFile)
-		}
-
-		//get connection
+	if migrate || scf.deploySchema {
+		//apply pending migrations: the data of existing DB files is kept
 		conn, err := scf.NewConnection()
 		if err != nil {
 			return errors.Wrap(err, "error getting sqliteConnectionFactory connection")
 		}
-
-		//populate DB schema
-		_, err = conn.Exec(string(ddl))
-		return errors.Wrap(err, "error populating DB schema")
+		if err := scf.baselineLegacySchema(conn); err != nil {
+			return errors.Wrap(err, "error detecting version of DB schema")
+		}
+		return errors.Wrap(migrateDatabase(conn, scf.migrationsDir, scf.debug), "error migrating DB schema")
 	}
 	return nil
 }
 
+//baselineLegacySchema sets the migration version of DB files which were created by the former DDL file
+//(reconciler.sql): such files have no version record, and migrating them from the first migration would fail
+func (scf *sqliteConnectionFactory) baselineLegacySchema(conn Connection) error {
+	version, err := legacySchemaVersion(conn.DB())
+	if err != nil || version == 0 {
+		return err
+	}
+	m, err := newMigrator(conn, scf.migrationsDir)
+	if err != nil {
+		return err
+	}
+	log.NewLogger(scf.debug).Infof("SQLite DB '%s' has no migration version: "+
+		"setting version %d which matches its schema", scf.file, version)
+	return m.Force(version)
+}
+
 func (scf *sqliteConnectionFactory) NewConnection() (Connection, error) {
 	db, err := sql.Open("sqlite3", scf.file) //establish connection
 	if err != nil {
@@ -156,7 +166,7 @@ func (scf *sqliteConnectionFactory) NewConnection() (Connection, error) {
 		return nil, err
 	}
 
-	return newSqliteConnection(db, scf.encryptionKey, scf.logQueries, scf.blockQueries) //connection ready to use
+	return newSqliteConnection(db, scf.keyProvider, scf.logQueries, scf.blockQueries) //connection ready to use
 }
 
 func (scf *sqliteConnectionFactory) resetFile() error {
@@ -173,3 +183,47 @@ func (scf *sqliteConnectionFactory) resetFile() error {
 	}
 	return nil
 }
+
+//legacySchemaVersions maps schemas created by the former DDL file to the migration version which creates the same
+//schema (ordered from the latest to the oldest schema)
+var legacySchemaVersions = []struct {
+	version int
+	table   string
+	column  string //empty if the table is sufficient to identify the version
+}{
+	{version: 15, table: "scheduler_operations", column: "trigger_results"},
+	{version: 14, table: "config_changes"},
+	{version: 13, table: "scheduler_operations", column: "effective_config"},
+	{version: 12, table: "inventory_clusters", column: "runtime_id"},
+}
+
+//legacySchemaVersion returns the migration version of a schema which was created by the former DDL file
+//(0 if the DB is empty or has a migration version already)
+func legacySchemaVersion(db *sql.DB) (int, error) {
+	migrated, err := sqliteColumnExists(db, "schema_migrations", "")
+	if err != nil || migrated {
+		return 0, err
+	}
+	for _, legacySchema := range legacySchemaVersions {
+		exists, err := sqliteColumnExists(db, legacySchema.table, legacySchema.column)
+		if err != nil {
+			return 0, err
+		}
+		if exists {
+			return legacySchema.version, nil
+		}
+	}
+	return 0, nil
+}
+
+//sqliteColumnExists checks whether a table (and a column of it if column isn't empty) exists
+func sqliteColumnExists(db *sql.DB, table, column string) (bool, error) {
+	var count int
+	var err error
+	if column == "" {
+		err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=$1", table).Scan(&count)
+	} else {
+		err = db.QueryRow("SELECT COUNT(*) FROM pragma_table_info($1) WHERE name=$2", table, column).Scan(&count)
+	}
+	return count > 0, err
+}
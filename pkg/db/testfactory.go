@@ -1,6 +1,7 @@
 package db
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/kyma-incubator/reconciler/pkg/test"
@@ -25,3 +26,26 @@ func NewTestConnection(t *testing.T) Connection {
 	require.NoError(t, err)
 	return conn
 }
+
+//NewTestSqliteConnection returns a connection to a new SQLite DB in a temporary directory which was migrated to
+//the latest schema (doesn't require an integration test environment)
+func NewTestSqliteConnection(t *testing.T) Connection {
+	configFile, err := test.GetConfigFile()
+	require.NoError(t, err)
+	configDir := filepath.Dir(configFile)
+
+	connFac := &sqliteConnectionFactory{
+		file:          filepath.Join(t.TempDir(), "reconciler.db"),
+		debug:         true,
+		deploySchema:  true,
+		migrationsDir: filepath.Join(configDir, "db", "sqlite"),
+		keyProvider:   NewFileKeyProvider(filepath.Join(configDir, "encryption", "unittest.key"), nil),
+	}
+	require.NoError(t, connFac.Init(false))
+	conn, err := connFac.NewConnection()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	return conn
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultVaultTransitMount = "transit"
+	vaultRequestTimeout      = 30 * time.Second
+)
+
+//VaultConfig defines the transit secrets engine of a HashiCorp Vault which wraps the data encryption keys
+type VaultConfig struct {
+	Address      string
+	Token        string
+	Namespace    string //Vault Enterprise namespace (optional)
+	TransitMount string
+	KeyName      string //name of the transit key used as key encryption key
+}
+
+func vaultConfig() *VaultConfig {
+	config := &VaultConfig{
+		Address:      viper.GetString("db.encryption.vault.address"),
+		Token:        viper.GetString("db.encryption.vault.token"),
+		Namespace:    viper.GetString("db.encryption.vault.namespace"),
+		TransitMount: viper.GetString("db.encryption.vault.transitMount"),
+		KeyName:      viper.GetString("db.encryption.vault.keyName"),
+	}
+	if viper.IsSet("DATABASE_ENCRYPTION_VAULT_ADDRESS") {
+		config.Address = viper.GetString("DATABASE_ENCRYPTION_VAULT_ADDRESS")
+	}
+	if viper.IsSet("DATABASE_ENCRYPTION_VAULT_TOKEN") {
+		config.Token = viper.GetString("DATABASE_ENCRYPTION_VAULT_TOKEN")
+	}
+	if viper.IsSet("DATABASE_ENCRYPTION_VAULT_NAMESPACE") {
+		config.Namespace = viper.GetString("DATABASE_ENCRYPTION_VAULT_NAMESPACE")
+	}
+	return config
+}
+
+func (c *VaultConfig) validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("Vault address is undefined")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("Vault token is undefined")
+	}
+	if c.KeyName == "" {
+		return fmt.Errorf("name of the Vault transit key is undefined")
+	}
+	if c.TransitMount == "" {
+		c.TransitMount = defaultVaultTransitMount
+	}
+	return nil
+}
+
+//VaultKeyProvider implements envelope encryption: the key files contain data encryption keys which were wrapped
+//(encrypted) by the transit secrets engine of a HashiCorp Vault. The keys are unwrapped by Vault when they are loaded.
+type VaultKeyProvider struct {
+	config      *VaultConfig
+	keyFile     string
+	oldKeyFiles []string
+	client      *http.Client
+}
+
+func NewVaultKeyProvider(config *VaultConfig, keyFile string, oldKeyFiles []string) (*VaultKeyProvider, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid Vault configuration")
+	}
+	return &VaultKeyProvider{
+		config:      config,
+		keyFile:     keyFile,
+		oldKeyFiles: oldKeyFiles,
+		client:      &http.Client{Timeout: vaultRequestTimeout},
+	}, nil
+}
+
+func (p *VaultKeyProvider) EncryptionKey() (string, error) {
+	return p.unwrapKeyFile(p.keyFile)
+}
+
+func (p *VaultKeyProvider) DecryptionKeys() ([]string, error) {
+	var keys []string
+	for _, oldKeyFile := range p.oldKeyFiles {
+		key, err := p.unwrapKeyFile(oldKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+//WrapKey encrypts the data encryption key with the transit key
+func (p *VaultKeyProvider) WrapKey(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	err := p.transit("encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString([]byte(key)),
+	}, &resp)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to wrap encryption key")
+	}
+	return resp.Data.Ciphertext, nil
+}
+
+func (p *VaultKeyProvider) unwrapKeyFile(keyFile string) (string, error) {
+	if !file.Exists(keyFile) {
+		return "", fmt.Errorf("encryption key file '%s' not found", keyFile)
+	}
+	wrappedKey, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to read encryption key file '%s'", keyFile))
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	err = p.transit("decrypt", map[string]string{
+		"ciphertext": strings.TrimSpace(string(wrappedKey)),
+	}, &resp)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to unwrap encryption key of file '%s'", keyFile))
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return "", errors.Wrap(err, "unwrapped encryption key is not base64 encoded")
+	}
+	if err := validateKey(string(key)); err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+//transit sends a request to an endpoint of the transit secrets engine
+func (p *VaultKeyProvider) transit(endpoint string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(p.config.Address, "/"),
+		strings.Trim(p.config.TransitMount, "/"), endpoint, p.config.KeyName)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", p.config.Token)
+	if p.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			return fmt.Errorf("Vault responded with status %d: %s", resp.StatusCode, strings.Join(vaultErr.Errors, ", "))
+		}
+		return fmt.Errorf("Vault responded with status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(respBody, result)
+}
@@ -2,11 +2,14 @@ package interpreter
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/traefik/yaegi/interp"
 	"github.com/traefik/yaegi/stdlib"
@@ -16,9 +19,15 @@ const allowedPackages = "fmt|regexp|net/url|strings|time|strconv"
 
 var regExp = regexp.MustCompile(fmt.Sprintf(`^import\s+"(%s)"$`, allowedPackages))
 
+//bindingTimePkg is the alias of the time package used by bindings of durations
+const bindingTimePkg = "bindingtime"
+
+var allowedPackagesPattern = regexp.MustCompile(fmt.Sprintf(`^(%s)$`, allowedPackages))
+
 type GolangInterpreter struct {
 	code     string
 	bindings map[string]interface{}
+	timeout  time.Duration
 }
 
 func NewGolangInterpreter(code string) *GolangInterpreter {
@@ -31,16 +40,29 @@ func (gi *GolangInterpreter) WithBindings(bindings map[string]interface{}) *Gola
 	if gi.bindings == nil {
 		gi.bindings = bindings
 	} else {
-		for k, v := range gi.bindings {
+		for k, v := range bindings {
 			gi.bindings[k] = v
 		}
 	}
 	return gi
 }
 
+//WithTimeout limits the execution time of the code: its evaluation is cancelled if the timeout is exceeded
+func (gi *GolangInterpreter) WithTimeout(timeout time.Duration) *GolangInterpreter {
+	gi.timeout = timeout
+	return gi
+}
+
 func (gi *GolangInterpreter) Eval() (reflect.Value, error) {
 	interp := interp.New(interp.Options{})
-	interp.Use(stdlib.Symbols)
+	interp.Use(allowedSymbols())
+
+	ctx := context.Background()
+	if gi.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gi.timeout)
+		defer cancel()
+	}
 
 	var lastResult reflect.Value
 	var err error
@@ -53,14 +75,17 @@ func (gi *GolangInterpreter) Eval() (reflect.Value, error) {
 	//execute the code
 	scanner := bufio.NewScanner(strings.NewReader(gi.code))
 	for scanner.Scan() {
-		line := scanner.Text()
+		line := strings.TrimSpace(scanner.Text())
 
 		//block execution of non-whitelisted imports
 		if strings.HasPrefix(line, "import") && !regExp.MatchString(line) {
 			return lastResult, &BlockedImportError{BlockedImport: line}
 		}
 
-		lastResult, err = interp.Eval(line)
+		lastResult, err = interp.EvalWithContext(ctx, line)
+		if err == context.DeadlineExceeded {
+			return lastResult, &TimeoutError{Timeout: gi.timeout}
+		}
 		if err != nil {
 			return lastResult, fmt.Errorf("Go interpreter failed to execute line '%s':\n%s", line, err.Error())
 		}
@@ -104,10 +129,11 @@ func (gi *GolangInterpreter) bind(interp *interp.Interpreter, bindings map[strin
 	}
 
 	var err error
+	timeImported := false
 	for k, v := range bindings {
-		switch v.(type) {
+		switch value := v.(type) {
 		case string:
-			_, err = interp.Eval(fmt.Sprintf(`var %s string = "%s"`, k, v))
+			_, err = interp.Eval(fmt.Sprintf(`var %s string = %q`, k, v))
 		case bool:
 			_, err = interp.Eval(fmt.Sprintf(`var %s bool = %t`, k, v))
 		case int:
@@ -118,12 +144,85 @@ func (gi *GolangInterpreter) bind(interp *interp.Interpreter, bindings map[strin
 			_, err = interp.Eval(fmt.Sprintf(`var %s float32 = %f`, k, v))
 		case float64:
 			_, err = interp.Eval(fmt.Sprintf(`var %s float64 = %f`, k, v))
+		case time.Duration:
+			//time package is imported with an alias to avoid a conflict with imports of the code
+			if !timeImported {
+				_, err = interp.Eval(fmt.Sprintf(`import %s "time"`, bindingTimePkg))
+				timeImported = err == nil
+			}
+			if err == nil {
+				_, err = interp.Eval(fmt.Sprintf(`var %s %s.Duration = %d`, k, bindingTimePkg, value))
+			}
+		case map[string]interface{}, []interface{}:
+			var literal string
+			literal, err = goLiteral(value)
+			if err == nil {
+				_, err = interp.Eval(fmt.Sprintf(`var %s = %s`, k, literal))
+			}
 		default:
 			err = fmt.Errorf("Cannot bind key '%s' because value of type '%T' is not supported", k, v)
 		}
+		if err != nil {
+			return err
+		}
 	}
 
-	return err
+	return nil
+}
+
+//goLiteral returns the Go expression of a value: maps and slices can contain the types of unmarshalled JSON documents
+func goLiteral(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return fmt.Sprintf("%q", value), nil
+	case bool:
+		return fmt.Sprintf("%t", value), nil
+	case int, int64:
+		return fmt.Sprintf("%T(%d)", value, value), nil
+	case float64:
+		return fmt.Sprintf("float64(%s)", strconv.FormatFloat(value, 'g', -1, 64)), nil
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			literal, err := goLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, literal)
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(items, ", ")), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		entries := make([]string, 0, len(value))
+		for _, key := range keys {
+			literal, err := goLiteral(value[key])
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, fmt.Sprintf("%q: %s", key, literal))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(entries, ", ")), nil
+	default:
+		return "", fmt.Errorf("value of type '%T' cannot be converted into a Go expression", v)
+	}
+}
+
+//allowedSymbols returns the symbols of the allowed standard library packages: code cannot use other packages
+//even if an import statement passes the import check
+func allowedSymbols() interp.Exports {
+	symbols := make(interp.Exports)
+	for pkg, pkgSymbols := range stdlib.Symbols {
+		if allowedPackagesPattern.MatchString(pkg) {
+			symbols[pkg] = pkgSymbols
+		}
+	}
+	return symbols
 }
 
 type BlockedImportError struct {
@@ -138,6 +237,18 @@ func IsBlockedImportError(err error) bool {
 	return reflect.TypeOf(err) == reflect.TypeOf(&BlockedImportError{})
 }
 
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("Go interpreter cancelled the execution because it exceeded the timeout of %s", e.Timeout)
+}
+
+func IsTimeoutError(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(&TimeoutError{})
+}
+
 type NoBooleanResultError struct {
 	Result interface{}
 }
@@ -3,6 +3,7 @@ package interpreter
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -100,4 +101,55 @@ os.GetEnv("ABC")
 		require.True(t, IsBlockedImportError(err))
 	})
 
+	t.Run("Block denied import (indented)", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+	import "os"
+os.Getenv("ABC")
+`)
+		_, err := goInt.Eval()
+		require.Error(t, err)
+		require.True(t, IsBlockedImportError(err))
+	})
+
+	t.Run("Bind strings with quotes", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+x + y
+`).WithBindings(map[string]interface{}{"x": `say "hello"`}).WithBindings(map[string]interface{}{"y": "!"})
+		result, err := goInt.EvalString()
+		require.NoError(t, err)
+		require.Equal(t, `say "hello"!`, result)
+	})
+
+	t.Run("Cancel execution after timeout", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+for {}
+`).WithTimeout(100 * time.Millisecond)
+		_, err := goInt.Eval()
+		require.Error(t, err)
+		require.True(t, IsTimeoutError(err))
+	})
+
+	t.Run("Bind durations", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+import "time"
+x > time.Minute && y < x
+`).WithBindings(map[string]interface{}{"x": 90 * time.Second, "y": time.Second})
+		result, err := goInt.EvalBool()
+		require.NoError(t, err)
+		require.True(t, result)
+	})
+
+	t.Run("Bind maps and lists", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+import "fmt"
+fmt.Sprintf("%s|%v|%d", x["a"].(map[string]interface{})["b"], x["c"].(float64)+1, len(y))
+`).WithBindings(map[string]interface{}{
+			"x": map[string]interface{}{"a": map[string]interface{}{"b": `say "hi"`}, "c": 1.5, "d": nil},
+			"y": []interface{}{"a", true, int64(1), []interface{}{}},
+		})
+		result, err := goInt.EvalString()
+		require.NoError(t, err)
+		require.Equal(t, `say "hi"|2.5|4`, result)
+	})
+
 }
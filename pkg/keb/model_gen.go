@@ -57,6 +57,15 @@ type HTTPClusterStatusResponse struct {
 	StatusChanges []StatusChange `json:"statusChanges"`
 }
 
+// HTTPComponentValuesResponse defines model for HTTPComponentValuesResponse.
+type HTTPComponentValuesResponse struct {
+	Component string                 `json:"component"`
+	Landscape string                 `json:"landscape"`
+	Profile   string                 `json:"profile"`
+	Values    map[string]interface{} `json:"values"`
+	Version   string                 `json:"version"`
+}
+
 // HTTPErrorResponse defines model for HTTPErrorResponse.
 type HTTPErrorResponse struct {
 	Error string `json:"error"`
@@ -77,6 +86,12 @@ type HTTPReconciliationInfo struct {
 	Updated       time.Time   `json:"updated"`
 }
 
+// HTTPValuesValidationErrorResponse defines model for HTTPValuesValidationErrorResponse.
+type HTTPValuesValidationErrorResponse struct {
+	Error      string             `json:"error"`
+	Violations *[]ValuesViolation `json:"violations,omitempty"`
+}
+
 // Cluster defines model for cluster.
 type Cluster struct {
 	// valid kubeconfig to cluster
@@ -222,9 +237,19 @@ type StatusUpdate struct {
 	Status Status `json:"status"`
 }
 
+// ValuesViolation defines model for valuesViolation.
+type ValuesViolation struct {
+	Component string `json:"component"`
+	Message   string `json:"message"`
+	Path      string `json:"path"`
+}
+
 // BadRequest defines model for BadRequest.
 type BadRequest HTTPErrorResponse
 
+// Conflict defines model for Conflict.
+type Conflict HTTPErrorResponse
+
 // InternalError defines model for InternalError.
 type InternalError HTTPErrorResponse
 
@@ -240,15 +265,28 @@ type ReconcilationsOKResponse HTTPReconcilerStatus
 // ReconciliationInfoOKResponse defines model for ReconciliationInfoOKResponse.
 type ReconciliationInfoOKResponse HTTPReconciliationInfo
 
+// ValuesValidationBadRequest defines model for ValuesValidationBadRequest.
+type ValuesValidationBadRequest HTTPValuesValidationErrorResponse
+
 // ConfigurationOkResponse defines model for configurationOkResponse.
 type ConfigurationOkResponse HTTPClusterConfig
 
 // PostClustersJSONBody defines parameters for PostClusters.
 type PostClustersJSONBody Cluster
 
+// PostClustersParams defines parameters for PostClusters.
+type PostClustersParams struct {
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
 // PutClustersJSONBody defines parameters for PutClusters.
 type PutClustersJSONBody Cluster
 
+// PutClustersParams defines parameters for PutClusters.
+type PutClustersParams struct {
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
 // GetClustersStateParams defines parameters for GetClustersState.
 type GetClustersStateParams struct {
 	RuntimeID     *string `json:"runtimeID,omitempty"`
@@ -256,9 +294,19 @@ type GetClustersStateParams struct {
 	CorrelationID *string `json:"correlationID,omitempty"`
 }
 
+// DeleteClustersRuntimeIDParams defines parameters for DeleteClustersRuntimeID.
+type DeleteClustersRuntimeIDParams struct {
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
 // PutClustersRuntimeIDStatusJSONBody defines parameters for PutClustersRuntimeIDStatus.
 type PutClustersRuntimeIDStatusJSONBody StatusUpdate
 
+// PutClustersRuntimeIDStatusParams defines parameters for PutClustersRuntimeIDStatus.
+type PutClustersRuntimeIDStatusParams struct {
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
 // PostOperationsSchedulingIDCorrelationIDStopJSONBody defines parameters for PostOperationsSchedulingIDCorrelationIDStop.
 type PostOperationsSchedulingIDCorrelationIDStopJSONBody OperationStop
 
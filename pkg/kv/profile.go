@@ -0,0 +1,55 @@
+package kv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/pkg/errors"
+)
+
+const profileBucketPrefix = "profile"
+
+//ProfileBuckets returns the names of the buckets which contain the injected values of a profile: the bucket
+//of the profile ('profile-<profile>') followed by the landscape specific bucket ('profile-<profile>-<landscape>')
+func ProfileBuckets(profile, landscape string) []string {
+	if profile == "" {
+		return nil
+	}
+	profileBucket := fmt.Sprintf("%s-%s", profileBucketPrefix, strings.ToLower(profile))
+	buckets := []string{profileBucket}
+	if landscape != "" {
+		buckets = append(buckets, fmt.Sprintf("%s-%s", profileBucket, strings.ToLower(landscape)))
+	}
+	return buckets
+}
+
+//ProfileValues returns the values stored in the profile buckets for a component. Keys of these values are
+//prefixed with the component name (e.g. 'istio.helmValues.replicas'): the prefix is stripped from the returned
+//keys. Values of the landscape specific bucket override the values of the profile bucket.
+func (cer *Repository) ProfileValues(component, profile, landscape string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	keyPrefix := component + "."
+	for _, bucket := range ProfileBuckets(profile, landscape) {
+		if model.ValidateBucketName(bucket) != nil {
+			cer.Logger.Debugf("Skipping profile bucket '%s' because its name is not a valid bucket name", bucket)
+			continue
+		}
+		values, err := cer.ValuesByBucket(bucket)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to retrieve values of profile bucket '%s'", bucket))
+		}
+		for _, value := range values {
+			if !strings.HasPrefix(value.Key, keyPrefix) {
+				continue
+			}
+			typedValue, err := value.ConfigurationValue()
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to convert value of key '%s' in profile bucket '%s'",
+					value.Key, bucket))
+			}
+			result[strings.TrimPrefix(value.Key, keyPrefix)] = typedValue
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileBuckets(t *testing.T) {
+	require.Empty(t, ProfileBuckets("", "prod"))
+	require.Equal(t, []string{"profile-evaluation"}, ProfileBuckets("evaluation", ""))
+	require.Equal(t, []string{"profile-production", "profile-production-prod"}, ProfileBuckets("Production", "prod"))
+}
+
+func TestRepositoryProfileValues(t *testing.T) {
+	ceRepo := newKeyValueRepo(t)
+
+	//use unique component and profile names to avoid collisions with other test data
+	suffix := time.Now().UnixNano()
+	component := fmt.Sprintf("comp%d", suffix)
+	profile := fmt.Sprintf("prof%d", suffix)
+
+	createValue := func(bucket, key string, dataType model.DataType, value string) {
+		keyEntity, err := ceRepo.CreateKey(&model.KeyEntity{
+			Key:      key,
+			DataType: dataType,
+			Username: "testUsername",
+		})
+		require.NoError(t, err)
+		_, err = ceRepo.CreateValue(&model.ValueEntity{
+			Key:        keyEntity.Key,
+			KeyVersion: keyEntity.Version,
+			Bucket:     bucket,
+			DataType:   dataType,
+			Username:   "testUsername",
+			Value:      value,
+		})
+		require.NoError(t, err)
+	}
+
+	profileBucket := fmt.Sprintf("profile-%s", profile)
+	landscapeBucket := fmt.Sprintf("profile-%s-prod", profile)
+	createValue(profileBucket, component+".replicas", model.Integer, "2")
+	createValue(profileBucket, component+".image.tag", model.String, "1.0.0")
+	createValue(profileBucket, component+".timeout", model.Duration, "90m")
+	createValue(profileBucket, "other"+component+".replicas", model.Integer, "5")
+	createValue(landscapeBucket, component+".image.tag", model.String, "2.0.0")
+
+	defer func() {
+		require.NoError(t, ceRepo.DeleteBucket(profileBucket))
+		require.NoError(t, ceRepo.DeleteBucket(landscapeBucket))
+	}()
+
+	t.Run("Without profile", func(t *testing.T) {
+		values, err := ceRepo.ProfileValues(component, "", "prod")
+		require.NoError(t, err)
+		require.Empty(t, values)
+	})
+
+	t.Run("Profile values", func(t *testing.T) {
+		values, err := ceRepo.ProfileValues(component, profile, "")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"replicas":  int64(2),
+			"image.tag": "1.0.0",
+			"timeout":   "1h30m0s", //durations are passed as strings to the charts
+		}, values)
+	})
+
+	t.Run("Landscape values override profile values", func(t *testing.T) {
+		values, err := ceRepo.ProfileValues(component, profile, "prod")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"replicas":  int64(2),
+			"image.tag": "2.0.0",
+			"timeout":   "1h30m0s",
+		}, values)
+	})
+}
@@ -147,7 +147,7 @@ func (cer *Repository) DeleteKey(key string) error {
 	//bundle DB operations
 	dbOps := func(tx *db.TxConnection) error {
 		//delete all cache entities which were using a value of this key
-		if err := cer.CacheDep.Invalidate().WithKey(key).Exec(tx); err != nil {
+		if err := cer.CacheDep.Invalidate().WithKey(key).WithReconciliation().Exec(tx); err != nil {
 			return err
 		}
 
@@ -362,7 +362,11 @@ func (cer *Repository) CreateValue(value *model.ValueEntity) (*model.ValueEntity
 		}
 
 		//new value provided - invalidate caches which were using the old value
-		if err := cer.CacheDep.Invalidate().WithBucket(value.Bucket).WithKey(value.Key).Exec(tx); err != nil {
+		if err := cer.CacheDep.Invalidate().WithBucket(value.Bucket).WithKey(value.Key).WithReconciliation().Exec(tx); err != nil {
+			return valueEntity, err
+		}
+		//...and caches which depend on all values of the bucket
+		if err := cer.CacheDep.Invalidate().WithBucket(value.Bucket).WithKey(model.CacheDependencyAnyKey).WithReconciliation().Exec(tx); err != nil {
 			return valueEntity, err
 		}
 
@@ -383,7 +387,7 @@ func (cer *Repository) DeleteValue(key, bucket string) error {
 	//bundle DB operations
 	dbOps := func(tx *db.TxConnection) error {
 		//delete all cache entities which were using a value of this key in this bucket
-		if err := cer.CacheDep.Invalidate().WithKey(key).WithBucket(bucket).Exec(tx); err != nil {
+		if err := cer.CacheDep.Invalidate().WithKey(key).WithBucket(bucket).WithReconciliation().Exec(tx); err != nil {
 			return err
 		}
 
@@ -461,7 +465,7 @@ func (cer *Repository) bucketNames() ([]string, error) {
 func (cer *Repository) DeleteBucket(bucket string) error {
 	dbOps := func(tx *db.TxConnection) error {
 		//invalidate all cache entities which were using values from this bucket
-		if err := cer.CacheDep.Invalidate().WithBucket(bucket).Exec(tx); err != nil {
+		if err := cer.CacheDep.Invalidate().WithBucket(bucket).WithReconciliation().Exec(tx); err != nil {
 			return err
 		}
 
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/retention"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RetentionCollector provides the following metrics about the retention runs:
+// - reconciler_retention_deleted_rows_total{"category", "table"} - rows deleted (and archived) by the retention
+// - reconciler_retention_archived_bytes_total{"category"} - size of the compressed archives
+// - reconciler_retention_runs_total{"result"} - retention runs by result ('success' or 'failure')
+// - reconciler_retention_last_run_duration_seconds - duration of the last retention run
+// - reconciler_retention_last_success_timestamp_seconds - unix time of the last successful retention run
+type RetentionCollector struct {
+	deletedRowsCounter   *prometheus.CounterVec
+	archivedBytesCounter *prometheus.CounterVec
+	runsCounter          *prometheus.CounterVec
+	lastDurationGauge    prometheus.Gauge
+	lastSuccessGauge     prometheus.Gauge
+}
+
+func NewRetentionCollector() *RetentionCollector {
+	collector := &RetentionCollector{
+		deletedRowsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: prometheusSubsystem,
+			Name:      "retention_deleted_rows_total",
+			Help:      "Rows deleted by the retention",
+		}, []string{"category", "table"}),
+		archivedBytesCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: prometheusSubsystem,
+			Name:      "retention_archived_bytes_total",
+			Help:      "Size of the compressed archives of deleted rows",
+		}, []string{"category"}),
+		runsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: prometheusSubsystem,
+			Name:      "retention_runs_total",
+			Help:      "Retention runs by result",
+		}, []string{"result"}),
+		lastDurationGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: prometheusSubsystem,
+			Name:      "retention_last_run_duration_seconds",
+			Help:      "Duration of the last retention run",
+		}),
+		lastSuccessGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: prometheusSubsystem,
+			Name:      "retention_last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful retention run",
+		}),
+	}
+	prometheus.MustRegister(collector)
+	return collector
+}
+
+func (c *RetentionCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.deletedRowsCounter.Describe(ch)
+	c.archivedBytesCounter.Describe(ch)
+	c.runsCounter.Describe(ch)
+	c.lastDurationGauge.Describe(ch)
+	c.lastSuccessGauge.Describe(ch)
+}
+
+func (c *RetentionCollector) Collect(ch chan<- prometheus.Metric) {
+	c.deletedRowsCounter.Collect(ch)
+	c.archivedBytesCounter.Collect(ch)
+	c.runsCounter.Collect(ch)
+	c.lastDurationGauge.Collect(ch)
+	c.lastSuccessGauge.Collect(ch)
+}
+
+func (c *RetentionCollector) OnRetentionRun(result *retention.Result) {
+	for _, table := range result.Tables {
+		c.deletedRowsCounter.WithLabelValues(table.Category, table.Table).Add(float64(table.Deleted))
+		c.archivedBytesCounter.WithLabelValues(table.Category).Add(float64(table.ArchivedBytes))
+	}
+	c.lastDurationGauge.Set(result.Duration.Seconds())
+	if result.Err == nil {
+		c.runsCounter.WithLabelValues("success").Inc()
+		c.lastSuccessGauge.Set(float64(result.Started.Unix()))
+	} else {
+		c.runsCounter.WithLabelValues("failure").Inc()
+	}
+}
@@ -9,6 +9,10 @@ import (
 
 const tblCacheDeps string = "config_cachedeps"
 
+//CacheDependencyAnyKey is used as key of a cache dependency which depends on all values of a bucket
+//(also on values which get added to the bucket later)
+const CacheDependencyAnyKey = "*"
+
 type CacheDependencyEntity struct {
 	Bucket    string    `db:"notNull"`
 	Key       string    `db:"notNull"`
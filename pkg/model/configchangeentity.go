@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+)
+
+const tblConfigChanges string = "config_changes"
+
+//ConfigChangeEntity marks a cluster whose configuration was changed (e.g. by a modified KV value)
+//and which has to be reconciled
+type ConfigChangeEntity struct {
+	ID        int64     `db:"readOnly"`
+	RuntimeID string    `db:"notNull"`
+	Created   time.Time `db:"readOnly"`
+}
+
+func (cc *ConfigChangeEntity) String() string {
+	return fmt.Sprintf("ConfigChangeEntity [ID=%d,RuntimeID=%s,Created=%s]", cc.ID, cc.RuntimeID, cc.Created)
+}
+
+func (cc *ConfigChangeEntity) New() db.DatabaseEntity {
+	return &ConfigChangeEntity{}
+}
+
+func (cc *ConfigChangeEntity) Marshaller() *db.EntityMarshaller {
+	marshaller := db.NewEntityMarshaller(&cc)
+	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
+	return marshaller
+}
+
+func (cc *ConfigChangeEntity) Table() string {
+	return tblConfigChanges
+}
+
+func (cc *ConfigChangeEntity) Equal(other db.DatabaseEntity) bool {
+	if other == nil {
+		return false
+	}
+	otherChange, ok := other.(*ConfigChangeEntity)
+	if ok {
+		return cc.ID == otherChange.ID
+	}
+	return false
+}
@@ -41,3 +41,22 @@ func convertStringToStatus(value interface{}) (interface{}, error) {
 	return nil, fmt.Errorf("failed to convert value '%s' (kind: %s) for field 'Status' to Status type",
 		value, reflect.TypeOf(value).Kind())
 }
+
+//convertNullableString converts values of nullable text columns (NULL is mapped to an empty string)
+func convertNullableString(value interface{}) (interface{}, error) {
+	if value == nil {
+		return "", nil
+	}
+	if stringValue, ok := value.(string); ok {
+		return stringValue, nil
+	}
+	return nil, fmt.Errorf("failed to convert value '%v' (type: %T) to string", value, value)
+}
+
+func convertStringToTriggerPhase(value interface{}) (interface{}, error) {
+	phase, err := convertNullableString(value)
+	if err != nil {
+		return nil, err
+	}
+	return NewTriggerPhase(phase.(string))
+}
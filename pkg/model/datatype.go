@@ -1,32 +1,48 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
 )
 
 const (
-	String  DataType = "string"
-	Integer DataType = "integer"
-	Boolean DataType = "boolean"
+	String    DataType = "string"
+	Integer   DataType = "integer"
+	Boolean   DataType = "boolean"
+	Float     DataType = "float"
+	Duration  DataType = "duration"
+	List      DataType = "list"
+	JSON      DataType = "json"
+	YAML      DataType = "yaml"
+	SecretRef DataType = "secretRef"
 )
 
+//DataTypes are the supported data types of configuration keys
+var DataTypes = []DataType{String, Integer, Boolean, Float, Duration, List, JSON, YAML, SecretRef}
+
 type DataType string
 
 func NewDataType(dataType string) (DataType, error) {
-	switch strings.ToLower(dataType) {
-	case string(String):
-		return String, nil
-	case string(Integer):
-		return Integer, nil
-	case string(Boolean):
-		return Boolean, nil
-	default:
-		return "", fmt.Errorf("DataType '%s' is not supported", dataType)
+	for _, dt := range DataTypes {
+		if strings.EqualFold(dataType, string(dt)) {
+			return dt, nil
+		}
 	}
+	return "", fmt.Errorf("DataType '%s' is not supported", dataType)
 }
 
+//Get returns the typed value:
+// * float: float64
+// * duration: time.Duration (e.g. '1h30m')
+// * list: []interface{} (JSON array or comma separated strings)
+// * json/yaml: the unmarshalled document (maps are of type map[string]interface{}, numbers of type float64)
+// * secretRef: map with the keys 'namespace' (optional), 'name' and 'key' (e.g. 'kyma-system/my-secret:password')
 func (dt DataType) Get(value string) (interface{}, error) {
 	var err error
 	var typedValue interface{}
@@ -41,12 +57,106 @@ func (dt DataType) Get(value string) (interface{}, error) {
 		if err != nil {
 			return typedValue, dt.fireParseError(value)
 		}
+	case Float:
+		typedValue, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return typedValue, dt.fireParseError(value)
+		}
+	case Duration:
+		typedValue, err = time.ParseDuration(value)
+		if err != nil {
+			return typedValue, dt.fireParseError(value)
+		}
+	case List:
+		typedValue, err = parseList(value)
+		if err != nil {
+			return typedValue, dt.fireParseErrorWithCause(value, err)
+		}
+	case JSON:
+		if err := json.Unmarshal([]byte(value), &typedValue); err != nil {
+			return typedValue, dt.fireParseErrorWithCause(value, err)
+		}
+	case YAML:
+		jsonValue, err := yaml.YAMLToJSON([]byte(value))
+		if err == nil {
+			err = json.Unmarshal(jsonValue, &typedValue)
+		}
+		if err != nil {
+			return typedValue, dt.fireParseErrorWithCause(value, err)
+		}
+	case SecretRef:
+		typedValue, err = parseSecretRef(value)
+		if err != nil {
+			return typedValue, dt.fireParseErrorWithCause(value, err)
+		}
 	default:
 		typedValue = value
 	}
 	return typedValue, nil
 }
 
+//ConfigurationValue returns the typed value as it is merged into the configuration of a component (Helm values).
+//Values which have no representation in a Helm values file are converted (durations become strings like '1h30m0s').
+func (dt DataType) ConfigurationValue(value string) (interface{}, error) {
+	typedValue, err := dt.Get(value)
+	if err != nil {
+		return typedValue, err
+	}
+	if duration, ok := typedValue.(time.Duration); ok {
+		return duration.String(), nil
+	}
+	return typedValue, nil
+}
+
 func (dt DataType) fireParseError(value string) error {
 	return fmt.Errorf("Value '%s' is not compatible with DataType '%s'", value, dt)
 }
+
+func (dt DataType) fireParseErrorWithCause(value string, cause error) error {
+	return fmt.Errorf("Value '%s' is not compatible with DataType '%s': %s", value, dt, cause)
+}
+
+func parseList(value string) ([]interface{}, error) {
+	value = strings.TrimSpace(value)
+	result := []interface{}{}
+	if strings.HasPrefix(value, "[") {
+		err := json.Unmarshal([]byte(value), &result)
+		return result, err
+	}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+//parseSecretRef parses a reference to the key of a Kubernetes secret ('[namespace/]name:key')
+func parseSecretRef(value string) (map[string]interface{}, error) {
+	sepKey := strings.LastIndex(value, ":")
+	if sepKey < 0 {
+		return nil, fmt.Errorf("secret reference has to be in the format '[namespace/]name:key'")
+	}
+	name, key := value[:sepKey], value[sepKey+1:]
+	namespace := ""
+	if sepNs := strings.Index(name, "/"); sepNs >= 0 {
+		namespace, name = name[:sepNs], name[sepNs+1:]
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace '%s': %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid secret name '%s': %s", name, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid secret key '%s': %s", key, strings.Join(errs, ", "))
+	}
+	ref := map[string]interface{}{
+		"name": name,
+		"key":  key,
+	}
+	if namespace != "" {
+		ref["namespace"] = namespace
+	}
+	return ref, nil
+}
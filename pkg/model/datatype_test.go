@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +23,64 @@ func TestDataType(t *testing.T) {
 		dt, err = NewDataType("string")
 		require.NoError(t, err)
 		require.Equal(t, dt, String)
+
+		dt, err = NewDataType("secretref")
+		require.NoError(t, err)
+		require.Equal(t, dt, SecretRef)
+	})
+
+	t.Run("Get typed values", func(t *testing.T) {
+		testCases := []struct {
+			dataType DataType
+			value    string
+			expected interface{}
+		}{
+			{Float, "1.5", 1.5},
+			{Duration, "1h30m", 90 * time.Minute},
+			{List, "a, b,,c", []interface{}{"a", "b", "c"}},
+			{List, `["a", 1]`, []interface{}{"a", float64(1)}},
+			{List, "", []interface{}{}},
+			{JSON, `{"a":{"b":[1,"c"]}}`, map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{float64(1), "c"}}}},
+			{YAML, "a:\n  b:\n  - 1\n  - c", map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{float64(1), "c"}}}},
+			{SecretRef, "my-secret:password", map[string]interface{}{"name": "my-secret", "key": "password"}},
+			{SecretRef, "kyma-system/my-secret:tls.crt", map[string]interface{}{"namespace": "kyma-system", "name": "my-secret", "key": "tls.crt"}},
+		}
+		for _, testCase := range testCases {
+			got, err := testCase.dataType.Get(testCase.value)
+			require.NoError(t, err, "%s: %s", testCase.dataType, testCase.value)
+			require.Equal(t, testCase.expected, got, "%s: %s", testCase.dataType, testCase.value)
+		}
+	})
+
+	t.Run("Reject invalid values", func(t *testing.T) {
+		testCases := []struct {
+			dataType DataType
+			value    string
+		}{
+			{Float, "abc"},
+			{Duration, "10"},
+			{List, "[1,"},
+			{JSON, "{a:1}"},
+			{YAML, "a: [1"},
+			{SecretRef, "my-secret"},
+			{SecretRef, "My_Secret:password"},
+			{SecretRef, "kyma.system/my-secret:password"},
+			{SecretRef, "my-secret:pass/word"},
+		}
+		for _, testCase := range testCases {
+			_, err := testCase.dataType.Get(testCase.value)
+			require.Error(t, err, "%s: %s", testCase.dataType, testCase.value)
+		}
+	})
+
+	t.Run("Get configuration values", func(t *testing.T) {
+		value, err := Duration.ConfigurationValue("90m")
+		require.NoError(t, err)
+		require.Equal(t, "1h30m0s", value)
+
+		value, err = Integer.ConfigurationValue("1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), value)
 	})
 
 }
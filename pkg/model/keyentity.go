@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/db"
@@ -10,15 +11,47 @@ import (
 
 const tblKeys string = "config_keys"
 
+//TriggerPhase defines whether a trigger is executed at the start or at the end of a reconciliation
+type TriggerPhase string
+
+const (
+	TriggerPhaseStart TriggerPhase = "start"
+	TriggerPhaseEnd   TriggerPhase = "end"
+)
+
+func NewTriggerPhase(phase string) (TriggerPhase, error) {
+	switch TriggerPhase(strings.ToLower(phase)) {
+	case TriggerPhaseStart:
+		return TriggerPhaseStart, nil
+	case "", TriggerPhaseEnd: //triggers are executed at the end of a reconciliation by default
+		return TriggerPhaseEnd, nil
+	default:
+		return "", fmt.Errorf("trigger phase '%s' is not supported (supported phases are '%s' and '%s')",
+			phase, TriggerPhaseStart, TriggerPhaseEnd)
+	}
+}
+
 type KeyEntity struct {
-	Key       string   `db:"notNull"`
-	Version   int64    `db:"readOnly"`
-	DataType  DataType `db:"notNull"`
-	Encrypted bool
-	Created   time.Time `db:"readOnly"`
-	Username  string    `db:"notNull"`
-	Validator string
-	Trigger   string
+	Key          string   `db:"notNull"`
+	Version      int64    `db:"readOnly"`
+	DataType     DataType `db:"notNull"`
+	Encrypted    bool
+	Created      time.Time `db:"readOnly"`
+	Username     string    `db:"notNull"`
+	Validator    string
+	Trigger      string
+	TriggerPhase TriggerPhase
+}
+
+//TriggerResult is the outcome of a trigger executed for a reconciliation (stored as JSON in the operations
+//of the reconciliation)
+type TriggerResult struct {
+	Key      string       `json:"key"`
+	Phase    TriggerPhase `json:"phase"`
+	Result   string       `json:"result,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Duration int64        `json:"duration"` //in milliseconds
+	Executed time.Time    `json:"executed"`
 }
 
 func (ke *KeyEntity) Validate(value string) error {
@@ -49,6 +82,25 @@ func (ke *KeyEntity) Validate(value string) error {
 	return nil
 }
 
+//ExecTrigger runs the trigger logic of the key. The bindings are available as variables in the trigger code.
+func (ke *KeyEntity) ExecTrigger(bindings map[string]interface{}, timeout time.Duration) (string, error) {
+	if ke.Trigger == "" {
+		return "", nil
+	}
+	return interpreter.NewGolangInterpreter(ke.Trigger).
+		WithBindings(bindings).
+		WithTimeout(timeout).
+		EvalString()
+}
+
+//EffectiveTriggerPhase returns the phase the trigger is executed in (triggers without phase run at the end)
+func (ke *KeyEntity) EffectiveTriggerPhase() TriggerPhase {
+	if ke.TriggerPhase == "" {
+		return TriggerPhaseEnd
+	}
+	return ke.TriggerPhase
+}
+
 func (ke *KeyEntity) String() string {
 	return fmt.Sprintf("KeyEntity [Key=%s,Version=%d,DataType=%s,Encrypted=%t,User=%s]",
 		ke.Key, ke.Version, ke.DataType, ke.Encrypted, ke.Username)
@@ -61,6 +113,7 @@ func (ke *KeyEntity) New() db.DatabaseEntity {
 func (ke *KeyEntity) Marshaller() *db.EntityMarshaller {
 	marshaller := db.NewEntityMarshaller(&ke)
 	marshaller.AddUnmarshaller("DataType", convertStringToDataType)
+	marshaller.AddUnmarshaller("TriggerPhase", convertStringToTriggerPhase)
 	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
 	return marshaller
 }
@@ -79,7 +132,8 @@ func (ke *KeyEntity) Equal(other db.DatabaseEntity) bool {
 			ke.DataType == otherKey.DataType &&
 			ke.Encrypted == otherKey.Encrypted &&
 			ke.Validator == otherKey.Validator &&
-			ke.Trigger == otherKey.Trigger
+			ke.Trigger == otherKey.Trigger &&
+			ke.EffectiveTriggerPhase() == otherKey.EffectiveTriggerPhase()
 	}
 	return false
 }
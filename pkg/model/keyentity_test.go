@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -69,4 +70,66 @@ func TestKeyEntity(t *testing.T) {
 		require.Error(t, err)
 		require.False(t, IsInvalidValueError(err)) //is code error
 	})
+
+	t.Run("Validate typed values", func(t *testing.T) {
+		testCases := []struct {
+			dataType  DataType
+			validator string
+			value     string
+		}{
+			{Float, `it > 0.5 && it < 1`, "0.75"},
+			{Duration, `import "time"
+it >= time.Minute`, "5m"},
+			{List, `len(it) == 2 && it[1].(string) == "b"`, "a,b"},
+			{JSON, `it["replicas"].(float64) > 1`, `{"replicas": 3}`},
+			{YAML, `len(it["replicas"].([]interface{})) == 1`, "replicas: [3]"},
+			{SecretRef, `it["namespace"].(string) == "kyma-system"`, "kyma-system/my-secret:password"},
+		}
+		for _, testCase := range testCases {
+			key := &KeyEntity{
+				Key:       "Mock",
+				DataType:  testCase.dataType,
+				Validator: testCase.validator,
+			}
+			require.NoError(t, key.Validate(testCase.value), "%s: %s", testCase.dataType, testCase.validator)
+		}
+
+		key := &KeyEntity{
+			Key:       "Mock",
+			DataType:  Duration,
+			Validator: `it < 0`,
+		}
+		err := key.Validate("1s")
+		require.Error(t, err)
+		require.True(t, IsInvalidValueError(err))
+	})
+
+	t.Run("Execute trigger", func(t *testing.T) {
+		key := &KeyEntity{
+			Key:      "Mock",
+			DataType: Integer,
+			Trigger: `import "fmt"
+fmt.Sprintf("%s:%d", runtimeID, value+1)`,
+		}
+		result, err := key.ExecTrigger(map[string]interface{}{"runtimeID": "abc", "value": int64(1)}, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, "abc:2", result)
+	})
+
+	t.Run("Compare trigger phases", func(t *testing.T) {
+		key := &KeyEntity{Key: "Mock", DataType: String, Trigger: `"x"`}
+		require.True(t, key.Equal(&KeyEntity{Key: "Mock", DataType: String, Trigger: `"x"`, TriggerPhase: TriggerPhaseEnd}))
+		require.False(t, key.Equal(&KeyEntity{Key: "Mock", DataType: String, Trigger: `"x"`, TriggerPhase: TriggerPhaseStart}))
+	})
+
+	t.Run("Parse trigger phase", func(t *testing.T) {
+		phase, err := NewTriggerPhase("")
+		require.NoError(t, err)
+		require.Equal(t, TriggerPhaseEnd, phase)
+		phase, err = NewTriggerPhase("Start")
+		require.NoError(t, err)
+		require.Equal(t, TriggerPhaseStart, phase)
+		_, err = NewTriggerPhase("middle")
+		require.Error(t, err)
+	})
 }
@@ -25,6 +25,15 @@ type OperationEntity struct {
 	ProcessingDuration int64          `db:""`
 	Retries            int64          `db:""`
 	RetryID            string         `db:"notNull"`
+	EffectiveConfig    string         `db:""`
+	TriggerResults     string         `db:""`
+}
+
+//EffectiveConfig is the configuration of a component which was sent to its reconciler when an operation
+//was processed (stored as JSON in the operation). Values of secret configuration entries are redacted.
+type EffectiveConfig struct {
+	Buckets       []string               `json:"buckets,omitempty"` //merged KV buckets in their layering order
+	Configuration map[string]interface{} `json:"configuration"`
 }
 
 func (o *OperationEntity) String() string {
@@ -54,6 +63,8 @@ func (o *OperationEntity) Marshaller() *db.EntityMarshaller {
 	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
 	marshaller.AddUnmarshaller("Updated", convertTimestampToTime)
 	marshaller.AddUnmarshaller("PickedUp", convertTimestampToTime)
+	marshaller.AddUnmarshaller("EffectiveConfig", convertNullableString)
+	marshaller.AddUnmarshaller("TriggerResults", convertNullableString)
 	marshaller.AddUnmarshaller("ProcessingDuration", func(value interface{}) (interface{}, error) {
 		if value == nil {
 			return int64(0), nil
@@ -59,6 +59,11 @@ func (ve *ValueEntity) Get() (interface{}, error) {
 	return ve.DataType.Get(ve.Value)
 }
 
+//ConfigurationValue returns the value as it is merged into the configuration of a component
+func (ve *ValueEntity) ConfigurationValue() (interface{}, error) {
+	return ve.DataType.ConfigurationValue(ve.Value)
+}
+
 func convertStringToDataType(value interface{}) (interface{}, error) {
 	return NewDataType(value.(string))
 }
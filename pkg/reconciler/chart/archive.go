@@ -0,0 +1,133 @@
+package chart
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/mholt/archiver/v3"
+)
+
+const (
+	defaultMaxArchiveBytes = 1024 * 1024 * 1024 //1 GiB
+	defaultMaxArchiveFiles = 50000
+)
+
+//archiveLimits protect against archives which expand to an excessive amount of data (e.g. zip bombs)
+type archiveLimits struct {
+	maxBytes int64
+	maxFiles int
+}
+
+var defaultArchiveLimits = archiveLimits{
+	maxBytes: defaultMaxArchiveBytes,
+	maxFiles: defaultMaxArchiveFiles,
+}
+
+//archiveWalker iterates over the entries of an archive
+type archiveWalker func(archiveFile string, walkFn archiver.WalkFunc) error
+
+//unarchive extracts the archive into the destination directory. Entries which would be written outside of the
+//destination directory, links and archives exceeding the limits are rejected. The archive format is detected by
+//the file extension.
+func unarchive(archiveFile, dstDir string, limits archiveLimits) error {
+	return unarchiveWith(archiver.Walk, archiveFile, dstDir, limits)
+}
+
+//unarchiveTarGz extracts a tar.gz archive regardless of its file extension (like unarchive)
+func unarchiveTarGz(archiveFile, dstDir string, limits archiveLimits) error {
+	return unarchiveWith(archiver.NewTarGz().Walk, archiveFile, dstDir, limits)
+}
+
+func unarchiveWith(walk archiveWalker, archiveFile, dstDir string, limits archiveLimits) error {
+	dstDir, err := filepath.Abs(dstDir)
+	if err != nil {
+		return err
+	}
+
+	var files int
+	var bytes int64
+	var walkErr error //archiver.Walk does not preserve the error type
+	err = walk(archiveFile, func(f archiver.File) error {
+		walkErr = func() error {
+			name := archiveEntryName(f)
+			target, err := extractionTarget(dstDir, name)
+			if err != nil {
+				return err
+			}
+
+			if f.IsDir() {
+				return os.MkdirAll(target, 0700)
+			}
+			if !f.Mode().IsRegular() {
+				return fmt.Errorf("archive entry '%s' is not a regular file (mode: %s): "+
+					"links and special files are not supported", name, f.Mode())
+			}
+
+			files++
+			if files > limits.maxFiles {
+				return fmt.Errorf("archive contains more than %d files", limits.maxFiles)
+			}
+			if bytes+f.Size() > limits.maxBytes {
+				return fmt.Errorf("extracted content of archive exceeds %d bytes", limits.maxBytes)
+			}
+
+			written, err := writeArchiveEntry(f, target, limits.maxBytes-bytes)
+			bytes += written
+			return err
+		}()
+		return walkErr
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	return err
+}
+
+func writeArchiveEntry(f archiver.File, target string, maxBytes int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	//the size declared in the archive header can't be trusted: read one byte more than allowed to detect overflows
+	written, err := io.Copy(out, io.LimitReader(f, maxBytes+1))
+	if err != nil {
+		return written, err
+	}
+	if written > maxBytes {
+		return written, fmt.Errorf("extracted content of archive exceeds the size limit while extracting '%s'", f.Name())
+	}
+	return written, out.Close()
+}
+
+//extractionTarget returns the path the archive entry will be extracted to and ensures it is within the destination
+func extractionTarget(dstDir, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("archive entry '%s' has an absolute path", name)
+	}
+	target := filepath.Join(dstDir, name)
+	if target != dstDir && !strings.HasPrefix(target, dstDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry '%s' would be extracted outside of the target directory", name)
+	}
+	return target, nil
+}
+
+//archiveEntryName returns the path of the entry within the archive (archiver.File.Name() returns just the base name).
+//The headers of all supported archive formats (tar, zip, rar) provide the path in the field 'Name'.
+func archiveEntryName(f archiver.File) string {
+	header := reflect.Indirect(reflect.ValueOf(f.Header))
+	if header.Kind() == reflect.Struct {
+		if name := header.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+			return name.String()
+		}
+	}
+	return f.Name()
+}
@@ -0,0 +1,123 @@
+package chart
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveEntry struct {
+	name     string
+	content  string
+	typeflag byte
+	linkname string
+}
+
+func newTarGzArchive(t *testing.T, entries ...archiveEntry) string {
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		typeflag := entry.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Typeflag: typeflag,
+			Linkname: entry.linkname,
+			Mode:     0600,
+			Size:     int64(len(entry.content)),
+		}))
+		_, err := tarWriter.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	archiveFile := filepath.Join(t.TempDir(), "archive.tar.gz")
+	require.NoError(t, os.WriteFile(archiveFile, buffer.Bytes(), 0600))
+	return archiveFile
+}
+
+func TestUnarchive(t *testing.T) {
+	t.Run("Extract tar.gz archive", func(t *testing.T) {
+		archiveFile := newTarGzArchive(t,
+			archiveEntry{name: "chart/", typeflag: tar.TypeDir},
+			archiveEntry{name: "chart/Chart.yaml", content: "name: chart"},
+			archiveEntry{name: "chart/templates/cm.yaml", content: "kind: ConfigMap"})
+		dstDir := t.TempDir()
+		require.NoError(t, unarchive(archiveFile, dstDir, defaultArchiveLimits))
+		require.True(t, file.Exists(filepath.Join(dstDir, "chart", "Chart.yaml")))
+		require.True(t, file.Exists(filepath.Join(dstDir, "chart", "templates", "cm.yaml")))
+	})
+
+	t.Run("Extract zip archive", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		zipWriter := zip.NewWriter(buffer)
+		writer, err := zipWriter.Create("chart/templates/cm.yaml")
+		require.NoError(t, err)
+		_, err = writer.Write([]byte("kind: ConfigMap"))
+		require.NoError(t, err)
+		require.NoError(t, zipWriter.Close())
+		archiveFile := filepath.Join(t.TempDir(), "archive.zip")
+		require.NoError(t, os.WriteFile(archiveFile, buffer.Bytes(), 0600))
+
+		dstDir := t.TempDir()
+		require.NoError(t, unarchive(archiveFile, dstDir, defaultArchiveLimits))
+		require.True(t, file.Exists(filepath.Join(dstDir, "chart", "templates", "cm.yaml")))
+	})
+
+	t.Run("Reject path traversal", func(t *testing.T) {
+		for _, name := range []string{"../evil.yaml", "chart/../../evil.yaml", "/etc/evil.yaml"} {
+			parentDir := t.TempDir()
+			dstDir := filepath.Join(parentDir, "dst")
+			archiveFile := newTarGzArchive(t, archiveEntry{name: name, content: "evil"})
+			require.Error(t, unarchive(archiveFile, dstDir, defaultArchiveLimits), name)
+			require.False(t, file.Exists(filepath.Join(parentDir, "evil.yaml")))
+		}
+	})
+
+	t.Run("Reject links", func(t *testing.T) {
+		archiveFile := newTarGzArchive(t,
+			archiveEntry{name: "chart/link", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"})
+		require.Error(t, unarchive(archiveFile, t.TempDir(), defaultArchiveLimits))
+	})
+
+	t.Run("Reject archives exceeding the limits", func(t *testing.T) {
+		archiveFile := newTarGzArchive(t,
+			archiveEntry{name: "a", content: strings.Repeat("x", 600)},
+			archiveEntry{name: "b", content: strings.Repeat("x", 600)})
+		err := unarchive(archiveFile, t.TempDir(), archiveLimits{maxBytes: 1000, maxFiles: 10})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds 1000 bytes")
+
+		err = unarchive(archiveFile, t.TempDir(), archiveLimits{maxBytes: 2000, maxFiles: 1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "more than 1 files")
+
+		require.NoError(t, unarchive(archiveFile, t.TempDir(), archiveLimits{maxBytes: 2000, maxFiles: 2}))
+	})
+
+	t.Run("Extract OCI layer without file extension", func(t *testing.T) {
+		layerFile := filepath.Join(t.TempDir(), "4f588f31474ebaad")
+		require.NoError(t, os.Rename(newTarGzArchive(t, archiveEntry{name: "chart/Chart.yaml", content: "name: chart"}),
+			layerFile))
+		dstDir := t.TempDir()
+		require.NoError(t, extractOCILayer(layerFile, dstDir))
+		require.True(t, file.Exists(filepath.Join(dstDir, "chart", "Chart.yaml")))
+
+		require.NoError(t, os.Rename(newTarGzArchive(t, archiveEntry{name: "../evil.yaml", content: "evil"}), layerFile))
+		parentDir := t.TempDir()
+		require.Error(t, extractOCILayer(layerFile, filepath.Join(parentDir, "dst")))
+		require.False(t, file.Exists(filepath.Join(parentDir, "evil.yaml")))
+	})
+}
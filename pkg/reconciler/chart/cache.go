@@ -0,0 +1,381 @@
+package chart
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	workspaceTypeKyma      = "kyma"
+	workspaceTypeComponent = "component"
+	cacheTrashDir          = ".trash"
+)
+
+var blobNameRegex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+var (
+	workspaceCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "reconciler",
+		Name:      "workspace_cache_hits_total",
+		Help:      "Number of workspace requests which were served from the local workspace cache",
+	}, []string{"type"})
+	workspaceCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "reconciler",
+		Name:      "workspace_cache_misses_total",
+		Help:      "Number of workspace requests which required a download of the workspace",
+	}, []string{"type"})
+	workspaceCacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: "reconciler",
+		Name:      "workspace_cache_bytes",
+		Help:      "Disk space in bytes used by cached workspaces and OCI blobs",
+	})
+	workspaceCacheWorkspaces = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: "reconciler",
+		Name:      "workspace_cache_workspaces",
+		Help:      "Number of cached workspaces",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(workspaceCacheHits, workspaceCacheMisses, workspaceCacheBytes, workspaceCacheWorkspaces)
+}
+
+//CacheQuota limits the disk usage of the workspace cache (including the blobs of the OCI cache).
+//A value of 0 disables the particular limit.
+type CacheQuota struct {
+	MaxBytes      int64
+	MaxWorkspaces int
+}
+
+func (q CacheQuota) enabled() bool {
+	return q.MaxBytes > 0 || q.MaxWorkspaces > 0
+}
+
+func (q CacheQuota) exceeded(bytes int64, workspaces int) bool {
+	return (q.MaxBytes > 0 && bytes > q.MaxBytes) || (q.MaxWorkspaces > 0 && workspaces > q.MaxWorkspaces)
+}
+
+func (q CacheQuota) String() string {
+	return fmt.Sprintf("maxBytes=%d|maxWorkspaces=%d", q.MaxBytes, q.MaxWorkspaces)
+}
+
+type cacheEntry struct {
+	dir      string
+	size     int64
+	lastUsed time.Time
+	refs     int
+	blob     bool //blob in the OCI cache instead of a workspace
+}
+
+//workspaceCache tracks the workspaces and OCI blobs in the storage directory and evicts the least recently used
+//ones if the quota is exceeded. Workspaces and blobs which are in use (acquired but not released yet) are never
+//evicted. Evicted entries are moved into a trash directory while holding the lock and deleted afterwards.
+type workspaceCache struct {
+	storageDir string
+	quota      CacheQuota
+	logger     *zap.SugaredLogger
+	entries    map[string]*cacheEntry
+	loaded     bool
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+func newWorkspaceCache(storageDir string, quota CacheQuota, logger *zap.SugaredLogger) *workspaceCache {
+	return &workspaceCache{
+		storageDir: storageDir,
+		quota:      quota,
+		logger:     logger,
+		entries:    make(map[string]*cacheEntry),
+		now:        time.Now,
+	}
+}
+
+//acquire marks the workspace as used. The returned function has to be called when the workspace is no longer needed.
+func (c *workspaceCache) acquire(dir string) func() {
+	return c.use(dir, false)
+}
+
+//acquireBlob marks a blob of the OCI cache as used. The returned function has to be called when the blob
+//is no longer needed.
+func (c *workspaceCache) acquireBlob(blobFile string) func() {
+	return c.use(blobFile, true)
+}
+
+func (c *workspaceCache) use(path string, blob bool) func() {
+	c.mu.Lock()
+	trash := c.load()
+	entry, ok := c.entries[path]
+	if !ok {
+		entry = &cacheEntry{dir: path, blob: blob}
+		c.entries[path] = entry
+	}
+	entry.refs++
+	entry.lastUsed = c.now()
+	if blob {
+		if info, err := os.Stat(path); err == nil {
+			entry.size = info.Size()
+		}
+	}
+
+	//persist the last-used timestamp to keep it across restarts
+	if touchFile := c.touchFile(entry); file.Exists(touchFile) {
+		if err := os.Chtimes(touchFile, entry.lastUsed, entry.lastUsed); err != nil {
+			c.logger.Warnf("Failed to update last-used timestamp of '%s': %s", path, err)
+		}
+	}
+	c.mu.Unlock()
+	c.purge(trash)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			entry.refs--
+			trash := c.evict()
+			c.mu.Unlock()
+			c.purge(trash)
+		})
+	}
+}
+
+//hit records that a workspace was served from the cache
+func (c *workspaceCache) hit(wsType string) {
+	workspaceCacheHits.WithLabelValues(wsType).Inc()
+}
+
+//miss records that a workspace has to be downloaded
+func (c *workspaceCache) miss(wsType string) {
+	workspaceCacheMisses.WithLabelValues(wsType).Inc()
+}
+
+//added records that a workspace was downloaded. Least recently used workspaces get evicted if the quota is exceeded.
+func (c *workspaceCache) added(dir string) {
+	size, err := dirSize(dir)
+	if err != nil {
+		c.logger.Warnf("Failed to calculate size of workspace '%s': %s", dir, err)
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[dir]; ok {
+		entry.size = size
+	}
+	trash := c.evict()
+	c.mu.Unlock()
+	c.purge(trash)
+}
+
+//removed drops a workspace which was deleted from the cache
+func (c *workspaceCache) removed(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[dir]; ok && entry.refs == 0 {
+		delete(c.entries, dir)
+	}
+	c.updateMetrics()
+}
+
+//load registers the workspaces and blobs which already exist in the storage directory and returns the
+//entries which have to be deleted (requires lock)
+func (c *workspaceCache) load() []string {
+	if c.loaded {
+		return nil
+	}
+	c.loaded = true
+
+	for _, parentDir := range []string{c.storageDir, filepath.Join(c.storageDir, gitComponentsBaseDir)} {
+		dirs, err := ioutil.ReadDir(parentDir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				c.logger.Warnf("Failed to read workspaces in directory '%s': %s", parentDir, err)
+			}
+			continue
+		}
+		for _, dir := range dirs {
+			wsDir := filepath.Join(parentDir, dir.Name())
+			readyFile, err := os.Stat(filepath.Join(wsDir, wsReadyIndicatorFile))
+			if !dir.IsDir() || err != nil {
+				continue
+			}
+			size, err := dirSize(wsDir)
+			if err != nil {
+				c.logger.Warnf("Failed to calculate size of workspace '%s': %s", wsDir, err)
+			}
+			c.entries[wsDir] = &cacheEntry{
+				dir:      wsDir,
+				size:     size,
+				lastUsed: readyFile.ModTime(),
+			}
+		}
+	}
+
+	blobDir := oci.BlobDir(filepath.Join(c.storageDir, ociCacheDir))
+	blobs, err := ioutil.ReadDir(blobDir)
+	if err != nil && !os.IsNotExist(err) {
+		c.logger.Warnf("Failed to read OCI blobs in directory '%s': %s", blobDir, err)
+	}
+	for _, blob := range blobs {
+		if !blob.Mode().IsRegular() || !blobNameRegex.MatchString(blob.Name()) {
+			continue
+		}
+		blobFile := filepath.Join(blobDir, blob.Name())
+		c.entries[blobFile] = &cacheEntry{
+			dir:      blobFile,
+			size:     blob.Size(),
+			lastUsed: blob.ModTime(),
+			blob:     true,
+		}
+	}
+	c.logger.Debugf("Found %d cached workspaces and blobs in storage directory '%s'", len(c.entries), c.storageDir)
+
+	//leftovers of evictions which were interrupted by a restart
+	var trash []string
+	if leftovers, err := ioutil.ReadDir(c.trashDir()); err == nil {
+		for _, leftover := range leftovers {
+			trash = append(trash, filepath.Join(c.trashDir(), leftover.Name()))
+		}
+	}
+	return append(trash, c.evict()...)
+}
+
+//evict moves the least recently used workspaces and blobs into the trash directory until the quota is fulfilled
+//and returns their new paths. The caller has to delete them by calling purge after releasing the lock (requires lock).
+func (c *workspaceCache) evict() []string {
+	defer c.updateMetrics()
+
+	var candidates []*cacheEntry
+	for path, entry := range c.entries {
+		if entry.refs > 0 {
+			continue
+		}
+		if !c.exists(entry) { //workspace or blob was never created or was deleted
+			delete(c.entries, path)
+			continue
+		}
+		candidates = append(candidates, entry)
+	}
+	if !c.quota.enabled() {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+	})
+
+	var trash []string
+	bytes, workspaces := c.usage()
+	for _, entry := range candidates {
+		if !c.quota.exceeded(bytes, workspaces) {
+			break
+		}
+		if entry.blob && !c.quota.exceeded(bytes, 0) { //evicting blobs doesn't reduce the number of workspaces
+			continue
+		}
+		c.logger.Infof("Evicting '%s' (%d bytes, last used %s) because cache quota is exceeded",
+			entry.dir, entry.size, entry.lastUsed.Format(time.RFC3339))
+		trashPath, err := c.moveToTrash(entry.dir)
+		if err != nil {
+			c.logger.Warnf("Failed to evict '%s': %s", entry.dir, err)
+			continue
+		}
+		trash = append(trash, trashPath)
+		delete(c.entries, entry.dir)
+		bytes -= entry.size
+		if !entry.blob {
+			workspaces--
+		}
+	}
+	if c.quota.exceeded(bytes, workspaces) {
+		c.logger.Warnf("Workspace cache exceeds quota (%s) because remaining workspaces are in use", c.quota)
+	}
+	return trash
+}
+
+//moveToTrash renames the path into the trash directory, which is quick compared to deleting it (requires lock)
+func (c *workspaceCache) moveToTrash(path string) (string, error) {
+	if err := os.MkdirAll(c.trashDir(), 0700); err != nil {
+		return "", err
+	}
+	trashDir, err := ioutil.TempDir(c.trashDir(), "evicted-")
+	if err != nil {
+		return "", err
+	}
+	trashPath := filepath.Join(trashDir, filepath.Base(path))
+	if err := os.Rename(path, trashPath); err != nil {
+		if removeErr := os.Remove(trashDir); removeErr != nil {
+			c.logger.Warnf("Failed to remove trash directory '%s': %s", trashDir, removeErr)
+		}
+		return "", err
+	}
+	return trashDir, nil
+}
+
+//purge deletes evicted workspaces and blobs (must not be called while holding the lock)
+func (c *workspaceCache) purge(trash []string) {
+	for _, path := range trash {
+		if err := os.RemoveAll(path); err != nil {
+			c.logger.Warnf("Failed to delete evicted '%s': %s", path, err)
+		}
+	}
+}
+
+func (c *workspaceCache) trashDir() string {
+	return filepath.Join(c.storageDir, cacheTrashDir)
+}
+
+func (c *workspaceCache) exists(entry *cacheEntry) bool {
+	if entry.blob {
+		return file.Exists(entry.dir)
+	}
+	return file.DirExists(entry.dir)
+}
+
+//touchFile returns the file whose modification time tracks the last usage of the entry
+func (c *workspaceCache) touchFile(entry *cacheEntry) string {
+	if entry.blob {
+		return entry.dir
+	}
+	return filepath.Join(entry.dir, wsReadyIndicatorFile)
+}
+
+//usage returns the bytes of cached workspaces and blobs and the number of cached workspaces (requires lock)
+func (c *workspaceCache) usage() (int64, int) {
+	var bytes int64
+	var workspaces int
+	for _, entry := range c.entries {
+		bytes += entry.size
+		if !entry.blob {
+			workspaces++
+		}
+	}
+	return bytes, workspaces
+}
+
+func (c *workspaceCache) updateMetrics() {
+	bytes, workspaces := c.usage()
+	workspaceCacheBytes.Set(float64(bytes))
+	workspaceCacheWorkspaces.Set(float64(workspaces))
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
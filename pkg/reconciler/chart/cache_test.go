@@ -0,0 +1,169 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci/test"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceCache(t *testing.T) {
+	logger := log.NewLogger(true)
+
+	newWorkspace := func(t *testing.T, storageDir, name string, size int, lastUsed time.Time) string {
+		wsDir := filepath.Join(storageDir, name)
+		require.NoError(t, os.MkdirAll(wsDir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(wsDir, "data"), []byte(strings.Repeat("x", size)), 0600))
+		readyFile := filepath.Join(wsDir, wsReadyIndicatorFile)
+		require.NoError(t, os.WriteFile(readyFile, nil, 0600))
+		require.NoError(t, os.Chtimes(readyFile, lastUsed, lastUsed))
+		return wsDir
+	}
+
+	t.Run("Evict least recently used workspaces on startup", func(t *testing.T) {
+		storageDir := t.TempDir()
+		now := time.Now()
+		ws1 := newWorkspace(t, storageDir, "ws1", 100, now.Add(-3*time.Hour))
+		ws2 := newWorkspace(t, storageDir, "ws2", 100, now.Add(-1*time.Hour))
+		ws3 := newWorkspace(t, storageDir, "ws3", 100, now.Add(-2*time.Hour))
+		baseWs := newWorkspace(t, filepath.Join(storageDir, gitComponentsBaseDir), "base1", 100, now.Add(-4*time.Hour))
+
+		cache := newWorkspaceCache(storageDir, CacheQuota{MaxBytes: 250}, logger)
+		release := cache.acquire(ws2)
+		defer release()
+
+		require.False(t, file.DirExists(baseWs))
+		require.False(t, file.DirExists(ws1))
+		require.True(t, file.DirExists(ws2))
+		require.True(t, file.DirExists(ws3))
+		require.Equal(t, float64(200), testutil.ToFloat64(workspaceCacheBytes))
+		require.Equal(t, float64(2), testutil.ToFloat64(workspaceCacheWorkspaces))
+	})
+
+	t.Run("Never evict workspaces in use", func(t *testing.T) {
+		storageDir := t.TempDir()
+		cache := newWorkspaceCache(storageDir, CacheQuota{MaxWorkspaces: 1}, logger)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		ws1 := filepath.Join(storageDir, "ws1")
+		release1 := cache.acquire(ws1)
+		newWorkspace(t, storageDir, "ws1", 10, now)
+		cache.added(ws1)
+
+		now = now.Add(time.Minute)
+		ws2 := filepath.Join(storageDir, "ws2")
+		release2a := cache.acquire(ws2)
+		release2b := cache.acquire(ws2) //concurrent usage of the same workspace
+		newWorkspace(t, storageDir, "ws2", 10, now)
+		cache.added(ws2)
+
+		//both workspaces are in use
+		require.True(t, file.DirExists(ws1))
+		require.True(t, file.DirExists(ws2))
+
+		//ws1 is least recently used and gets evicted when released
+		release1()
+		release1() //releasing twice has no effect
+		require.False(t, file.DirExists(ws1))
+
+		//ws2 is still in use
+		release2a()
+		require.True(t, file.DirExists(ws2))
+		release2b()
+		require.True(t, file.DirExists(ws2)) //quota is not exceeded
+	})
+
+	t.Run("Count OCI blobs against the byte quota", func(t *testing.T) {
+		storageDir := t.TempDir()
+		now := time.Now()
+		blobDir := oci.BlobDir(filepath.Join(storageDir, ociCacheDir))
+		require.NoError(t, os.MkdirAll(blobDir, 0700))
+		newBlob := func(digest string, size int, lastUsed time.Time) string {
+			blobFile := filepath.Join(blobDir, strings.Repeat(digest, 64))
+			require.NoError(t, os.WriteFile(blobFile, []byte(strings.Repeat("x", size)), 0600))
+			require.NoError(t, os.Chtimes(blobFile, lastUsed, lastUsed))
+			return blobFile
+		}
+		blob1 := newBlob("a", 100, now.Add(-3*time.Hour))
+		blob2 := newBlob("b", 100, now.Add(-1*time.Hour))
+		ws1 := newWorkspace(t, storageDir, "ws1", 100, now.Add(-2*time.Hour))
+
+		//leftover of an interrupted eviction
+		leftover := filepath.Join(storageDir, cacheTrashDir, "evicted-1")
+		require.NoError(t, os.MkdirAll(leftover, 0700))
+
+		cache := newWorkspaceCache(storageDir, CacheQuota{MaxBytes: 250, MaxWorkspaces: 1}, logger)
+		release := cache.acquireBlob(blob2)
+
+		require.False(t, file.Exists(blob1))
+		require.True(t, file.Exists(blob2))
+		require.True(t, file.DirExists(ws1)) //blobs aren't counted as workspaces
+		require.False(t, file.DirExists(leftover))
+		require.Equal(t, float64(200), testutil.ToFloat64(workspaceCacheBytes))
+		require.Equal(t, float64(1), testutil.ToFloat64(workspaceCacheWorkspaces))
+
+		//blob is least recently used when a new workspace is added
+		ws2 := filepath.Join(storageDir, "ws2")
+		releaseWs2 := cache.acquire(ws2)
+		defer releaseWs2()
+		newWorkspace(t, storageDir, "ws2", 200, now)
+		cache.added(ws2)
+		require.False(t, file.DirExists(ws1))
+		require.True(t, file.Exists(blob2)) //in use
+		release()
+		require.False(t, file.Exists(blob2))
+		require.True(t, file.DirExists(ws2))
+
+		trash, err := os.ReadDir(filepath.Join(storageDir, cacheTrashDir))
+		require.NoError(t, err)
+		require.Empty(t, trash)
+	})
+}
+
+func TestFactoryCacheQuota(t *testing.T) {
+	logger := log.NewLogger(true)
+	registry := test.NewRegistry(t)
+	for _, tag := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+		registry.Push(t, "charts/mychart", tag, map[string]string{
+			"mychart/Chart.yaml": "apiVersion: v2\nname: mychart\nversion: " + tag + "\n",
+		})
+	}
+	url := "oci://" + registry.Host() + "/charts/mychart"
+
+	factory := (&DefaultFactory{logger: logger, storageDir: t.TempDir()}).
+		WithCacheQuota(CacheQuota{MaxWorkspaces: 2})
+
+	hits := testutil.ToFloat64(workspaceCacheHits.WithLabelValues(workspaceTypeComponent))
+	misses := testutil.ToFloat64(workspaceCacheMisses.WithLabelValues(workspaceTypeComponent))
+
+	ws1, err := factory.GetExternalComponent(NewComponentBuilder("1.0.0", "mychart").WithURL(url).Build())
+	require.NoError(t, err)
+	ws2, err := factory.GetExternalComponent(NewComponentBuilder("2.0.0", "mychart").WithURL(url).Build())
+	require.NoError(t, err)
+	ws2.Release()
+	ws1Again, err := factory.GetExternalComponent(NewComponentBuilder("1.0.0", "mychart").WithURL(url).Build())
+	require.NoError(t, err)
+	require.Equal(t, ws1.WorkspaceDir, ws1Again.WorkspaceDir)
+	ws1Again.Release()
+
+	//ws2 is the least recently used workspace and gets evicted
+	ws3, err := factory.GetExternalComponent(NewComponentBuilder("3.0.0", "mychart").WithURL(url).Build())
+	require.NoError(t, err)
+	defer ws3.Release()
+	require.False(t, file.DirExists(ws2.WorkspaceDir))
+	require.True(t, file.DirExists(ws1.WorkspaceDir)) //still in use
+	require.True(t, file.DirExists(ws3.WorkspaceDir))
+	ws1.Release()
+
+	require.Equal(t, hits+1, testutil.ToFloat64(workspaceCacheHits.WithLabelValues(workspaceTypeComponent)))
+	require.Equal(t, misses+3, testutil.ToFloat64(workspaceCacheMisses.WithLabelValues(workspaceTypeComponent)))
+}
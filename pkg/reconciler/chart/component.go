@@ -1,6 +1,8 @@
 package chart
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/imdario/mergo"
@@ -12,7 +14,9 @@ type Component struct {
 	name          string
 	profile       string
 	namespace     string
+	landscape     string
 	configuration map[string]interface{}
+	profileValues map[string]interface{}
 }
 
 func (c *Component) isExternalComponent() bool {
@@ -23,10 +27,27 @@ func (c *Component) isExternalGitComponent() bool {
 	return strings.HasSuffix(c.url, ".git")
 }
 
+//tokenNamespace returns the namespace of the secret which contains the credentials for the component URL
+func (c *Component) tokenNamespace() string {
+	tokenNamespace := c.configuration["repo.token.namespace"]
+	if tokenNamespace == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s", tokenNamespace)
+}
+
+//Configuration returns the configuration as nested map. Values which are maps (e.g. JSON or YAML configuration
+//values) are merged with the values of more specific keys (e.g. 'a.b' overrides the field 'b' of the map of key 'a').
 func (c *Component) Configuration() (map[string]interface{}, error) {
+	keys := make([]string, 0, len(c.configuration))
+	for key := range c.configuration {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) //keys are sorted before their more specific keys
+
 	result := make(map[string]interface{})
-	for key, value := range c.configuration {
-		if err := mergo.Merge(&result, c.convertToNestedMap(key, value), mergo.WithOverride); err != nil {
+	for _, key := range keys {
+		if err := mergo.Merge(&result, c.convertToNestedMap(key, c.configuration[key]), mergo.WithOverride); err != nil {
 			return nil, err
 		}
 	}
@@ -41,7 +62,7 @@ func (c *Component) convertToNestedMap(key string, value interface{}) map[string
 	for depth, token := range tokens {
 		switch depth {
 		case len(tokens) - 1: //last token reached, stop nesting
-			lastNestedMap[token] = value
+			lastNestedMap[token] = copyValue(value) //merging must not modify maps of the configuration
 		default:
 			lastNestedMap[token] = make(map[string]interface{})
 			lastNestedMap = lastNestedMap[token].(map[string]interface{})
@@ -50,6 +71,26 @@ func (c *Component) convertToNestedMap(key string, value interface{}) map[string
 	return result
 }
 
+//copyValue returns a deep copy of maps and slices
+func copyValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typedValue))
+		for key, nestedValue := range typedValue {
+			result[key] = copyValue(nestedValue)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, nestedValue := range typedValue {
+			result[i] = copyValue(nestedValue)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
 type ComponentBuilder struct {
 	component *Component
 }
@@ -79,6 +120,18 @@ func (cb *ComponentBuilder) WithConfiguration(config map[string]interface{}) *Co
 	return cb
 }
 
+//WithLandscape sets the landscape whose overlay (landscape-<name>.yaml) is applied on top of the profile values
+func (cb *ComponentBuilder) WithLandscape(landscape string) *ComponentBuilder {
+	cb.component.landscape = landscape
+	return cb
+}
+
+//WithProfileValues sets values which override the profile values of the chart but not the component configuration
+func (cb *ComponentBuilder) WithProfileValues(profileValues map[string]interface{}) *ComponentBuilder {
+	cb.component.profileValues = profileValues
+	return cb
+}
+
 func (cb *ComponentBuilder) WithURL(url string) *ComponentBuilder {
 	cb.component.url = url
 	return cb
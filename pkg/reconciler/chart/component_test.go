@@ -60,4 +60,41 @@ func TestComponent(t *testing.T) {
 		require.Equal(t, expected, got)
 	})
 
+	t.Run("Merge map values with dot-notated configuration keys", func(t *testing.T) {
+		for i := 0; i < 10; i++ { //map iteration order is random
+			mapValue := map[string]interface{}{
+				"subkey1": "test value 1",
+				"subkey2": map[string]interface{}{"a": float64(1)},
+				"subkey3": []interface{}{"x", "y"},
+			}
+			component := NewComponentBuilder("main", "unittest-kyma").
+				WithConfiguration(map[string]interface{}{
+					"test.key1":           mapValue,
+					"test.key1.subkey2.b": "test value 2",
+					"test.key1.subkey3":   []interface{}{"z"},
+				}).
+				Build()
+
+			expected := make(map[string]interface{})
+			err := json.Unmarshal([]byte(`{
+				"test":{
+					"key1":{
+						"subkey1":"test value 1",
+						"subkey2":{
+							"a":1,
+							"b":"test value 2"
+						},
+						"subkey3":["z"]
+					}
+				}
+			}`), &expected)
+			require.NoError(t, err)
+
+			got, err := component.Configuration()
+			require.NoError(t, err)
+			require.Equal(t, expected, got)
+			require.Equal(t, map[string]interface{}{"a": float64(1)}, mapValue["subkey2"]) //value isn't modified
+		}
+	})
+
 }
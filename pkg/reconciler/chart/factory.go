@@ -13,13 +13,14 @@ import (
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
-	"github.com/mholt/archiver/v3"
 	"github.com/otiai10/copy"
 
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/git"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -33,8 +34,13 @@ const (
 	wsReadyIndicatorFile = "workspace-ready.yaml"
 
 	gitComponentsBaseDir = "base"
+
+	//downloadTimeout limits the download of an archive or signature including the response body
+	downloadTimeout = 10 * time.Minute
 )
 
+var downloadClient = &http.Client{Timeout: downloadTimeout}
+
 //go:generate mockery --name=Factory --outpkg=mocks --case=underscore
 // Factory of workspace.
 type Factory interface {
@@ -49,9 +55,14 @@ type Factory interface {
 type DefaultFactory struct {
 	storageDir        string
 	logger            *zap.SugaredLogger
-	mutexGet          sync.Mutex
+	mutex             sync.Mutex             //guards the validation and the version locks
+	versionLocks      map[string]*sync.Mutex //workspaces of different versions can be retrieved concurrently
 	mutexGetComponent sync.Mutex
 	kymaRepository    *reconciler.Repository
+	quota             CacheQuota
+	cache             *workspaceCache
+	cacheOnce         sync.Once
+	trustedKeys       []string
 }
 
 func NewFactory(repo *reconciler.Repository, storageDir string, logger *zap.SugaredLogger) (*DefaultFactory, error) {
@@ -63,6 +74,20 @@ func NewFactory(repo *reconciler.Repository, storageDir string, logger *zap.Suga
 	return factory, factory.validate()
 }
 
+//WithCacheQuota limits the disk usage of the workspaces: least recently used workspaces are evicted if the quota
+//is exceeded. Has to be set before the first workspace is retrieved.
+func (f *DefaultFactory) WithCacheQuota(quota CacheQuota) *DefaultFactory {
+	f.quota = quota
+	return f
+}
+
+//WithTrustedKeys requires a valid signature of one of the given public keys (PGP or PEM encoded) for each
+//downloaded archive. Has to be set before the first workspace is retrieved.
+func (f *DefaultFactory) WithTrustedKeys(keys []string) *DefaultFactory {
+	f.trustedKeys = keys
+	return f
+}
+
 func (f *DefaultFactory) String() string {
 	return fmt.Sprintf("WorkspaceFactory [storageDir=%s]", f.storageDir)
 }
@@ -82,6 +107,30 @@ func (f *DefaultFactory) validate() error {
 	return nil
 }
 
+func (f *DefaultFactory) validateSync() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.validate()
+}
+
+//lockVersion locks the workspace of a Kyma version and returns the unlock function. Callers retrieving
+//a different version are not blocked.
+func (f *DefaultFactory) lockVersion(version string) func() {
+	f.mutex.Lock()
+	if f.versionLocks == nil {
+		f.versionLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := f.versionLocks[version]
+	if !ok {
+		lock = &sync.Mutex{}
+		f.versionLocks[version] = lock
+	}
+	f.mutex.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
 func (f *DefaultFactory) workspaceDir(version string) string {
 	return filepath.Join(f.storageDir, version) //add Kyma version as subdirectory
 }
@@ -99,10 +148,7 @@ func (f *DefaultFactory) defaultStorageDir() string {
 }
 
 func (f *DefaultFactory) Get(version string) (*KymaWorkspace, error) {
-	f.mutexGet.Lock()
-	defer f.mutexGet.Unlock()
-
-	if err := f.validate(); err != nil {
+	if err := f.validateSync(); err != nil {
 		return nil, err
 	}
 
@@ -111,13 +157,29 @@ func (f *DefaultFactory) Get(version string) (*KymaWorkspace, error) {
 		return newKymaWorkspace(f.storageDir)
 	}
 
+	unlock := f.lockVersion(version)
+	defer unlock()
+
 	wsDir := f.workspaceDir(version)
+	release := f.workspaceCache().acquire(wsDir)
 
+	ws, err := f.getKymaWorkspace(version, wsDir)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	ws.release = release
+	return ws, nil
+}
+
+func (f *DefaultFactory) getKymaWorkspace(version, wsDir string) (*KymaWorkspace, error) {
 	wsReadyFile := filepath.Join(wsDir, wsReadyIndicatorFile)
 	if file.Exists(wsReadyFile) {
 		f.logger.Debugf("Workspace '%s' already exists", wsDir)
+		f.workspaceCache().hit(workspaceTypeKyma)
 		return newKymaWorkspace(wsDir)
 	}
+	f.workspaceCache().miss(workspaceTypeKyma)
 
 	if file.DirExists(wsDir) {
 		f.logger.Warnf("Deleting workspace '%s' because previous download does not contain all the required files", wsDir)
@@ -126,9 +188,14 @@ func (f *DefaultFactory) Get(version string) (*KymaWorkspace, error) {
 		}
 	}
 
-	if err := f.clone(version, wsDir, wsDir, f.kymaRepository); err != nil {
+	if oci.IsOCIURL(f.kymaRepository.URL) {
+		if err := f.pullKymaWorkspace(version, wsDir); err != nil {
+			return nil, err
+		}
+	} else if err := f.clone(version, wsDir, wsDir, f.kymaRepository, kymaWorkspaceDirs...); err != nil {
 		return nil, err
 	}
+	f.workspaceCache().added(wsDir)
 
 	return newKymaWorkspace(wsDir)
 }
@@ -145,32 +212,63 @@ func (f *DefaultFactory) GetExternalComponent(component *Component) (*Workspace,
 		return f.getExternalGitComponent(component)
 	}
 
+	if component.isExternalOCIComponent() {
+		return f.getExternalOCIComponent(component)
+	}
+
 	return f.getExternalArchiveComponent(component)
 }
 
+//acquireWorkspace marks the workspace directory as used in the workspace cache and returns the workspace
+//provided by the get function. The returned workspace has to be released by the caller.
+func (f *DefaultFactory) acquireWorkspace(wsDir string, get func() (*Workspace, error)) (*Workspace, error) {
+	release := f.workspaceCache().acquire(wsDir)
+	ws, err := get()
+	if err != nil {
+		release()
+		return nil, err
+	}
+	ws.release = release
+	return ws, nil
+}
+
+func (f *DefaultFactory) workspaceCache() *workspaceCache {
+	f.cacheOnce.Do(func() {
+		f.cache = newWorkspaceCache(f.storageDir, f.quota, f.logger)
+	})
+	return f.cache
+}
+
 func (f *DefaultFactory) getExternalArchiveComponent(component *Component) (*Workspace, error) {
 	wsDir := f.componentBaseDir(component)
 
-	if f.readyMarkerExists(wsDir) {
-		return newComponentWorkspace(wsDir)
-	}
+	return f.acquireWorkspace(wsDir, func() (*Workspace, error) {
+		if f.readyMarkerExists(wsDir) {
+			f.workspaceCache().hit(workspaceTypeComponent)
+			return newComponentWorkspace(wsDir)
+		}
+		f.workspaceCache().miss(workspaceTypeComponent)
 
-	if err := f.cleanFailedWorkspace(wsDir); err != nil {
-		return nil, err
-	}
+		if err := f.cleanFailedWorkspace(wsDir); err != nil {
+			return nil, err
+		}
 
-	f.logger.Infof("Downloading component '%s' with version '%s' from source '%s' into workspace '%s'",
-		component.name, component.version, component.url, wsDir)
+		f.logger.Infof("Downloading component '%s' with version '%s' from source '%s' into workspace '%s'",
+			component.name, component.version, component.url, wsDir)
 
-	if err := f.downloadComponent(component, wsDir); err != nil {
-		return nil, err
-	}
+		if err := f.downloadComponent(component, wsDir); err != nil {
+			return nil, err
+		}
+		f.workspaceCache().added(wsDir)
 
-	return newComponentWorkspace(wsDir)
+		return newComponentWorkspace(wsDir)
+	})
 }
 
 func (f *DefaultFactory) getExternalGitComponent(component *Component) (*Workspace, error) {
 	baseDir := f.componentBaseDir(component)
+	releaseBaseDir := f.workspaceCache().acquire(baseDir)
+	defer releaseBaseDir()
 
 	if f.readyMarkerExists(baseDir) { // already cloned, just fetch
 		if err := f.fetchComponent(component, baseDir); err != nil {
@@ -183,13 +281,29 @@ func (f *DefaultFactory) getExternalGitComponent(component *Component) (*Workspa
 		if err := f.cloneComponent(component, baseDir); err != nil {
 			return nil, err
 		}
+		f.workspaceCache().added(baseDir)
 	}
 
-	wsDir, err := f.copyComponentLatestRev(component, baseDir)
+	rev, err := f.getLatestRevOfVersion(component.version, path.Join(baseDir, component.name))
 	if err != nil {
 		return nil, err
 	}
-	return newComponentWorkspace(wsDir)
+	wsDir := f.workspaceDir(fmt.Sprintf("%s-%s", rev[0:8], component.name))
+
+	return f.acquireWorkspace(wsDir, func() (*Workspace, error) {
+		if f.readyMarkerExists(wsDir) {
+			f.workspaceCache().hit(workspaceTypeComponent)
+			return newComponentWorkspace(wsDir)
+		}
+		f.workspaceCache().miss(workspaceTypeComponent)
+
+		if err := f.copyComponentRev(component, baseDir, wsDir, rev); err != nil {
+			return nil, err
+		}
+		f.workspaceCache().added(wsDir)
+
+		return newComponentWorkspace(wsDir)
+	})
 }
 
 func (f *DefaultFactory) cloneComponent(component *Component, dstDir string) error {
@@ -197,12 +311,8 @@ func (f *DefaultFactory) cloneComponent(component *Component, dstDir string) err
 		component.name, component.version, component.url, dstDir)
 
 	repo := &reconciler.Repository{
-		URL: component.url,
-	}
-
-	tokenNamespace := component.configuration["repo.token.namespace"]
-	if tokenNamespace != nil {
-		repo.TokenNamespace = fmt.Sprintf("%s", tokenNamespace)
+		URL:            component.url,
+		TokenNamespace: component.tokenNamespace(),
 	}
 
 	dstPath := path.Join(dstDir, component.name)
@@ -218,6 +328,12 @@ func (f *DefaultFactory) downloadComponent(component *Component, dstDir string)
 	// TODO consider extracting file to memory
 	tmpFile, err := f.downloadArchive(component.url, dstDir)
 	if err != nil {
+		//remove partially written archives
+		if tmpFile != "" {
+			if removeErr := os.Remove(tmpFile); removeErr != nil {
+				f.logger.Warnf("Unable to remove archive file %q: %s", tmpFile, removeErr)
+			}
+		}
 		return err
 	}
 	defer func() {
@@ -227,11 +343,14 @@ func (f *DefaultFactory) downloadComponent(component *Component, dstDir string)
 		}
 	}()
 
-	err = archiver.Unarchive(tmpFile, dstDir)
-	if err != nil {
+	if err := f.verifyArchive(component, tmpFile); err != nil {
 		return err
 	}
 
+	if err := unarchive(tmpFile, dstDir, defaultArchiveLimits); err != nil {
+		return errors.Wrapf(err, "failed to extract archive '%s' of component '%s'", component.url, component.name)
+	}
+
 	//create a marker file to flag success
 	if err := f.createReadyMarker(dstDir); err != nil {
 		return err
@@ -243,7 +362,7 @@ func (f *DefaultFactory) downloadComponent(component *Component, dstDir string)
 func (f *DefaultFactory) downloadArchive(URL, dstDir string) (string, error) {
 	f.logger.Infof("Downloading archive '%s' into workspace '%s'", URL, dstDir)
 
-	resp, err := http.Get(URL) // #nosec
+	resp, err := downloadClient.Get(URL)
 	if err != nil {
 		return "", err
 	}
@@ -251,11 +370,21 @@ func (f *DefaultFactory) downloadArchive(URL, dstDir string) (string, error) {
 	if resp.StatusCode == 404 {
 		return "", fmt.Errorf("not found: %q", URL)
 	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %q failed with status %d", URL, resp.StatusCode)
+	}
+	if resp.ContentLength > defaultArchiveLimits.maxBytes {
+		return "", fmt.Errorf("archive %q exceeds the maximal size of %d bytes", URL, defaultArchiveLimits.maxBytes)
+	}
+	//read one byte more than allowed to detect archives exceeding the limit
+	body := io.LimitReader(resp.Body, defaultArchiveLimits.maxBytes+1)
 
 	b := make([]byte, 255)
-	if _, err := resp.Body.Read(b); err != nil {
+	n, err := io.ReadFull(body, b)
+	if err != nil && err != io.ErrUnexpectedEOF { //archive can be smaller than the buffer
 		return "", err
 	}
+	b = b[:n]
 
 	mimeType := http.DetectContentType(b)
 	// the extension is required by the archiver
@@ -278,10 +407,13 @@ func (f *DefaultFactory) downloadArchive(URL, dstDir string) (string, error) {
 	// first write bytes used to get the mime type
 	_, err = tmpFile.Write(b)
 	if err != nil {
-		return "", err
+		return tmpFile.Name(), err
 	}
 	// write the rest of the archive
-	_, err = io.Copy(tmpFile, resp.Body)
+	written, err := io.Copy(tmpFile, body)
+	if err == nil && int64(n)+written > defaultArchiveLimits.maxBytes {
+		err = fmt.Errorf("archive %q exceeds the maximal size of %d bytes", URL, defaultArchiveLimits.maxBytes)
+	}
 
 	return tmpFile.Name(), err
 }
@@ -303,7 +435,9 @@ func (f *DefaultFactory) readyFile(dstDir string) string {
 	return filepath.Join(dstDir, wsReadyIndicatorFile)
 }
 
-func (f *DefaultFactory) clone(version string, dstDir string, markerDir string, repo *reconciler.Repository) error {
+//clone checks out the revision of the GIT repository. If directories are passed, a shallow clone is made which
+//contains only these directories.
+func (f *DefaultFactory) clone(version string, dstDir string, markerDir string, repo *reconciler.Repository, sparseDirs ...string) error {
 	f.logger.Infof("Cloning GIT repository '%s' with revision '%s' into workspace '%s'",
 		repo.URL, version, dstDir)
 
@@ -313,6 +447,9 @@ func (f *DefaultFactory) clone(version string, dstDir string, markerDir string,
 	}
 
 	cloner, _ := git.NewCloner(&git.Client{}, repo, true, clientSet, f.logger)
+	if len(sparseDirs) > 0 {
+		cloner.WithShallowClone(sparseDirs...)
+	}
 	if err := cloner.CloneAndCheckout(dstDir, version); err != nil {
 		f.logger.Warnf("Deleting workspace '%s' because GIT clone of repository-URL '%s' with revision '%s' failed",
 			dstDir, repo.URL, version)
@@ -330,15 +467,19 @@ func (f *DefaultFactory) clone(version string, dstDir string, markerDir string,
 }
 
 func (f *DefaultFactory) Delete(version string) error {
-	if err := f.validate(); err != nil {
+	if err := f.validateSync(); err != nil {
 		return err
 	}
+	unlock := f.lockVersion(version)
+	defer unlock()
+
 	wsDir := f.workspaceDir(version)
 	f.logger.Infof("Deleting workspace '%s'", wsDir)
 	err := os.RemoveAll(wsDir)
 	if err != nil {
 		f.logger.Warnf("Failed to delete workspace '%s': %s", wsDir, err)
 	}
+	f.workspaceCache().removed(wsDir)
 	return err
 }
 
@@ -370,12 +511,8 @@ func (f *DefaultFactory) fetchComponent(component *Component, dstDir string) err
 		component.url, dstPath)
 
 	repo := &reconciler.Repository{
-		URL: component.url,
-	}
-
-	tokenNamespace := component.configuration["repo.token.namespace"]
-	if tokenNamespace != nil {
-		repo.TokenNamespace = fmt.Sprintf("%s", tokenNamespace)
+		URL:            component.url,
+		TokenNamespace: component.tokenNamespace(),
 	}
 	clientSet, err := reconcilerK8s.NewInClusterClientSet(f.logger)
 	if err != nil {
@@ -402,47 +539,39 @@ func (f *DefaultFactory) getLatestRevOfVersion(version, path string) (string, er
 	return revision.String(), nil
 }
 
-func (f *DefaultFactory) copyComponentLatestRev(component *Component, baseDir string) (string, error) {
-	rev, err := f.getLatestRevOfVersion(component.version, path.Join(baseDir, component.name))
-	if err != nil {
-		return "", err
-	}
-	wsDir := f.workspaceDir(fmt.Sprintf("%s-%s", rev[0:8], component.name))
-
-	if f.readyMarkerExists(wsDir) {
-		return wsDir, nil
-	}
+func (f *DefaultFactory) copyComponentRev(component *Component, baseDir, wsDir, rev string) error {
 	if err := f.cleanFailedWorkspace(wsDir); err != nil {
-		return "", err
+		return err
 	}
 	destWsDir := path.Join(wsDir, component.name)
 	componentBaseDir := path.Join(baseDir, component.name)
 
-	if err = copy.Copy(componentBaseDir, destWsDir); err != nil {
-		return "", err
+	if err := copy.Copy(componentBaseDir, destWsDir); err != nil {
+		return err
 	}
 	gitClient, err := git.NewClientWithPath(destWsDir)
 	if err != nil {
-		return "", err
+		return err
 	}
 	if err := gitClient.PlainCheckout(&gogit.CheckoutOptions{
 		Hash: plumbing.NewHash(rev),
 	}); err != nil {
-		return "", err
-	}
-	if err := f.createReadyMarker(wsDir); err != nil {
-		return "", err
+		return err
 	}
-
-	return wsDir, nil
+	return f.createReadyMarker(wsDir)
 }
 
+//createReadyMarker flags the workspace as completely downloaded. The marker contains a unique revision which
+//changes whenever the workspace is re-created (used to invalidate cached renderings of the workspace).
 func (f *DefaultFactory) createReadyMarker(wsDir string) error {
 	fileHandler, err := os.Create(f.readyFile(wsDir))
 	if err != nil {
 		return err
 	}
 
+	if _, err := fmt.Fprintf(fileHandler, "revision: \"%d\"\n", time.Now().UnixNano()); err != nil {
+		f.logger.Warnf("Failed to write revision into marker file: %s", err)
+	}
 	if err := fileHandler.Close(); err != nil {
 		f.logger.Warnf("Failed to close marker file: %s", err)
 	}
@@ -355,3 +355,41 @@ func validateWorkspace(fileName, componentName, serverURL string) error {
 
 	return nil
 }
+
+func TestFactoryVersionLocks(t *testing.T) {
+	factory := &DefaultFactory{logger: log.NewLogger(true), storageDir: t.TempDir()}
+
+	unlockV1 := factory.lockVersion("1.0.0")
+
+	//other versions are not blocked
+	lockedV2 := make(chan struct{})
+	go func() {
+		unlock := factory.lockVersion("2.0.0")
+		defer unlock()
+		close(lockedV2)
+	}()
+	select {
+	case <-lockedV2:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "lock of version 2.0.0 is blocked by lock of version 1.0.0")
+	}
+
+	//same version is blocked until it gets unlocked
+	lockedV1 := make(chan struct{})
+	go func() {
+		unlock := factory.lockVersion("1.0.0")
+		defer unlock()
+		close(lockedV1)
+	}()
+	select {
+	case <-lockedV1:
+		require.Fail(t, "lock of version 1.0.0 was acquired twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlockV1()
+	select {
+	case <-lockedV1:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "lock of version 1.0.0 was not released")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
-	"strings"
 
 	"github.com/imdario/mergo"
 	file "github.com/kyma-incubator/reconciler/pkg/files"
@@ -13,7 +12,6 @@ import (
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
-	"helm.sh/helm/v3/pkg/chartutil"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -53,20 +51,32 @@ func (c *HelmClient) getPath(component *Component) (string, error) {
 }
 
 func (c *HelmClient) Render(component *Component) (string, error) {
-	path, err := c.getPath(component)
+	helmChart, config, err := c.loadChart(component)
 	if err != nil {
 		return "", err
 	}
+	return c.template(component, helmChart, config)
+}
+
+//loadChart returns the chart of the component and the configuration used to render it
+func (c *HelmClient) loadChart(component *Component) (*chart.Chart, map[string]interface{}, error) {
+	path, err := c.getPath(component)
+	if err != nil {
+		return nil, nil, err
+	}
 	helmChart, err := loader.Load(path)
 	if err != nil {
-		return "", errors.Wrap(err, "loader failed to load helm chart")
+		return nil, nil, errors.Wrap(err, "loader failed to load helm chart")
 	}
 
 	config, err := c.mergeChartConfiguration(helmChart, component, false)
 	if err != nil {
-		return "", errors.Wrap(err, "client failed to merge chart configuration")
+		return nil, nil, errors.Wrap(err, "client failed to merge chart configuration")
 	}
+	return helmChart, config, nil
+}
 
+func (c *HelmClient) template(component *Component, helmChart *chart.Chart, config map[string]interface{}) (string, error) {
 	tplAction, err := c.newTemplatingAction(component)
 	if err != nil {
 		return "", errors.Wrap(err, "templating action failed")
@@ -119,7 +129,7 @@ func (c *HelmClient) Configuration(component *Component) (map[string]interface{}
 }
 
 func (c *HelmClient) mergeChartConfiguration(chart *chart.Chart, component *Component, withValues bool) (map[string]interface{}, error) {
-	result, err := c.profileConfiguration(chart, component.profile, withValues)
+	result, err := c.defaultConfiguration(chart, component, withValues)
 	if err != nil {
 		return nil, err
 	}
@@ -137,37 +147,65 @@ func (c *HelmClient) mergeChartConfiguration(chart *chart.Chart, component *Comp
 	return result, nil
 }
 
-func (c *HelmClient) profileConfiguration(ch *chart.Chart, profileName string, withValues bool) (map[string]interface{}, error) {
-	var profile *chart.File
-
-	profileNameLC := strings.ToLower(profileName)
-	profileNameWithPrefix := fmt.Sprintf("profile-%s.yaml", profileNameLC)
-	profileNameWithoutPrefix := fmt.Sprintf("%s.yaml", profileNameLC)
+//defaultConfiguration returns the values of the chart before the component configuration is applied:
+//the profile values are overlaid by the landscape specific values and the injected profile values
+func (c *HelmClient) defaultConfiguration(chart *chart.Chart, component *Component, withValues bool) (map[string]interface{}, error) {
+	result, err := c.profileConfiguration(chart, component.profile, withValues)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, f := range ch.Files {
-		if (f.Name == profileNameWithPrefix) || (f.Name == profileNameWithoutPrefix) {
-			profile = f
-			break
+	overlay, err := landscapeOverlay(chart, component.landscape)
+	if err != nil {
+		return nil, err
+	}
+	//injected profile values can use the dot-notation for keys
+	profileValues, err := (&Component{configuration: component.profileValues}).Configuration()
+	if err != nil {
+		return nil, err
+	}
+	for _, values := range []map[string]interface{}{overlay, profileValues} {
+		if len(values) == 0 {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]interface{})
+		}
+		if err := mergo.Merge(&result, values, mergo.WithOverride); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to merge profile configuration with overlays "+
+				"for component '%s'", component.name))
 		}
 	}
 
-	//if no profile file was found, use the values from values.yaml
-	if profile == nil {
-		return ch.Values, nil
-	}
+	return result, nil
+}
 
-	profileValues, err := chartutil.ReadValues(profile.Data)
+//profileConfiguration returns the values of the profile including the values of all profiles it extends
+func (c *HelmClient) profileConfiguration(ch *chart.Chart, profileName string, withValues bool) (map[string]interface{}, error) {
+	chain, err := profileChain(ch, profileName)
+	if err != nil {
+		return nil, err
+	}
+	profileValues, err := mergeProfileChain(ch, chain)
 	if err != nil {
 		return nil, err
 	}
 
+	//if no profile file was found, use the values from values.yaml (the values are copied because the result is
+	//merged with further values and the chart values have to stay unchanged)
+	if profileValues == nil {
+		return copyValue(ch.Values).(map[string]interface{}), nil
+	}
+
 	if withValues {
-		if err := mergo.Merge(&ch.Values, profileValues.AsMap(), mergo.WithOverride); err != nil {
+		result := copyValue(ch.Values).(map[string]interface{})
+		if err := mergo.Merge(&result, profileValues, mergo.WithOverride); err != nil {
 			return nil, errors.Wrap(err, "failed to merge values.yaml with profile configuration")
 		}
-		return ch.Values, nil
+		return result, nil
 	}
 
 	//if a profile file was found, use the values from the <profile>.yaml
 	return profileValues, nil
 }
+
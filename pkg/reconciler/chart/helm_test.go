@@ -96,6 +96,27 @@ func TestHelm(t *testing.T) {
 		require.Equal(t, expected, got)
 	})
 
+	t.Run("Chart values aren't modified by merging configurations", func(t *testing.T) {
+		for _, profile := range []string{"", profileName} {
+			component := NewComponentBuilder("main", componentName).
+				WithNamespace("testNamespace").
+				WithProfile(profile).
+				WithProfileValues(map[string]interface{}{"config.key1": "value1 from profile values"}).
+				WithConfiguration(map[string]interface{}{"config.key2": "value2 from configuration"}).
+				Build()
+
+			helm, err := NewHelmClient(chartDir, logger)
+			require.NoError(t, err)
+
+			helmChart := loadHelmChart(t, component)
+			chartValues := copyValue(helmChart.Values)
+			got, err := helm.mergeChartConfiguration(helmChart, component, true)
+			require.NoError(t, err)
+			require.Equal(t, "value1 from profile values", got["config"].(map[string]interface{})["key1"])
+			require.Equal(t, chartValues, helmChart.Values)
+		}
+	})
+
 	t.Run("Merge chart configuration with empty component configuration", func(t *testing.T) {
 		component := NewComponentBuilder("main", componentName).
 			WithNamespace("testNamespace").
@@ -0,0 +1,441 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/imdario/mergo"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+//ComponentType defines how the sources of a component are rendered
+type ComponentType string
+
+const (
+	ComponentTypeHelm      ComponentType = "helm"
+	ComponentTypeKustomize ComponentType = "kustomize"
+	ComponentTypeManifests ComponentType = "manifests"
+
+	//KustomizePatchesConfigKey defines strategic merge patches which are applied on top of a kustomization
+	KustomizePatchesConfigKey = "kustomize.patches"
+
+	helmChartFile        = "Chart.yaml"
+	defaultValuesFile    = "values.yaml"
+	kustomizeConfigKey   = "kustomize"
+	kustomizeComponentFs = "/component"
+	kustomizeOverlayFs   = "/overlay"
+)
+
+var (
+	//variablePattern matches variables in kustomizations and plain manifests, e.g. '${global.domainName}'
+	variablePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.\-]+)\}`)
+	//scalarPrefixPattern matches the beginning of a line whose value is defined by a variable, e.g. 'key: ' or '- '
+	scalarPrefixPattern = regexp.MustCompile(`(:|^[ \t]*-)[ \t]+$`)
+	//kubernetesObjectPatterns match the mandatory fields of a Kubernetes object
+	kubernetesObjectPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^apiVersion:[ \t]*\S`),
+		regexp.MustCompile(`(?m)^kind:[ \t]*\S`),
+	}
+)
+
+//componentType returns the type of the component sources in the directory
+func componentType(dir string) ComponentType {
+	if fileExists(filepath.Join(dir, helmChartFile)) {
+		return ComponentTypeHelm
+	}
+	for _, kustomizationFile := range konfig.RecognizedKustomizationFileNames() {
+		if fileExists(filepath.Join(dir, kustomizationFile)) {
+			return ComponentTypeKustomize
+		}
+	}
+	return ComponentTypeManifests
+}
+
+//componentDir returns the directory which contains the sources of the component. External components can contain
+//their sources in a sub-directory: charts are preferred over kustomizations, plain manifests are expected in the root.
+func componentDir(baseDir string, component *Component) (string, ComponentType, error) {
+	if !component.isExternalComponent() {
+		dir := filepath.Join(baseDir, component.name)
+		return dir, componentType(dir), nil
+	}
+
+	markers := map[ComponentType][]string{
+		ComponentTypeHelm:      {helmChartFile},
+		ComponentTypeKustomize: konfig.RecognizedKustomizationFileNames(),
+	}
+	for _, compType := range []ComponentType{ComponentTypeHelm, ComponentTypeKustomize} {
+		dir, err := findDirWithFile(baseDir, markers[compType]...)
+		if err != nil {
+			return "", "", err
+		}
+		if dir != "" {
+			return dir, compType, nil
+		}
+	}
+	return baseDir, ComponentTypeManifests, nil
+}
+
+func findDirWithFile(baseDir string, fileNames ...string) (string, error) {
+	var result string
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || result != "" || d.IsDir() {
+			return err
+		}
+		for _, fileName := range fileNames {
+			if d.Name() == fileName {
+				result = filepath.Dir(path)
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+//ManifestClient renders components which are shipped as kustomization or as plain Kubernetes manifests.
+//Variables like '${key}' are substituted with the values of the component: the defaults defined in the
+//'values.yaml' file of the component are overridden by the component configuration.
+type ManifestClient struct {
+	dir           string
+	componentType ComponentType
+	logger        *zap.SugaredLogger
+}
+
+func NewManifestClient(dir string, componentType ComponentType, logger *zap.SugaredLogger) (*ManifestClient, error) {
+	if componentType != ComponentTypeKustomize && componentType != ComponentTypeManifests {
+		return nil, fmt.Errorf("component type '%s' is not supported by the manifest client", componentType)
+	}
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("component directory '%s' not found", dir)
+	}
+	return &ManifestClient{
+		dir:           dir,
+		componentType: componentType,
+		logger:        logger,
+	}, nil
+}
+
+func (c *ManifestClient) Render(component *Component) (string, error) {
+	values, err := c.Configuration(component)
+	if err != nil {
+		return "", err
+	}
+
+	var manifest string
+	if c.componentType == ComponentTypeKustomize {
+		manifest, err = c.kustomize(component)
+	} else {
+		manifest, err = c.readManifests()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err = substituteVariables(manifest, flattenValues(values, ""))
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to render manifests of component '%s'", component.name))
+	}
+	return manifest, nil
+}
+
+//Configuration returns the values of the component used for the variable substitution
+func (c *ManifestClient) Configuration(component *Component) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	valuesFile := filepath.Join(c.dir, defaultValuesFile)
+	if fileExists(valuesFile) {
+		defaults, err := chartutil.ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to read default values of component '%s'", component.name))
+		}
+		result = defaults.AsMap()
+	}
+
+	profileValues, err := (&Component{configuration: component.profileValues}).Configuration()
+	if err != nil {
+		return nil, err
+	}
+	componentConfig, err := chartConfiguration(component)
+	if err != nil {
+		return nil, err
+	}
+	delete(componentConfig, kustomizeConfigKey)
+
+	for _, values := range []map[string]interface{}{profileValues, componentConfig} {
+		if err := mergo.Merge(&result, values, mergo.WithOverride); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to merge configuration of component '%s'", component.name))
+		}
+	}
+	return result, nil
+}
+
+//kustomize builds the kustomization of the component. Patches defined in the component configuration
+//are applied by an overlay which uses the kustomization of the component as base.
+func (c *ManifestClient) kustomize(component *Component) (string, error) {
+	fSys := filesys.MakeFsInMemory()
+	if err := copyToFs(c.dir, kustomizeComponentFs, fSys); err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to load kustomization of component '%s'", component.name))
+	}
+
+	buildDir := kustomizeComponentFs
+	patches, err := kustomizePatches(component)
+	if err != nil {
+		return "", err
+	}
+	if len(patches) > 0 {
+		if err := writeOverlay(fSys, patches); err != nil {
+			return "", err
+		}
+		buildDir = kustomizeOverlayFs
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, buildDir)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to build kustomization of component '%s'", component.name))
+	}
+	manifest, err := resMap.AsYaml()
+	if err != nil {
+		return "", err
+	}
+	return string(manifest), nil
+}
+
+//readManifests concatenates all YAML files of the component which contain Kubernetes objects. Hidden directories
+//(e.g. '.github') and files without Kubernetes objects (e.g. CI configurations) are ignored.
+func (c *ManifestClient) readManifests() (string, error) {
+	var files []string
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != c.dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if (ext == ".yaml" || ext == ".yml") && path != filepath.Join(c.dir, defaultValuesFile) &&
+			d.Name() != wsReadyIndicatorFile {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	var buffer bytes.Buffer
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		if !containsKubernetesObject(data) {
+			c.logger.Debugf("Ignoring file '%s' because it contains no Kubernetes object", file)
+			continue
+		}
+		buffer.WriteString("---\n")
+		buffer.Write(bytes.TrimPrefix(data, []byte("---\n")))
+		buffer.WriteString("\n")
+	}
+	return buffer.String(), nil
+}
+
+func containsKubernetesObject(data []byte) bool {
+	for _, pattern := range kubernetesObjectPatterns {
+		if !pattern.Match(data) {
+			return false
+		}
+	}
+	return true
+}
+
+//kustomizePatches returns the strategic merge patches defined in the component configuration. Patches
+//can be defined as YAML strings or as objects.
+func kustomizePatches(component *Component) ([]string, error) {
+	value, ok := component.configuration[KustomizePatchesConfigKey]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	var patches []string
+	for _, item := range items {
+		if patch, ok := item.(string); ok {
+			patches = append(patches, patch)
+			continue
+		}
+		patch, err := yaml.Marshal(item)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid kustomize patch in configuration of component '%s'",
+				component.name))
+		}
+		patches = append(patches, string(patch))
+	}
+	return patches, nil
+}
+
+func writeOverlay(fSys filesys.FileSystem, patches []string) error {
+	if err := fSys.MkdirAll(kustomizeOverlayFs); err != nil {
+		return err
+	}
+	var patchFiles []string
+	for idx, patch := range patches {
+		patchFile := fmt.Sprintf("patch-%d.yaml", idx)
+		if err := fSys.WriteFile(filepath.Join(kustomizeOverlayFs, patchFile), []byte(patch)); err != nil {
+			return err
+		}
+		patchFiles = append(patchFiles, patchFile)
+	}
+	kustomization, err := yaml.Marshal(map[string]interface{}{
+		"resources":             []string{"../" + filepath.Base(kustomizeComponentFs)},
+		"patchesStrategicMerge": patchFiles,
+	})
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(filepath.Join(kustomizeOverlayFs, konfig.DefaultKustomizationFileName()), kustomization)
+}
+
+func copyToFs(srcDir, dstDir string, fSys filesys.FileSystem) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dstDir, relPath)
+		if d.IsDir() {
+			return fSys.MkdirAll(dstPath)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return fSys.WriteFile(dstPath, data)
+	})
+}
+
+//flattenValues converts nested values into keys with dot-notation (e.g. [a:[b:value]] becomes a.b=value)
+func flattenValues(values map[string]interface{}, prefix string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range values {
+		keyPath := joinKeyPath(prefix, key)
+		if nested, ok := value.(map[string]interface{}); ok {
+			for nestedKey, nestedValue := range flattenValues(nested, keyPath) {
+				result[nestedKey] = nestedValue
+			}
+			continue
+		}
+		result[keyPath] = value
+	}
+	return result
+}
+
+//substituteVariables replaces variables with the values of the component. Unknown variables are kept unchanged.
+//A variable which defines a whole value is rendered as YAML scalar, a variable which is embedded in a value is
+//replaced by the plain value (values which would require quoting are rejected). Only scalar values can be substituted.
+func substituteVariables(manifest string, values map[string]interface{}) (string, error) {
+	var result strings.Builder
+	var last int
+	for _, match := range variablePattern.FindAllStringSubmatchIndex(manifest, -1) {
+		start, end := match[0], match[1]
+		value, ok := values[manifest[match[2]:match[3]]]
+		if !ok || value == nil {
+			continue
+		}
+
+		lineStart := strings.LastIndex(manifest[:start], "\n") + 1
+		lineEnd := strings.Index(manifest[end:], "\n")
+		if lineEnd < 0 {
+			lineEnd = len(manifest)
+		} else {
+			lineEnd += end
+		}
+		standalone := scalarPrefixPattern.MatchString(manifest[lineStart:start]) &&
+			strings.TrimSpace(manifest[end:lineEnd]) == ""
+
+		rendered, err := renderVariable(manifest[start:end], value, standalone)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(manifest[last:start])
+		result.WriteString(rendered)
+		last = end
+	}
+	result.WriteString(manifest[last:])
+	return result.String(), nil
+}
+
+func renderVariable(variable string, value interface{}, standalone bool) (string, error) {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("variable '%s' cannot be substituted by a non-scalar value", variable)
+	}
+	str, isString := value.(string)
+	if !standalone {
+		if isString && strings.ContainsAny(str, "\r\n") {
+			return "", fmt.Errorf("variable '%s' is embedded in a value and cannot be substituted by a multi-line string",
+				variable)
+		}
+		if isString && !isPlainScalarFragment(str) {
+			return "", fmt.Errorf("variable '%s' is embedded in a value and cannot be substituted by a string "+
+				"which requires quoting: use the variable as the whole value instead", variable)
+		}
+		return fmt.Sprint(value), nil
+	}
+	if isString && strings.ContainsAny(str, "\r\n") {
+		//multi-line strings are rendered as quoted scalar to stay independent of the indentation
+		quoted, err := json.Marshal(str)
+		return string(quoted), err
+	}
+	scalar, err := yaml.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to render value of variable '%s'", variable))
+	}
+	return strings.TrimSuffix(string(scalar), "\n"), nil
+}
+
+//isPlainScalarFragment returns true if the string can be embedded in a plain YAML scalar without changing
+//the structure of the document (e.g. strings containing ': ' would start a mapping, ' #' a comment)
+func isPlainScalarFragment(str string) bool {
+	if str == "" {
+		return true
+	}
+	if strings.Contains(str, ": ") || strings.HasSuffix(str, ":") ||
+		strings.Contains(str, " #") || strings.Contains(str, "\t") ||
+		strings.ContainsAny(str, "{}[]") {
+		return false
+	}
+	//indicators which are not allowed at the beginning of a plain scalar
+	return !strings.ContainsAny(str[:1], "*&!|>'\"%@`# ") && !strings.HasSuffix(str, " ")
+}
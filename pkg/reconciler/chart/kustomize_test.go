@@ -0,0 +1,203 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	kustomizeDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: addon
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: addon
+        image: addon:${image.tag}
+`
+	plainConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: addon
+data:
+  domain: ${global.domainName}
+  script: echo ${HOME}
+`
+)
+
+func writeFiles(t *testing.T, baseDir string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(baseDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+func TestComponentDir(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFiles(t, baseDir, map[string]string{
+		"chart/Chart.yaml":                     "name: chart",
+		"kustomize/kustomization.yaml":         "resources: []",
+		"manifests/cm.yaml":                    plainConfigMap,
+		"external/overlays/kustomization.yaml": "resources: []",
+	})
+
+	for name, expected := range map[string]ComponentType{
+		"chart":     ComponentTypeHelm,
+		"kustomize": ComponentTypeKustomize,
+		"manifests": ComponentTypeManifests,
+	} {
+		dir, compType, err := componentDir(baseDir, NewComponentBuilder("1.0.0", name).Build())
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(baseDir, name), dir)
+		require.Equal(t, expected, compType)
+	}
+
+	dir, compType, err := componentDir(filepath.Join(baseDir, "external"),
+		NewComponentBuilder("1.0.0", "addon").WithURL("https://example.com/addon.tgz").Build())
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(baseDir, "external", "overlays"), dir)
+	require.Equal(t, ComponentTypeKustomize, compType)
+
+	dir, compType, err = componentDir(filepath.Join(baseDir, "manifests"),
+		NewComponentBuilder("1.0.0", "addon").WithURL("https://example.com/addon.tgz").Build())
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(baseDir, "manifests"), dir)
+	require.Equal(t, ComponentTypeManifests, compType)
+}
+
+func TestManifestClient(t *testing.T) {
+	logger := log.NewLogger(true)
+
+	t.Run("Render plain manifests", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"cm.yaml":            plainConfigMap,
+			"values.yaml":        "global:\n  domainName: default.example.com\n",
+			"README.md":          "ignored",
+			".github/ci.yaml":    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: hidden\n",
+			"docs/notes.yaml":    "title: notes\n",
+			"sub/deploy.yml":     kustomizeDeployment,
+			wsReadyIndicatorFile: "revision: \"1\"",
+		})
+		client, err := NewManifestClient(dir, ComponentTypeManifests, logger)
+		require.NoError(t, err)
+
+		manifest, err := client.Render(NewComponentBuilder("1.0.0", "addon").
+			WithConfiguration(map[string]interface{}{"image.tag": "2.0.0"}).
+			Build())
+		require.NoError(t, err)
+		require.Contains(t, manifest, "domain: default.example.com")
+		require.Contains(t, manifest, "image: addon:2.0.0")
+		require.Contains(t, manifest, "echo ${HOME}") //unknown variables are kept
+		require.NotContains(t, manifest, "ignored")
+		require.NotContains(t, manifest, "revision")
+		require.NotContains(t, manifest, "hidden")      //hidden directories are skipped
+		require.NotContains(t, manifest, "title:")      //files without Kubernetes objects are skipped
+		require.NotContains(t, manifest, "domainName:") //values are not rendered
+	})
+
+	t.Run("Render values as YAML scalars", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: addon\ndata:\n" +
+				"  enabled: ${enabled}\n  text: ${text}\n  script: ${script}\n  url: https://${host}/path\n" +
+				"  items:\n  - ${item}\n",
+		})
+		client, err := NewManifestClient(dir, ComponentTypeManifests, logger)
+		require.NoError(t, err)
+
+		manifest, err := client.Render(NewComponentBuilder("1.0.0", "addon").
+			WithConfiguration(map[string]interface{}{
+				"enabled": "yes",
+				"text":    "key: value",
+				"script":  "line1\nline2",
+				"host":    "example.com",
+				"item":    "1.0",
+			}).
+			Build())
+		require.NoError(t, err)
+		require.Contains(t, manifest, `enabled: "yes"`)
+		require.Contains(t, manifest, `text: 'key: value'`)
+		require.Contains(t, manifest, `script: "line1\nline2"`)
+		require.Contains(t, manifest, "url: https://example.com/path")
+		require.Contains(t, manifest, `- "1.0"`)
+
+		_, err = client.Render(NewComponentBuilder("1.0.0", "addon").
+			WithConfiguration(map[string]interface{}{"text": []interface{}{"a", "b"}}).
+			Build())
+		require.Error(t, err)
+	})
+
+	t.Run("Reject embedded values which require quoting", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: addon\ndata:\n  url: https://${host}/path\n",
+		})
+		client, err := NewManifestClient(dir, ComponentTypeManifests, logger)
+		require.NoError(t, err)
+
+		for _, host := range []string{"a: b", "x # y", "{a}", "*alias", "&anchor"} {
+			_, err = client.Render(NewComponentBuilder("1.0.0", "addon").
+				WithConfiguration(map[string]interface{}{"host": host}).
+				Build())
+			require.Error(t, err, host)
+			require.Contains(t, err.Error(), "requires quoting", host)
+		}
+
+		manifest, err := client.Render(NewComponentBuilder("1.0.0", "addon").
+			WithConfiguration(map[string]interface{}{"host": "example.com:8080"}).
+			Build())
+		require.NoError(t, err)
+		require.Contains(t, manifest, "url: https://example.com:8080/path")
+	})
+
+	t.Run("Render kustomization with patches", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"kustomization.yaml": "resources:\n- deployment.yaml\n",
+			"deployment.yaml":    kustomizeDeployment,
+		})
+		client, err := NewManifestClient(dir, ComponentTypeKustomize, logger)
+		require.NoError(t, err)
+
+		manifest, err := client.Render(NewComponentBuilder("1.0.0", "addon").
+			WithConfiguration(map[string]interface{}{
+				"image.tag": "3.0.0",
+				KustomizePatchesConfigKey: []interface{}{
+					"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: addon\nspec:\n  replicas: 3\n",
+				},
+			}).
+			Build())
+		require.NoError(t, err)
+		require.Contains(t, manifest, "replicas: 3")
+		require.Contains(t, manifest, "image: addon:3.0.0")
+
+		values, err := client.Configuration(NewComponentBuilder("1.0.0", "addon").
+			WithConfiguration(map[string]interface{}{"image.tag": "3.0.0", KustomizePatchesConfigKey: []interface{}{}}).
+			Build())
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"image": map[string]interface{}{"tag": "3.0.0"}}, values)
+	})
+
+	t.Run("Invalid kustomization", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"kustomization.yaml": "resources:\n- missing.yaml\n"})
+		client, err := NewManifestClient(dir, ComponentTypeKustomize, logger)
+		require.NoError(t, err)
+		_, err = client.Render(NewComponentBuilder("1.0.0", "addon").Build())
+		require.Error(t, err)
+	})
+
+	t.Run("Unsupported component type", func(t *testing.T) {
+		_, err := NewManifestClient(t.TempDir(), ComponentTypeHelm, logger)
+		require.Error(t, err)
+	})
+}
@@ -8,10 +8,18 @@ import (
 type ManifestType string
 
 const (
-	CRD       ManifestType = "crd"
-	HelmChart ManifestType = "helmChart"
+	CRD            ManifestType = "crd"
+	HelmChart      ManifestType = "helmChart"
+	Kustomization  ManifestType = "kustomization"
+	PlainManifests ManifestType = "manifests"
 )
 
+var manifestTypes = map[ComponentType]ManifestType{
+	ComponentTypeHelm:      HelmChart,
+	ComponentTypeKustomize: Kustomization,
+	ComponentTypeManifests: PlainManifests,
+}
+
 type Manifest struct {
 	Type     ManifestType
 	Name     string
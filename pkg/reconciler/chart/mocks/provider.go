@@ -81,6 +81,20 @@ func (_m *Provider) RenderManifest(component *chart.Component) (*chart.Manifest,
 	return r0, r1
 }
 
+// Validate provides a mock function with given fields: component
+func (_m *Provider) Validate(component *chart.Component) error {
+	ret := _m.Called(component)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*chart.Component) error); ok {
+		r0 = rf(component)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WithFilter provides a mock function with given fields: filter
 func (_m *Provider) WithFilter(filter chart.Filter) chart.Provider {
 	ret := _m.Called(filter)
@@ -0,0 +1,122 @@
+package chart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	reconcilerK8s "github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci"
+	"github.com/pkg/errors"
+)
+
+const ociCacheDir = ".oci"
+
+func (c *Component) isExternalOCIComponent() bool {
+	return oci.IsOCIURL(c.url)
+}
+
+//pullKymaWorkspace downloads the Kyma sources of the given version from an OCI registry
+func (f *DefaultFactory) pullKymaWorkspace(version, wsDir string) error {
+	ref, err := oci.ParseReference(f.kymaRepository.URL)
+	if err != nil {
+		return err
+	}
+	ref = ref.WithDefaultTag(version)
+
+	f.logger.Infof("Pulling OCI artifact '%s' into workspace '%s'", ref, wsDir)
+	client, err := f.ociClient(ref, f.kymaRepository.TokenNamespace)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Pull(ref, wsDir, f.trackBlob(extractOCILayer)); err != nil {
+		f.logger.Warnf("Deleting workspace '%s' because pulling OCI artifact '%s' failed", wsDir, ref)
+		if removeErr := os.RemoveAll(wsDir); removeErr != nil {
+			err = errors.Wrap(err, removeErr.Error())
+		}
+		return err
+	}
+	return f.createReadyMarker(wsDir)
+}
+
+//getExternalOCIComponent downloads the chart of an external component from an OCI registry. Tags are resolved
+//to the digest of their manifest, so each workspace contains an immutable chart and is re-used by all
+//references pointing to the same digest.
+func (f *DefaultFactory) getExternalOCIComponent(component *Component) (*Workspace, error) {
+	ref, err := oci.ParseReference(component.url)
+	if err != nil {
+		return nil, err
+	}
+	ref = ref.WithDefaultTag(component.version)
+
+	client, err := f.ociClient(ref, component.tokenNamespace())
+	if err != nil {
+		return nil, err
+	}
+	digest, err := client.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	wsDir := f.workspaceDir(fmt.Sprintf("%s-%s", strings.TrimPrefix(digest, "sha256:")[0:12], component.name))
+	return f.acquireWorkspace(wsDir, func() (*Workspace, error) {
+		if f.readyMarkerExists(wsDir) {
+			f.workspaceCache().hit(workspaceTypeComponent)
+			return newComponentWorkspace(wsDir)
+		}
+		f.workspaceCache().miss(workspaceTypeComponent)
+
+		if err := f.cleanFailedWorkspace(wsDir); err != nil {
+			return nil, err
+		}
+
+		f.logger.Infof("Pulling component '%s' with version '%s' from OCI artifact '%s' into workspace '%s'",
+			component.name, component.version, ref, wsDir)
+		extract := func(layerFile, dstDir string) error {
+			if err := f.verifyArchive(component, layerFile); err != nil {
+				return err
+			}
+			return extractOCILayer(layerFile, dstDir)
+		}
+		if _, err := client.Pull(ref.WithDigest(digest), wsDir, f.trackBlob(extract)); err != nil {
+			if removeErr := os.RemoveAll(wsDir); removeErr != nil {
+				err = errors.Wrap(err, removeErr.Error())
+			}
+			return nil, err
+		}
+		if err := f.createReadyMarker(wsDir); err != nil {
+			return nil, err
+		}
+		f.workspaceCache().added(wsDir)
+
+		return newComponentWorkspace(wsDir)
+	})
+}
+
+//extractOCILayer extracts a pulled layer with the same protections as downloaded archives
+func extractOCILayer(layerFile, dstDir string) error {
+	return unarchiveTarGz(layerFile, dstDir, defaultArchiveLimits)
+}
+
+//trackBlob registers the pulled blob in the workspace cache to count it against the cache quota.
+//The blob can't be evicted while it gets extracted.
+func (f *DefaultFactory) trackBlob(extract oci.Extractor) oci.Extractor {
+	return func(layerFile, dstDir string) error {
+		release := f.workspaceCache().acquireBlob(layerFile)
+		defer release()
+		return extract(layerFile, dstDir)
+	}
+}
+
+func (f *DefaultFactory) ociClient(ref *oci.Reference, tokenNamespace string) (*oci.Client, error) {
+	clientSet, err := reconcilerK8s.NewInClusterClientSet(f.logger)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := oci.LoadCredentials(clientSet, ref.Registry, tokenNamespace, f.logger)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load credentials of OCI registry '%s'", ref.Registry)
+	}
+	return oci.NewClient(filepath.Join(f.storageDir, ociCacheDir), credentials, f.logger), nil
+}
@@ -0,0 +1,89 @@
+package chart
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOCIWorkspaces(t *testing.T) {
+	logger := log.NewLogger(true)
+	registry := test.NewRegistry(t)
+
+	t.Run("Pull Kyma workspace", func(t *testing.T) {
+		registry.Push(t, "kyma", "2.0.0", map[string]string{
+			"resources/comp/Chart.yaml":                  "apiVersion: v2\nname: comp\nversion: 1.0.0\n",
+			"installation/resources/components.yaml":     "components: []\n",
+			"installation/resources/crds/crd.yaml":       "kind: CustomResourceDefinition\n",
+			"installation/resources/crds/other/crd.yaml": "kind: CustomResourceDefinition\n",
+		})
+
+		factory, err := NewFactory(&reconciler.Repository{URL: "oci://" + registry.Host() + "/kyma"}, t.TempDir(), logger)
+		require.NoError(t, err)
+
+		ws, err := factory.Get("2.0.0")
+		require.NoError(t, err)
+		checkWorkspaceDirectories(t, ws)
+		require.True(t, file.Exists(filepath.Join(ws.ResourceDir, "comp", "Chart.yaml")))
+
+		_, err = factory.Get("3.0.0")
+		require.Error(t, err)
+		require.False(t, file.DirExists(factory.workspaceDir("3.0.0")))
+	})
+
+	t.Run("Pull external component", func(t *testing.T) {
+		digest := registry.Push(t, "charts/mychart", "1.0.0", map[string]string{
+			"mychart/Chart.yaml":  "apiVersion: v2\nname: mychart\nversion: 1.0.0\n",
+			"mychart/values.yaml": "replicas: 1\n",
+		})
+		registry.Push(t, "charts/mychart", "latest", map[string]string{
+			"mychart/Chart.yaml":  "apiVersion: v2\nname: mychart\nversion: 1.0.0\n",
+			"mychart/values.yaml": "replicas: 1\n",
+		})
+
+		factory := &DefaultFactory{logger: logger, storageDir: t.TempDir()}
+		url := "oci://" + registry.Host() + "/charts/mychart"
+
+		ws, err := factory.GetExternalComponent(NewComponentBuilder("1.0.0", "mychart").WithURL(url).Build())
+		require.NoError(t, err)
+		require.Equal(t, factory.workspaceDir(digest[len("sha256:"):len("sha256:")+12]+"-mychart"), ws.WorkspaceDir)
+		require.True(t, file.Exists(filepath.Join(ws.WorkspaceDir, "mychart", "Chart.yaml")))
+		blobRequests := registry.BlobRequests()
+
+		//same digest referenced via tag 'latest' and via pinned digest re-uses the workspace
+		ws2, err := factory.GetExternalComponent(NewComponentBuilder("", "mychart").WithURL(url).Build())
+		require.NoError(t, err)
+		require.Equal(t, ws.WorkspaceDir, ws2.WorkspaceDir)
+		ws3, err := factory.GetExternalComponent(NewComponentBuilder("", "mychart").WithURL(url + "@" + digest).Build())
+		require.NoError(t, err)
+		require.Equal(t, ws.WorkspaceDir, ws3.WorkspaceDir)
+		require.Equal(t, blobRequests, registry.BlobRequests())
+	})
+	t.Run("Verify layer of external component", func(t *testing.T) {
+		registry.Push(t, "charts/verified", "1.0.0", map[string]string{
+			"verified/Chart.yaml": "apiVersion: v2\nname: verified\nversion: 1.0.0\n",
+		})
+		factory := &DefaultFactory{logger: logger, storageDir: t.TempDir()}
+		url := "oci://" + registry.Host() + "/charts/verified"
+
+		_, err := factory.GetExternalComponent(NewComponentBuilder("1.0.0", "verified").
+			WithURL(url).
+			WithConfiguration(map[string]interface{}{ArchiveChecksumConfigKey: fmt.Sprintf("%x", sha256.Sum256(nil))}).
+			Build())
+		require.Error(t, err)
+		require.True(t, IsVerificationError(err))
+
+		//signatures of OCI artifacts have no default location
+		_, err = factory.WithTrustedKeys([]string{"-----BEGIN PUBLIC KEY-----"}).
+			GetExternalComponent(NewComponentBuilder("1.0.0", "verified").WithURL(url).Build())
+		require.Error(t, err)
+		require.True(t, IsVerificationError(err))
+	})
+}
@@ -0,0 +1,113 @@
+package chart
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imdario/mergo"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	//profilesFile defines the inheritance of profiles, e.g. 'production: {extends: evaluation}'
+	profilesFile         = "profiles.yaml"
+	landscapeFilePattern = "landscape-%s.yaml"
+	maxProfileDepth      = 10
+)
+
+type profileDefinition struct {
+	Extends string `json:"extends"`
+}
+
+//profileChain returns the names of the profiles which have to be merged to get the values of the profile:
+//base profiles first, the requested profile last
+func profileChain(ch *chart.Chart, profileName string) ([]string, error) {
+	definitions := make(map[string]profileDefinition)
+	if f := chartFile(ch, profilesFile); f != nil {
+		if err := yaml.Unmarshal(f.Data, &definitions); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to parse profile definitions in '%s' of chart '%s'",
+				profilesFile, ch.Name()))
+		}
+	}
+
+	var chain []string
+	visited := make(map[string]bool)
+	for name := strings.ToLower(profileName); name != ""; {
+		if visited[name] {
+			return nil, fmt.Errorf("profile '%s' of chart '%s' has a cyclic inheritance: %s",
+				profileName, ch.Name(), strings.Join(append(chain, name), " -> "))
+		}
+		if len(chain) >= maxProfileDepth {
+			return nil, fmt.Errorf("inheritance of profile '%s' of chart '%s' exceeds the maximum depth of %d",
+				profileName, ch.Name(), maxProfileDepth)
+		}
+		visited[name] = true
+		chain = append(chain, name)
+		name = strings.ToLower(definitions[name].Extends)
+	}
+
+	//reverse the order: base profiles have to be merged first
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+//profileFile returns the file of the profile or nil if the chart has no file for the profile
+func profileFile(ch *chart.Chart, profileName string) *chart.File {
+	profileNameLC := strings.ToLower(profileName)
+	if f := chartFile(ch, fmt.Sprintf("profile-%s.yaml", profileNameLC)); f != nil {
+		return f
+	}
+	return chartFile(ch, fmt.Sprintf("%s.yaml", profileNameLC))
+}
+
+//landscapeOverlay returns the values of the chart which are specific for the landscape
+func landscapeOverlay(ch *chart.Chart, landscape string) (map[string]interface{}, error) {
+	if landscape == "" {
+		return nil, nil
+	}
+	f := chartFile(ch, fmt.Sprintf(landscapeFilePattern, strings.ToLower(landscape)))
+	if f == nil {
+		return nil, nil
+	}
+	values, err := chartutil.ReadValues(f.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to read overlay of landscape '%s'", landscape))
+	}
+	return values, nil
+}
+
+//mergeProfileChain merges the values of all profiles in the chain. Returns nil if none of the profiles has a file.
+func mergeProfileChain(ch *chart.Chart, chain []string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	for _, name := range chain {
+		f := profileFile(ch, name)
+		if f == nil {
+			continue
+		}
+		values, err := chartutil.ReadValues(f.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to read values of profile '%s'", name))
+		}
+		if result == nil {
+			result = make(map[string]interface{})
+		}
+		if err := mergo.Merge(&result, values.AsMap(), mergo.WithOverride); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to merge values of profile '%s'", name))
+		}
+	}
+	return result, nil
+}
+
+func chartFile(ch *chart.Chart, name string) *chart.File {
+	for _, f := range ch.Files {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func newProfileChart(t *testing.T, profiles string) string {
+	chartsDir := t.TempDir()
+	writeFile := func(path, content string) {
+		path = filepath.Join(chartsDir, "mycomponent", path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	writeFile("Chart.yaml", "apiVersion: v2\nname: mycomponent\nversion: 1.0.0\n")
+	writeFile("values.yaml", "replicas: 1\nimage:\n  tag: 1.0.0\n")
+	writeFile("profiles.yaml", profiles)
+	writeFile("profile-evaluation.yaml", "replicas: 1\nimage:\n  tag: 1.1.0\nresources: small\n")
+	writeFile("profile-production.yaml", "replicas: 3\n")
+	writeFile("profile-production-ha.yaml", "replicas: 5\n")
+	writeFile("landscape-prod.yaml", "image:\n  tag: 1.2.0\n")
+	return chartsDir
+}
+
+func TestProfileChain(t *testing.T) {
+	newChart := func(profiles string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: "mycomponent"},
+			Files:    []*chart.File{{Name: profilesFile, Data: []byte(profiles)}},
+		}
+	}
+
+	t.Run("Profile without inheritance", func(t *testing.T) {
+		chain, err := profileChain(newChart(""), "Evaluation")
+		require.NoError(t, err)
+		require.Equal(t, []string{"evaluation"}, chain)
+	})
+
+	t.Run("Profile with inheritance", func(t *testing.T) {
+		chain, err := profileChain(newChart("production-ha: {extends: production}\nproduction: {extends: evaluation}\n"),
+			"production-ha")
+		require.NoError(t, err)
+		require.Equal(t, []string{"evaluation", "production", "production-ha"}, chain)
+	})
+
+	t.Run("Cyclic inheritance", func(t *testing.T) {
+		_, err := profileChain(newChart("production: {extends: evaluation}\nevaluation: {extends: production}\n"),
+			"production")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "production -> evaluation -> production")
+	})
+
+	t.Run("Invalid profile definitions", func(t *testing.T) {
+		_, err := profileChain(newChart("production: [evaluation"), "production")
+		require.Error(t, err)
+	})
+}
+
+func TestProfileConfiguration(t *testing.T) {
+	helmClient, err := NewHelmClient(newProfileChart(t, "production: {extends: evaluation}\n"), log.NewLogger(true))
+	require.NoError(t, err)
+
+	configuration := func(component *Component) map[string]interface{} {
+		values, err := helmClient.Configuration(component)
+		require.NoError(t, err)
+		return values
+	}
+
+	t.Run("Inherited profile values", func(t *testing.T) {
+		values := configuration(NewComponentBuilder("1.0.0", "mycomponent").
+			WithProfile("production").
+			Build())
+		require.Equal(t, float64(3), values["replicas"])
+		require.Equal(t, "small", values["resources"])
+		require.Equal(t, "1.1.0", values["image"].(map[string]interface{})["tag"])
+	})
+
+	t.Run("Landscape overlay and profile values", func(t *testing.T) {
+		values := configuration(NewComponentBuilder("1.0.0", "mycomponent").
+			WithProfile("production").
+			WithLandscape("prod").
+			Build())
+		require.Equal(t, "1.2.0", values["image"].(map[string]interface{})["tag"])
+
+		values = configuration(NewComponentBuilder("1.0.0", "mycomponent").
+			WithProfile("production").
+			WithLandscape("prod").
+			WithProfileValues(map[string]interface{}{"image.tag": "1.3.0", "replicas": 4}).
+			Build())
+		require.Equal(t, "1.3.0", values["image"].(map[string]interface{})["tag"])
+		require.Equal(t, 4, values["replicas"])
+	})
+
+	t.Run("Component configuration overrides profile values", func(t *testing.T) {
+		values := configuration(NewComponentBuilder("1.0.0", "mycomponent").
+			WithProfile("production").
+			WithProfileValues(map[string]interface{}{"replicas": 4}).
+			WithConfiguration(map[string]interface{}{"replicas": 6}).
+			Build())
+		require.Equal(t, 6, values["replicas"])
+	})
+}
@@ -28,15 +28,20 @@ type Provider interface {
 
 	// Configuration of the given component.
 	Configuration(component *Component) (map[string]interface{}, error)
+
+	// Validate the configuration of the given component against the chart.
+	Validate(component *Component) error
 }
 
 type Filter func(string) (string, error)
 
 // DefaultProvider provides a default implementation of Provider.
 type DefaultProvider struct {
-	wsFactory Factory
-	logger    *zap.SugaredLogger
-	filters   []Filter
+	wsFactory        Factory
+	logger           *zap.SugaredLogger
+	filters          []Filter
+	strictValidation bool
+	renderCache      *RenderCache
 }
 
 // NewDefaultProvider returns a new instance of DefaultProvider.
@@ -55,11 +60,24 @@ func (p *DefaultProvider) WithFilter(f Filter) Provider {
 	return p
 }
 
+//WithStrictValidation enables the rejection of configuration keys which are unknown to the chart
+func (p *DefaultProvider) WithStrictValidation(strict bool) *DefaultProvider {
+	p.strictValidation = strict
+	return p
+}
+
+//WithRenderCache reuses manifests which were already rendered for the same workspace revision and values
+func (p *DefaultProvider) WithRenderCache(cache *RenderCache) *DefaultProvider {
+	p.renderCache = cache
+	return p
+}
+
 func (p *DefaultProvider) RenderCRD(version string) ([]*Manifest, error) {
 	ws, err := p.wsFactory.Get(version)
 	if err != nil {
 		return nil, err
 	}
+	defer ws.Release()
 
 	p.logger.Debugf("Rendering CRD resources of Kyma version '%s'", version)
 
@@ -113,19 +131,39 @@ func (p *DefaultProvider) RenderCRD(version string) ([]*Manifest, error) {
 }
 
 func (p *DefaultProvider) RenderManifest(component *Component) (*Manifest, error) {
-	wsDir, err := p.workspaceDir(component)
+	chartDir, wsDir, release, err := p.workspace(component)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create workspace dir")
 	}
+	defer release()
 
-	helmClient, err := NewHelmClient(wsDir, p.logger)
+	srcDir, compType, err := componentDir(chartDir, component)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create new helm client")
+		return nil, err
 	}
 
-	manifest, err := helmClient.Render(component)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed helm client render")
+	var manifest string
+	manifestType := HelmChart
+	if compType == ComponentTypeHelm {
+		helmClient, err := NewHelmClient(chartDir, p.logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create new helm client")
+		}
+		manifest, err = p.render(helmClient, wsDir, component)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed helm client render")
+		}
+	} else {
+		p.logger.Debugf("Rendering component '%s' of type '%s' from directory '%s'", component.name, compType, srcDir)
+		manifestClient, err := NewManifestClient(srcDir, compType, p.logger)
+		if err != nil {
+			return nil, err
+		}
+		manifest, err = manifestClient.Render(component)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to render %s component", compType))
+		}
+		manifestType = manifestTypes[compType]
 	}
 
 	for _, f := range p.filters {
@@ -136,17 +174,30 @@ func (p *DefaultProvider) RenderManifest(component *Component) (*Manifest, error
 	}
 
 	return &Manifest{
-		Type:     HelmChart,
+		Type:     manifestType,
 		Name:     component.name,
 		Manifest: manifest,
 	}, nil
 }
 
 func (p *DefaultProvider) Configuration(component *Component) (map[string]interface{}, error) {
-	wsDir, err := p.workspaceDir(component)
+	wsDir, release, err := p.workspaceDir(component)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	srcDir, compType, err := componentDir(wsDir, component)
 	if err != nil {
 		return nil, err
 	}
+	if compType != ComponentTypeHelm {
+		manifestClient, err := NewManifestClient(srcDir, compType, p.logger)
+		if err != nil {
+			return nil, err
+		}
+		return manifestClient.Configuration(component)
+	}
 
 	helmClient, err := NewHelmClient(wsDir, p.logger)
 	if err != nil {
@@ -156,20 +207,83 @@ func (p *DefaultProvider) Configuration(component *Component) (map[string]interf
 	return helmClient.Configuration(component)
 }
 
-func (p *DefaultProvider) workspaceDir(component *Component) (string, error) {
+func (p *DefaultProvider) Validate(component *Component) error {
+	wsDir, release, err := p.workspaceDir(component)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	_, compType, err := componentDir(wsDir, component)
+	if err != nil {
+		return err
+	}
+	if compType != ComponentTypeHelm {
+		//kustomizations and plain manifests have no values schema
+		return nil
+	}
+
+	helmClient, err := NewHelmClient(wsDir, p.logger)
+	if err != nil {
+		return err
+	}
+
+	return helmClient.Validate(component, p.strictValidation)
+}
+
+//render returns the rendered manifest of the component. If the render cache is enabled, the manifest is only
+//rendered if the cache contains no manifest for the same workspace revision, component and values.
+func (p *DefaultProvider) render(helmClient *HelmClient, wsDir string, component *Component) (string, error) {
+	if !p.renderCache.enabled() || (component.url == "" && component.version == VersionLocal) {
+		//local workspaces can change at any time and are never cached
+		return helmClient.Render(component)
+	}
+
+	helmChart, config, err := helmClient.loadChart(component)
+	if err != nil {
+		return "", err
+	}
+	revision := workspaceRevision(wsDir)
+	key, err := renderCacheKey(revision, component, config)
+	if err != nil {
+		p.logger.Warnf("Failed to calculate render cache key of component '%s': %s", component.name, err)
+		return helmClient.template(component, helmChart, config)
+	}
+	if manifest, ok := p.renderCache.get(key, wsDir, revision); ok {
+		p.logger.Debugf("Using cached manifest of component '%s' (key: %s)", component.name, key)
+		return manifest, nil
+	}
+
+	manifest, err := helmClient.template(component, helmChart, config)
+	if err != nil {
+		return "", err
+	}
+	p.renderCache.put(key, wsDir, revision, manifest)
+	return manifest, nil
+}
+
+//workspaceDir returns the directory of the workspace which contains the chart of the component.
+//The returned function releases the workspace and has to be called when the directory is no longer used.
+func (p *DefaultProvider) workspaceDir(component *Component) (string, func(), error) {
+	chartDir, _, release, err := p.workspace(component)
+	return chartDir, release, err
+}
+
+//workspace returns the directory which contains the chart of the component and the root directory of its workspace
+func (p *DefaultProvider) workspace(component *Component) (string, string, func(), error) {
 	if component.url == "" {
 		//is a Kyma component
 		ws, err := p.wsFactory.Get(component.version)
 		if err != nil {
-			return "", err
+			return "", "", nil, err
 		}
-		return ws.ResourceDir, nil
+		return ws.ResourceDir, ws.WorkspaceDir, ws.Release, nil
 	}
 
 	//is an external component
 	ws, err := p.wsFactory.GetExternalComponent(component)
 	if err != nil {
-		return "", err
+		return "", "", nil, err
 	}
-	return ws.WorkspaceDir, nil
+	return ws.WorkspaceDir, ws.WorkspaceDir, ws.Release, nil
 }
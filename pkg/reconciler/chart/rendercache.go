@@ -0,0 +1,289 @@
+package chart
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	renderCacheFileExt = ".yaml"
+	renderTierMemory   = "memory"
+	renderTierDisk     = "disk"
+)
+
+var (
+	renderCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "reconciler",
+		Name:      "render_cache_hits_total",
+		Help:      "Number of rendered manifests which were served from the render cache",
+	}, []string{"tier"})
+	renderCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: "reconciler",
+		Name:      "render_cache_misses_total",
+		Help:      "Number of manifests which had to be rendered because they were not found in the render cache",
+	})
+	renderCacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: "reconciler",
+		Name:      "render_cache_bytes",
+		Help:      "Size in bytes of the rendered manifests held in memory",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(renderCacheHits, renderCacheMisses, renderCacheBytes)
+}
+
+// RenderCacheConfig configures the cache of rendered manifests. A MaxBytes value of 0 disables the cache.
+type RenderCacheConfig struct {
+	MaxBytes    int64  //size limit of the manifests cached in memory
+	Dir         string //optional directory used to keep rendered manifests across restarts
+	DirMaxBytes int64  //size limit of the manifests cached in the directory
+}
+
+type renderCacheEntry struct {
+	key      string
+	wsDir    string
+	manifest string
+}
+
+type renderCacheFile struct {
+	key  string
+	size int64
+}
+
+// RenderCache keeps rendered manifests of components. Entries are keyed by a hash of the workspace revision,
+// the component and its merged values. The memory and the optional disk tier have their own size limit and
+// the least recently used entries of a tier are evicted if its limit is exceeded.
+type RenderCache struct {
+	config     RenderCacheConfig
+	logger     *zap.SugaredLogger
+	entries    map[string]*list.Element
+	lru        *list.List //front = most recently used
+	bytes      int64
+	workspaces map[string]map[string]*list.Element //in-memory entries per workspace directory
+	revisions  map[string]string                   //latest revision per workspace directory
+	files      map[string]*list.Element
+	filesLRU   *list.List //front = most recently used
+	filesBytes int64
+	mu         sync.Mutex
+}
+
+func NewRenderCache(config RenderCacheConfig, logger *zap.SugaredLogger) (*RenderCache, error) {
+	if config.MaxBytes < 0 {
+		return nil, fmt.Errorf("size limit of render cache cannot be < 0 (got %d)", config.MaxBytes)
+	}
+	if config.Dir != "" && config.DirMaxBytes <= 0 {
+		return nil, fmt.Errorf("size limit of render cache directory has to be > 0 (got %d)", config.DirMaxBytes)
+	}
+	cache := &RenderCache{
+		config:     config,
+		logger:     logger,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		workspaces: make(map[string]map[string]*list.Element),
+		revisions:  make(map[string]string),
+		files:      make(map[string]*list.Element),
+		filesLRU:   list.New(),
+	}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0700); err != nil {
+			return nil, err
+		}
+		cache.loadFiles()
+	}
+	return cache, nil
+}
+
+func (c *RenderCache) enabled() bool {
+	return c != nil && c.config.MaxBytes > 0
+}
+
+// get returns the cached manifest for the key. In-memory entries which belong to a previous revision of the
+// workspace are dropped: the workspace was refreshed in the meantime. Files of previous revisions can't be hit
+// anymore (the revision is part of the key) and get evicted from the disk tier over time.
+func (c *RenderCache) get(key, wsDir, revision string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.trackRevision(wsDir, revision)
+
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		renderCacheHits.WithLabelValues(renderTierMemory).Inc()
+		return elem.Value.(*renderCacheEntry).manifest, true
+	}
+
+	if elem, ok := c.files[key]; ok {
+		data, err := ioutil.ReadFile(c.diskFile(key))
+		if err == nil {
+			c.filesLRU.MoveToFront(elem)
+			if int64(len(data)) <= c.config.MaxBytes {
+				c.add(&renderCacheEntry{key: key, wsDir: wsDir, manifest: string(data)})
+			}
+			renderCacheHits.WithLabelValues(renderTierDisk).Inc()
+			return string(data), true
+		}
+		c.logger.Warnf("Failed to read rendered manifest '%s' from render cache: %s", c.diskFile(key), err)
+		c.removeFile(elem)
+	}
+
+	renderCacheMisses.Inc()
+	return "", false
+}
+
+// put adds a rendered manifest to the cache
+func (c *RenderCache) put(key, wsDir, revision, manifest string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.trackRevision(wsDir, revision)
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if c.config.Dir != "" && int64(len(manifest)) <= c.config.DirMaxBytes {
+		if _, ok := c.files[key]; !ok {
+			c.addFile(key, manifest)
+		}
+	}
+	if int64(len(manifest)) > c.config.MaxBytes {
+		c.logger.Debugf("Rendered manifest with %d bytes exceeds the size limit of the render cache", len(manifest))
+		return
+	}
+	c.add(&renderCacheEntry{key: key, wsDir: wsDir, manifest: manifest})
+}
+
+// trackRevision drops the in-memory entries of a workspace if its revision changed (requires lock)
+func (c *RenderCache) trackRevision(wsDir, revision string) {
+	if current, ok := c.revisions[wsDir]; ok && current != revision {
+		if stale := c.workspaces[wsDir]; len(stale) > 0 {
+			c.logger.Debugf("Dropping %d rendered manifests of workspace '%s' because the workspace was refreshed",
+				len(stale), wsDir)
+			for _, elem := range stale {
+				c.remove(elem)
+			}
+			renderCacheBytes.Set(float64(c.bytes))
+		}
+	}
+	c.revisions[wsDir] = revision
+}
+
+// add stores the entry in memory and evicts least recently used entries (requires lock)
+func (c *RenderCache) add(entry *renderCacheEntry) {
+	elem := c.lru.PushFront(entry)
+	c.entries[entry.key] = elem
+	if _, ok := c.workspaces[entry.wsDir]; !ok {
+		c.workspaces[entry.wsDir] = make(map[string]*list.Element)
+	}
+	c.workspaces[entry.wsDir][entry.key] = elem
+	c.bytes += int64(len(entry.manifest))
+	for c.bytes > c.config.MaxBytes && c.lru.Len() > 1 {
+		c.remove(c.lru.Back())
+	}
+	renderCacheBytes.Set(float64(c.bytes))
+}
+
+// remove drops an entry from memory (requires lock)
+func (c *RenderCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*renderCacheEntry)
+	delete(c.entries, entry.key)
+	delete(c.workspaces[entry.wsDir], entry.key)
+	if len(c.workspaces[entry.wsDir]) == 0 {
+		delete(c.workspaces, entry.wsDir)
+	}
+	c.bytes -= int64(len(entry.manifest))
+}
+
+// addFile stores the manifest on disk and evicts least recently used files (requires lock)
+func (c *RenderCache) addFile(key, manifest string) {
+	if err := ioutil.WriteFile(c.diskFile(key), []byte(manifest), 0600); err != nil {
+		c.logger.Warnf("Failed to write rendered manifest '%s' into render cache: %s", c.diskFile(key), err)
+		return
+	}
+	c.files[key] = c.filesLRU.PushFront(&renderCacheFile{key: key, size: int64(len(manifest))})
+	c.filesBytes += int64(len(manifest))
+	for c.filesBytes > c.config.DirMaxBytes && c.filesLRU.Len() > 1 {
+		c.removeFile(c.filesLRU.Back())
+	}
+}
+
+// removeFile deletes a manifest from disk (requires lock)
+func (c *RenderCache) removeFile(elem *list.Element) {
+	file := c.filesLRU.Remove(elem).(*renderCacheFile)
+	delete(c.files, file.key)
+	c.filesBytes -= file.size
+	if err := os.Remove(c.diskFile(file.key)); err != nil && !os.IsNotExist(err) {
+		c.logger.Warnf("Failed to delete rendered manifest '%s' from render cache: %s", c.diskFile(file.key), err)
+	}
+}
+
+// loadFiles registers the manifests in the cache directory and deletes the oldest ones which don't fit
+// into the size limit
+func (c *RenderCache) loadFiles() {
+	files, err := ioutil.ReadDir(c.config.Dir)
+	if err != nil {
+		c.logger.Warnf("Failed to read render cache directory '%s': %s", c.config.Dir, err)
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { //newest first
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), renderCacheFileExt) {
+			continue
+		}
+		if c.filesBytes+file.Size() > c.config.DirMaxBytes {
+			if err := os.Remove(filepath.Join(c.config.Dir, file.Name())); err != nil {
+				c.logger.Warnf("Failed to delete rendered manifest '%s' from render cache: %s", file.Name(), err)
+			}
+			continue
+		}
+		key := strings.TrimSuffix(file.Name(), renderCacheFileExt)
+		c.files[key] = c.filesLRU.PushBack(&renderCacheFile{key: key, size: file.Size()})
+		c.filesBytes += file.Size()
+	}
+}
+
+func (c *RenderCache) diskFile(key string) string {
+	return filepath.Join(c.config.Dir, key+renderCacheFileExt)
+}
+
+// renderCacheKey returns the hash of all inputs which influence the rendered manifest of a component
+func renderCacheKey(revision string, component *Component, values map[string]interface{}) (string, error) {
+	data, err := json.Marshal(struct { //maps are marshalled with sorted keys
+		Revision  string                 `json:"revision"`
+		Name      string                 `json:"name"`
+		Version   string                 `json:"version"`
+		URL       string                 `json:"url"`
+		Profile   string                 `json:"profile"`
+		Namespace string                 `json:"namespace"`
+		Values    map[string]interface{} `json:"values"`
+	}{revision, component.name, component.version, component.url, component.profile, component.namespace, values})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// workspaceRevision returns the revision of the workspace stored in its ready marker. Workspaces created by
+// previous releases have an empty marker: their directory is used as revision.
+func workspaceRevision(wsDir string) string {
+	data, err := ioutil.ReadFile(filepath.Join(wsDir, wsReadyIndicatorFile))
+	if err != nil || len(data) == 0 {
+		return wsDir
+	}
+	return fmt.Sprintf("%s@%s", wsDir, strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,175 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCache(t *testing.T) {
+	logger := log.NewLogger(true)
+
+	t.Run("Evict least recently used manifests", func(t *testing.T) {
+		cache, err := NewRenderCache(RenderCacheConfig{MaxBytes: 20}, logger)
+		require.NoError(t, err)
+
+		cache.put("key1", "ws", "rev1", strings.Repeat("1", 10))
+		cache.put("key2", "ws", "rev1", strings.Repeat("2", 10))
+		_, ok := cache.get("key1", "ws", "rev1") //key1 becomes most recently used
+		require.True(t, ok)
+
+		cache.put("key3", "ws", "rev1", strings.Repeat("3", 10))
+		_, ok = cache.get("key2", "ws", "rev1")
+		require.False(t, ok)
+		manifest, ok := cache.get("key1", "ws", "rev1")
+		require.True(t, ok)
+		require.Equal(t, strings.Repeat("1", 10), manifest)
+		require.Equal(t, float64(20), testutil.ToFloat64(renderCacheBytes))
+
+		cache.put("key4", "ws", "rev1", strings.Repeat("4", 21)) //exceeds the limit
+		_, ok = cache.get("key4", "ws", "rev1")
+		require.False(t, ok)
+	})
+
+	t.Run("Drop manifests of refreshed workspaces", func(t *testing.T) {
+		cache, err := NewRenderCache(RenderCacheConfig{MaxBytes: 100}, logger)
+		require.NoError(t, err)
+
+		cache.put("key1", "ws1", "rev1", "manifest1")
+		cache.put("key2", "ws2", "rev1", "manifest2")
+
+		_, ok := cache.get("key3", "ws1", "rev2")
+		require.False(t, ok)
+		_, ok = cache.get("key1", "ws1", "rev1")
+		require.False(t, ok) //dropped because workspace 'ws1' has a new revision
+		_, ok = cache.get("key2", "ws2", "rev1")
+		require.True(t, ok)
+	})
+
+	t.Run("Keep manifests on disk", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, err := NewRenderCache(RenderCacheConfig{MaxBytes: 100, Dir: dir, DirMaxBytes: 100}, logger)
+		require.NoError(t, err)
+		cache.put("key1", "ws", "rev1", "manifest1")
+
+		diskHits := testutil.ToFloat64(renderCacheHits.WithLabelValues(renderTierDisk))
+		restartedCache, err := NewRenderCache(RenderCacheConfig{MaxBytes: 100, Dir: dir, DirMaxBytes: 100}, logger)
+		require.NoError(t, err)
+		manifest, ok := restartedCache.get("key1", "ws", "rev1")
+		require.True(t, ok)
+		require.Equal(t, "manifest1", manifest)
+		require.Equal(t, diskHits+1, testutil.ToFloat64(renderCacheHits.WithLabelValues(renderTierDisk)))
+
+		//oldest manifests are pruned on startup if the directory exceeds the limit
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "big"+renderCacheFileExt), []byte(strings.Repeat("x", 200)), 0600))
+		_, err = NewRenderCache(RenderCacheConfig{MaxBytes: 100, Dir: dir, DirMaxBytes: 100}, logger)
+		require.NoError(t, err)
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+	})
+
+	t.Run("Evict manifests from memory and disk independently", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, err := NewRenderCache(RenderCacheConfig{MaxBytes: 10, Dir: dir, DirMaxBytes: 25}, logger)
+		require.NoError(t, err)
+
+		cache.put("key1", "ws", "rev1", strings.Repeat("1", 10))
+		cache.put("key2", "ws", "rev1", strings.Repeat("2", 10)) //evicts key1 from memory
+		require.Equal(t, float64(10), testutil.ToFloat64(renderCacheBytes))
+
+		diskHits := testutil.ToFloat64(renderCacheHits.WithLabelValues(renderTierDisk))
+		manifest, ok := cache.get("key1", "ws", "rev1")
+		require.True(t, ok)
+		require.Equal(t, strings.Repeat("1", 10), manifest)
+		require.Equal(t, diskHits+1, testutil.ToFloat64(renderCacheHits.WithLabelValues(renderTierDisk)))
+
+		//key2 is the least recently used file
+		cache.put("key3", "ws", "rev1", strings.Repeat("3", 10))
+		require.NoFileExists(t, cache.diskFile("key2"))
+		require.FileExists(t, cache.diskFile("key1"))
+		require.FileExists(t, cache.diskFile("key3"))
+
+		//manifests which exceed the memory limit are still kept on disk
+		cache.put("key4", "ws", "rev1", strings.Repeat("4", 15))
+		require.FileExists(t, cache.diskFile("key4"))
+		manifest, ok = cache.get("key4", "ws", "rev1")
+		require.True(t, ok)
+		require.Equal(t, strings.Repeat("4", 15), manifest)
+		require.Equal(t, float64(10), testutil.ToFloat64(renderCacheBytes))
+	})
+
+	t.Run("Disabled cache", func(t *testing.T) {
+		var cache *RenderCache
+		require.False(t, cache.enabled())
+		cache, err := NewRenderCache(RenderCacheConfig{}, logger)
+		require.NoError(t, err)
+		require.False(t, cache.enabled())
+		_, err = NewRenderCache(RenderCacheConfig{MaxBytes: -1}, logger)
+		require.Error(t, err)
+		_, err = NewRenderCache(RenderCacheConfig{MaxBytes: 100, Dir: t.TempDir()}, logger)
+		require.Error(t, err)
+	})
+}
+
+func TestRenderCacheKey(t *testing.T) {
+	component := NewComponentBuilder("1.0.0", "mycomponent").WithProfile("evaluation").WithNamespace("kyma-system").Build()
+	key1, err := renderCacheKey("rev1", component, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": true}})
+	require.NoError(t, err)
+	key2, err := renderCacheKey("rev1", component, map[string]interface{}{"b": map[string]interface{}{"c": true}, "a": 1})
+	require.NoError(t, err)
+	require.Equal(t, key1, key2)
+
+	key3, err := renderCacheKey("rev2", component, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": true}})
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key3)
+
+	key4, err := renderCacheKey("rev1", component, map[string]interface{}{"a": 2, "b": map[string]interface{}{"c": true}})
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key4)
+}
+
+func TestRenderManifestWithCache(t *testing.T) {
+	logger := log.NewLogger(true)
+	chartDir := newValidationChart(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(chartDir, "mycomponent", "templates"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "mycomponent", "templates", "cm.yaml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  replicas: \"{{ .Values.replicas }}\"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, wsReadyIndicatorFile), []byte("revision: \"1\"\n"), 0600))
+
+	cache, err := NewRenderCache(RenderCacheConfig{MaxBytes: 1024 * 1024}, logger)
+	require.NoError(t, err)
+	provider := &DefaultProvider{logger: logger, renderCache: cache}
+	helmClient, err := NewHelmClient(chartDir, logger)
+	require.NoError(t, err)
+
+	render := func(replicas int) string {
+		component := NewComponentBuilder("1.0.0", "mycomponent").
+			WithNamespace("kyma-system").
+			WithConfiguration(map[string]interface{}{"replicas": replicas}).
+			Build()
+		manifest, err := provider.render(helmClient, chartDir, component)
+		require.NoError(t, err)
+		return manifest
+	}
+
+	misses := testutil.ToFloat64(renderCacheMisses)
+	hits := testutil.ToFloat64(renderCacheHits.WithLabelValues(renderTierMemory))
+
+	manifest := render(2)
+	require.Contains(t, manifest, `replicas: "2"`)
+	require.Equal(t, manifest, render(2))
+	require.Contains(t, render(3), `replicas: "3"`)
+	require.Equal(t, misses+2, testutil.ToFloat64(renderCacheMisses))
+	require.Equal(t, hits+1, testutil.ToFloat64(renderCacheHits.WithLabelValues(renderTierMemory)))
+
+	//refreshing the workspace invalidates the cached manifests
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, wsReadyIndicatorFile), []byte("revision: \"2\"\n"), 0600))
+	render(2)
+	require.Equal(t, misses+3, testutil.ToFloat64(renderCacheMisses))
+}
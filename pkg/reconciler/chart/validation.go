@@ -0,0 +1,218 @@
+package chart
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	schemaRootField = "(root)"
+	globalValuesKey = "global"
+)
+
+//reservedConfigKeys are configuration keys which are consumed by the reconciler and not passed to the chart
+var reservedConfigKeys = []string{
+	"repo.token.namespace",
+	ArchiveChecksumConfigKey,
+	ArchiveSignatureURLConfigKey,
+}
+
+//ValuesViolation is a value of the component configuration which is not accepted by the chart
+type ValuesViolation struct {
+	Path   string `json:"path"` //key path in dot-notation, e.g. 'global.domainName'
+	Reason string `json:"reason"`
+}
+
+//ValuesValidationError indicates that the configuration of a component is not accepted by its chart
+type ValuesValidationError struct {
+	Component  string            `json:"component"`
+	Violations []ValuesViolation `json:"violations"`
+}
+
+func (e *ValuesValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		violations = append(violations, fmt.Sprintf("'%s': %s", violation.Path, violation.Reason))
+	}
+	return fmt.Sprintf("configuration of component '%s' is invalid: %s", e.Component, strings.Join(violations, ", "))
+}
+
+//IsValuesValidationError returns true if the error (or one of its causes) is a ValuesValidationError
+func IsValuesValidationError(err error) bool {
+	var validationErr *ValuesValidationError
+	return errors.As(err, &validationErr)
+}
+
+//Validate verifies the configuration of the component against the values.schema.json files of the chart
+//and its sub-charts. In strict mode, configuration keys which are neither defined in the values of the chart
+//nor in its schema are rejected (global values are shared between all charts and are therefore ignored).
+func (c *HelmClient) Validate(component *Component, strict bool) error {
+	path, err := c.getPath(component)
+	if err != nil {
+		return err
+	}
+	helmChart, err := loader.Load(path)
+	if err != nil {
+		return errors.Wrap(err, "loader failed to load helm chart")
+	}
+
+	profileConfig, err := c.defaultConfiguration(helmChart, component, false)
+	if err != nil {
+		return err
+	}
+	defaults, err := chartutil.CoalesceValues(helmChart, profileConfig)
+	if err != nil {
+		return err
+	}
+
+	config, err := c.mergeChartConfiguration(helmChart, component, false)
+	if err != nil {
+		return errors.Wrap(err, "client failed to merge chart configuration")
+	}
+	values, err := chartutil.CoalesceValues(helmChart, config)
+	if err != nil {
+		return err
+	}
+
+	violations, err := validateSchema(helmChart, values, "")
+	if err != nil {
+		return err
+	}
+
+	if strict {
+		componentConfig, err := chartConfiguration(component)
+		if err != nil {
+			return err
+		}
+		schema, err := parseSchema(helmChart)
+		if err != nil {
+			return err
+		}
+		delete(componentConfig, globalValuesKey)
+		violations = append(violations, unknownKeys(componentConfig, defaults, schema, "")...)
+	}
+
+	if len(violations) > 0 {
+		return &ValuesValidationError{
+			Component:  component.name,
+			Violations: violations,
+		}
+	}
+	return nil
+}
+
+//chartConfiguration returns the configuration of the component without reconciler specific keys
+func chartConfiguration(component *Component) (map[string]interface{}, error) {
+	configuration := make(map[string]interface{}, len(component.configuration))
+	for key, value := range component.configuration {
+		configuration[key] = value
+	}
+	for _, key := range reservedConfigKeys {
+		delete(configuration, key)
+	}
+	return (&Component{configuration: configuration}).Configuration()
+}
+
+func validateSchema(ch *chart.Chart, values map[string]interface{}, prefix string) ([]ValuesViolation, error) {
+	var violations []ValuesViolation
+	if len(ch.Schema) > 0 {
+		valuesJSON, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(ch.Schema), gojsonschema.NewBytesLoader(valuesJSON))
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to validate values against schema of chart '%s'", ch.Name()))
+		}
+		for _, resultErr := range result.Errors() {
+			field := resultErr.Field()
+			if field == schemaRootField {
+				field = ""
+			}
+			violations = append(violations, ValuesViolation{
+				Path:   joinKeyPath(prefix, field),
+				Reason: resultErr.Description(),
+			})
+		}
+	}
+
+	for _, dependency := range ch.Dependencies() {
+		dependencyValues, ok := values[dependency.Name()].(map[string]interface{})
+		if !ok {
+			dependencyValues = make(map[string]interface{})
+		}
+		dependencyViolations, err := validateSchema(dependency, dependencyValues, joinKeyPath(prefix, dependency.Name()))
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, dependencyViolations...)
+	}
+	return violations, nil
+}
+
+func parseSchema(ch *chart.Chart) (map[string]interface{}, error) {
+	if len(ch.Schema) == 0 {
+		return nil, nil
+	}
+	schema := make(map[string]interface{})
+	if err := json.Unmarshal(ch.Schema, &schema); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to parse schema of chart '%s'", ch.Name()))
+	}
+	return schema, nil
+}
+
+//unknownKeys returns the configuration keys which are neither defined in the chart values nor in the schema
+func unknownKeys(config, defaults, schema map[string]interface{}, prefix string) []ValuesViolation {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations []ValuesViolation
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, key := range keys {
+		keyPath := joinKeyPath(prefix, key)
+		defaultValue, inDefaults := defaults[key]
+		propertySchema, inSchema := properties[key].(map[string]interface{})
+		if !inDefaults && !inSchema {
+			violations = append(violations, ValuesViolation{
+				Path:   keyPath,
+				Reason: "key is not defined in the values or the schema of the chart",
+			})
+			continue
+		}
+
+		//only descend if the structure below the key is known (empty maps are free-form values)
+		nestedConfig, ok := config[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		nestedDefaults, _ := defaultValue.(map[string]interface{})
+		_, hasProperties := propertySchema["properties"]
+		if len(nestedDefaults) == 0 && !hasProperties {
+			continue
+		}
+		violations = append(violations, unknownKeys(nestedConfig, nestedDefaults, propertySchema, keyPath)...)
+	}
+	return violations
+}
+
+func joinKeyPath(prefix, key string) string {
+	switch {
+	case prefix == "":
+		return key
+	case key == "":
+		return prefix
+	default:
+		return prefix + "." + key
+	}
+}
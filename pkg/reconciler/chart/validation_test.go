@@ -0,0 +1,131 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validationChartValues = `
+replicas: 1
+image:
+  repository: kyma/component
+  tag: 1.0.0
+annotations: {}
+sub:
+  enabled: true
+`
+	validationChartSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1},
+    "image": {
+      "type": "object",
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string"},
+        "pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent"]}
+      }
+    }
+  }
+}`
+	validationSubChartSchema = `{
+  "type": "object",
+  "properties": {
+    "enabled": {"type": "boolean"}
+  }
+}`
+)
+
+func newValidationChart(t *testing.T) string {
+	chartsDir := t.TempDir()
+	writeFile := func(path, content string) {
+		path = filepath.Join(chartsDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	writeFile("mycomponent/Chart.yaml", "apiVersion: v2\nname: mycomponent\nversion: 1.0.0\n")
+	writeFile("mycomponent/values.yaml", validationChartValues)
+	writeFile("mycomponent/values.schema.json", validationChartSchema)
+	writeFile("mycomponent/profile-evaluation.yaml", "replicas: 1\nprofileOnly: true\n")
+	writeFile("mycomponent/charts/sub/Chart.yaml", "apiVersion: v2\nname: sub\nversion: 1.0.0\n")
+	writeFile("mycomponent/charts/sub/values.yaml", "enabled: true\n")
+	writeFile("mycomponent/charts/sub/values.schema.json", validationSubChartSchema)
+	return chartsDir
+}
+
+func TestValuesValidation(t *testing.T) {
+	helmClient, err := NewHelmClient(newValidationChart(t), log.NewLogger(true))
+	require.NoError(t, err)
+
+	validate := func(configuration map[string]interface{}, strict bool) *ValuesValidationError {
+		component := NewComponentBuilder("1.0.0", "mycomponent").
+			WithProfile("evaluation").
+			WithConfiguration(configuration).
+			Build()
+		err := helmClient.Validate(component, strict)
+		if err == nil {
+			return nil
+		}
+		require.True(t, IsValuesValidationError(err), err.Error())
+		validationErr := &ValuesValidationError{}
+		require.True(t, errors.As(err, &validationErr))
+		require.Equal(t, "mycomponent", validationErr.Component)
+		return validationErr
+	}
+
+	t.Run("Valid configuration", func(t *testing.T) {
+		require.Nil(t, validate(map[string]interface{}{
+			"replicas":                   3,
+			"image.tag":                  "2.0.0",
+			"image.pullPolicy":           "Always", //only defined in schema
+			"annotations.custom":         "value",  //free-form map
+			"profileOnly":                false,    //defined in profile
+			"global.domainName":          "example.com",
+			"repo.token.namespace":       "default",
+			ArchiveChecksumConfigKey:     "abc",
+			ArchiveSignatureURLConfigKey: "https://example.com/sig",
+		}, true))
+	})
+
+	t.Run("Schema violations", func(t *testing.T) {
+		validationErr := validate(map[string]interface{}{
+			"replicas":         "three",
+			"image.pullPolicy": "Never",
+			"sub.enabled":      "yes",
+		}, false)
+		require.NotNil(t, validationErr)
+		var paths []string
+		for _, violation := range validationErr.Violations {
+			paths = append(paths, violation.Path)
+		}
+		require.ElementsMatch(t, []string{"replicas", "image.pullPolicy", "sub.enabled"}, paths)
+	})
+
+	t.Run("Unknown keys are only rejected in strict mode", func(t *testing.T) {
+		configuration := map[string]interface{}{
+			"replica":          2,
+			"image.repsitory":  "kyma/other",
+			"sub.unknown":      true,
+			"global.whatever":  true,
+			"annotations.free": "value",
+		}
+		require.Nil(t, validate(configuration, false))
+
+		validationErr := validate(configuration, true)
+		require.NotNil(t, validationErr)
+		require.Equal(t, []ValuesViolation{
+			{Path: "image.repsitory", Reason: "key is not defined in the values or the schema of the chart"},
+			{Path: "replica", Reason: "key is not defined in the values or the schema of the chart"},
+			{Path: "sub.unknown", Reason: "key is not defined in the values or the schema of the chart"},
+		}, validationErr.Violations)
+		require.Contains(t, validationErr.Error(), "component 'mycomponent'")
+		require.Contains(t, validationErr.Error(), "'image.repsitory'")
+	})
+}
@@ -0,0 +1,312 @@
+package chart
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/pkg/errors"
+)
+
+const (
+	//configuration keys of a component which enable the verification of a downloaded archive
+	ArchiveChecksumConfigKey     = "archive.sha256"
+	ArchiveSignatureURLConfigKey = "archive.signature.url"
+
+	pgpPublicKeyHeader    = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	pgpSignatureHeader    = "-----BEGIN PGP SIGNATURE-----"
+	cosignSignatureSuffix = ".sig"
+	pgpSignatureSuffix    = ".asc"
+	maxSignatureSize      = 64 * 1024
+	maxTrustedKeySize     = 64 * 1024
+)
+
+//VerificationError indicates that a downloaded archive failed the integrity verification
+type VerificationError struct {
+	Component string
+	URL       string
+	Reason    string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("integrity verification of archive '%s' of component '%s' failed: %s",
+		e.URL, e.Component, e.Reason)
+}
+
+//IsVerificationError returns true if the error (or one of its causes) is a VerificationError
+func IsVerificationError(err error) bool {
+	var verificationErr *VerificationError
+	return errors.As(err, &verificationErr)
+}
+
+//LoadTrustedKeys reads the public keys (PGP or PEM encoded) which are trusted to sign downloaded archives. The path
+//can point to a single key file or to a directory (e.g. a mounted secret) where each file contains one key.
+func LoadTrustedKeys(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read trusted keys")
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read trusted keys from directory '%s'", path)
+		}
+		files = nil
+		for _, entry := range entries {
+			//skip hidden files like the '..data' links of mounted secrets
+			if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	var keys []string
+	for _, keyFile := range files {
+		key, err := readTrustedKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no trusted keys found in '%s'", path)
+	}
+	return keys, nil
+}
+
+func readTrustedKey(keyFile string) (string, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read trusted key '%s'", keyFile)
+	}
+	if len(data) > maxTrustedKeySize {
+		return "", fmt.Errorf("trusted key '%s' exceeds the maximal size of %d bytes", keyFile, maxTrustedKeySize)
+	}
+	key := strings.TrimSpace(string(data))
+	if isPGPKey(key) {
+		if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key)); err != nil {
+			return "", errors.Wrapf(err, "trusted key '%s' is not a valid PGP public key", keyFile)
+		}
+		return key, nil
+	}
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return "", fmt.Errorf("trusted key '%s' is neither a PGP key nor a PEM encoded key", keyFile)
+	}
+	if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		return "", errors.Wrapf(err, "trusted key '%s' is not a valid public key", keyFile)
+	}
+	return key, nil
+}
+
+func isPGPKey(key string) bool {
+	return strings.HasPrefix(key, pgpPublicKeyHeader)
+}
+
+//archiveVerification defines how the integrity of an archive is verified
+type archiveVerification struct {
+	component    *Component
+	checksum     string
+	signatureURL string
+}
+
+func newArchiveVerification(component *Component) *archiveVerification {
+	return &archiveVerification{
+		component:    component,
+		checksum:     strings.ToLower(strings.TrimPrefix(configString(component, ArchiveChecksumConfigKey), "sha256:")),
+		signatureURL: configString(component, ArchiveSignatureURLConfigKey),
+	}
+}
+
+func configString(component *Component, key string) string {
+	value, ok := component.configuration[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+//signatureURLOf returns the location of the signature created with the given key
+func (v *archiveVerification) signatureURLOf(key string) string {
+	//signatures of OCI artifacts aren't located next to the artifact and have to be configured explicitly
+	if v.signatureURL != "" || v.component.isExternalOCIComponent() {
+		return v.signatureURL
+	}
+	if isPGPKey(key) {
+		return v.component.url + pgpSignatureSuffix
+	}
+	return v.component.url + cosignSignatureSuffix
+}
+
+//verifyArchive checks the checksum of the archive (if configured) and its signature (if trusted keys are configured)
+func (f *DefaultFactory) verifyArchive(component *Component, archiveFile string) error {
+	verification := newArchiveVerification(component)
+	newErr := func(reason string, args ...interface{}) error {
+		return &VerificationError{
+			Component: component.name,
+			URL:       component.url,
+			Reason:    fmt.Sprintf(reason, args...),
+		}
+	}
+
+	if verification.checksum == "" && len(f.trustedKeys) == 0 {
+		f.logger.Debugf("Integrity verification of archive '%s' is not configured", component.url)
+		return nil
+	}
+
+	digest, err := fileDigest(archiveFile)
+	if err != nil {
+		return err
+	}
+
+	if verification.checksum != "" {
+		if hex.EncodeToString(digest) != verification.checksum {
+			return newErr("sha256 checksum is '%x' but expected was '%s'", digest, verification.checksum)
+		}
+		f.logger.Debugf("Checksum of archive '%s' verified", component.url)
+	}
+
+	if len(f.trustedKeys) > 0 {
+		if err := verification.verifySignature(archiveFile, digest, f.trustedKeys); err != nil {
+			return newErr("%s", err)
+		}
+		f.logger.Debugf("Signature of archive '%s' verified", component.url)
+	}
+
+	return nil
+}
+
+//verifySignature succeeds if the signature of the archive was created by one of the trusted keys
+func (v *archiveVerification) verifySignature(archiveFile string, digest []byte, trustedKeys []string) error {
+	signatures := make(map[string][]byte)
+	var failures []string
+	for _, key := range trustedKeys {
+		signatureURL := v.signatureURLOf(key)
+		if signatureURL == "" {
+			return fmt.Errorf("signature URL has to be configured with '%s'", ArchiveSignatureURLConfigKey)
+		}
+		signature, ok := signatures[signatureURL]
+		if !ok {
+			var err error
+			signature, err = downloadSignature(signatureURL)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("failed to download signature '%s': %s", signatureURL, err))
+				continue
+			}
+			signatures[signatureURL] = signature
+		}
+
+		var err error
+		if isPGPKey(key) {
+			err = verifyPGPSignature(archiveFile, signature, key)
+		} else {
+			err = verifyCosignSignature(archiveFile, digest, signature, key)
+		}
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("signature '%s' is invalid: %s", signatureURL, err))
+	}
+	return fmt.Errorf("archive is not signed by a trusted key: %s", strings.Join(failures, ", "))
+}
+
+//fileDigest returns the sha256 checksum of a file without loading it into memory
+func fileDigest(fileName string) ([]byte, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+func downloadSignature(URL string) ([]byte, error) {
+	resp, err := downloadClient.Get(URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxSignatureSize))
+}
+
+//verifyPGPSignature verifies a detached (armored or binary) PGP signature
+func verifyPGPSignature(archiveFile string, signature []byte, publicKey string) error {
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to read PGP public key")
+	}
+	data, err := os.Open(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte(pgpSignatureHeader)) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyRing, data, bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyRing, data, bytes.NewReader(signature), nil)
+	}
+	return err
+}
+
+//verifyCosignSignature verifies a base64 encoded signature created by 'cosign sign-blob' using a key pair
+func verifyCosignSignature(archiveFile string, digest, signature []byte, publicKey string) error {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return fmt.Errorf("public key is neither a PGP key nor a PEM encoded key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse public key")
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return errors.Wrap(err, "signature is not base64 encoded")
+	}
+
+	switch pubKey := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pubKey, digest, rawSignature) {
+			return fmt.Errorf("ECDSA signature does not match")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pubKey, crypto.SHA256, digest, rawSignature); err != nil {
+			return err
+		}
+	case ed25519.PublicKey:
+		//ed25519 signs the whole message: the archive size is bounded by the download limit
+		data, err := ioutil.ReadFile(archiveFile)
+		if err != nil {
+			return err
+		}
+		if !ed25519.Verify(pubKey, data, rawSignature) {
+			return fmt.Errorf("ed25519 signature does not match")
+		}
+	default:
+		return fmt.Errorf("public key type '%T' is not supported", key)
+	}
+	return nil
+}
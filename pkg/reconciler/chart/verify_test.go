@@ -0,0 +1,176 @@
+package chart
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveVerification(t *testing.T) {
+	logger := log.NewLogger(true)
+
+	archiveFile := newTarGzArchive(t,
+		archiveEntry{name: "mychart/", typeflag: tar.TypeDir},
+		archiveEntry{name: "mychart/Chart.yaml", content: "apiVersion: v2\nname: mychart\nversion: 1.0.0\n"})
+	archive, err := os.ReadFile(archiveFile)
+	require.NoError(t, err)
+	checksum := fmt.Sprintf("%x", sha256.Sum256(archive))
+
+	//cosign compatible key pair and signature
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecdsaPubKey, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	require.NoError(t, err)
+	cosignPublicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecdsaPubKey}))
+	digest := sha256.Sum256(archive)
+	rawSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	require.NoError(t, err)
+	cosignSignature := base64.StdEncoding.EncodeToString(rawSignature)
+
+	//PGP key pair and armored detached signature
+	entity, err := openpgp.NewEntity("test", "", "test@kyma-project.io", nil)
+	require.NoError(t, err)
+	pgpPublicKey := &bytes.Buffer{}
+	armorWriter, err := armor.Encode(pgpPublicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(armorWriter))
+	require.NoError(t, armorWriter.Close())
+	pgpSignature := &bytes.Buffer{}
+	require.NoError(t, openpgp.ArmoredDetachSign(pgpSignature, entity, bytes.NewReader(archive), nil))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mychart.tgz":
+			_, _ = w.Write(archive)
+		case "/mychart.tgz.sig":
+			_, _ = w.Write([]byte(cosignSignature))
+		case "/mychart.tgz.asc":
+			_, _ = w.Write(pgpSignature.Bytes())
+		case "/invalid.sig":
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString([]byte("invalid"))))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	getComponent := func(t *testing.T, trustedKeys []string, config map[string]interface{}) (*Workspace, error) {
+		factory := (&DefaultFactory{logger: logger, storageDir: t.TempDir()}).WithTrustedKeys(trustedKeys)
+		component := NewComponentBuilder("1.0.0", "mychart").
+			WithURL(server.URL + "/mychart.tgz").
+			WithConfiguration(config).
+			Build()
+		return factory.GetExternalComponent(component)
+	}
+
+	otherEntity, err := openpgp.NewEntity("other", "", "other@kyma-project.io", nil)
+	require.NoError(t, err)
+	otherPublicKey := &bytes.Buffer{}
+	armorWriter, err = armor.Encode(otherPublicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, otherEntity.Serialize(armorWriter))
+	require.NoError(t, armorWriter.Close())
+
+	t.Run("Verify checksum", func(t *testing.T) {
+		ws, err := getComponent(t, nil, map[string]interface{}{ArchiveChecksumConfigKey: "sha256:" + checksum})
+		require.NoError(t, err)
+		require.True(t, file.Exists(filepath.Join(ws.WorkspaceDir, "mychart", "Chart.yaml")))
+
+		_, err = getComponent(t, nil, map[string]interface{}{ArchiveChecksumConfigKey: fmt.Sprintf("%x", sha256.Sum256(nil))})
+		require.Error(t, err)
+		require.True(t, IsVerificationError(err))
+	})
+
+	t.Run("Verify cosign signature", func(t *testing.T) {
+		_, err := getComponent(t, []string{cosignPublicKey}, nil)
+		require.NoError(t, err)
+
+		_, err = getComponent(t, []string{cosignPublicKey}, map[string]interface{}{
+			ArchiveSignatureURLConfigKey: server.URL + "/invalid.sig",
+		})
+		require.Error(t, err)
+		require.True(t, IsVerificationError(err))
+	})
+
+	t.Run("Verify PGP signature", func(t *testing.T) {
+		_, err := getComponent(t, []string{pgpPublicKey.String()}, map[string]interface{}{
+			ArchiveChecksumConfigKey: checksum,
+		})
+		require.NoError(t, err)
+
+		_, err = getComponent(t, []string{otherPublicKey.String()}, nil)
+		require.Error(t, err)
+		require.True(t, IsVerificationError(err))
+	})
+
+	t.Run("Any trusted key is accepted", func(t *testing.T) {
+		_, err := getComponent(t, []string{otherPublicKey.String(), cosignPublicKey}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("Missing signature fails verification", func(t *testing.T) {
+		_, err := getComponent(t, []string{cosignPublicKey}, map[string]interface{}{
+			ArchiveSignatureURLConfigKey: server.URL + "/missing.sig",
+		})
+		require.Error(t, err)
+		require.True(t, IsVerificationError(err))
+	})
+
+	t.Run("Reject archive exceeding the size limit", func(t *testing.T) {
+		limits := defaultArchiveLimits
+		defer func() {
+			defaultArchiveLimits = limits
+		}()
+		defaultArchiveLimits.maxBytes = int64(len(archive) - 1)
+
+		_, err := getComponent(t, nil, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds the maximal size")
+	})
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecdsaPubKey, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecdsaPubKey}))
+
+	t.Run("Load keys from secret directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cosign.pub"), []byte(publicKey), 0600))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0700))
+		keys, err := LoadTrustedKeys(dir)
+		require.NoError(t, err)
+		require.Equal(t, []string{strings.TrimSpace(publicKey)}, keys)
+	})
+
+	t.Run("Reject invalid keys", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "invalid.pub")
+		require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0600))
+		_, err := LoadTrustedKeys(keyFile)
+		require.Error(t, err)
+
+		_, err = LoadTrustedKeys(t.TempDir())
+		require.Error(t, err)
+	})
+}
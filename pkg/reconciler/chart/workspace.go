@@ -15,8 +15,19 @@ const (
 	instResCrdDir = "installation/resources/crds"
 )
 
+//kymaWorkspaceDirs are the directories of the Kyma sources required by a Kyma workspace
+var kymaWorkspaceDirs = []string{resDir, instResDir, instResCrdDir}
+
 type Workspace struct {
 	WorkspaceDir string
+	release      func()
+}
+
+//Release marks the workspace as no longer used, so it can be evicted from the workspace cache
+func (ws *Workspace) Release() {
+	if ws != nil && ws.release != nil {
+		ws.release()
+	}
 }
 
 func (ws *Workspace) delete() error {
@@ -44,28 +55,28 @@ var validateDir = func(w *Workspace) error {
 	return nil
 }
 
-func containsFile(filename string) func(*Workspace) error {
-	return func(w *Workspace) error {
-		found := false
-		err := filepath.WalkDir(w.WorkspaceDir, func(path string, d fs.DirEntry, err error) error {
-			if filepath.Base(path) == filename {
-				found = true
-			}
-			return nil
-		})
-		if !found {
-			return fmt.Errorf("Failed to find %v in %v", filename, w.WorkspaceDir)
-		}
-		if err != nil {
+//containsComponent verifies that the workspace contains a Helm chart, a kustomization or plain manifests
+var containsComponent = func(w *Workspace) error {
+	found := false
+	err := filepath.WalkDir(w.WorkspaceDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || found || d.IsDir() || d.Name() == wsReadyIndicatorFile {
 			return err
 		}
+		ext := filepath.Ext(path)
+		found = d.Name() == "Kustomization" || ext == ".yaml" || ext == ".yml"
 		return nil
+	})
+	if err != nil {
+		return err
 	}
+	if !found {
+		return fmt.Errorf("Failed to find a Helm chart, a kustomization or manifests in %v", w.WorkspaceDir)
+	}
+	return nil
 }
 
 func newComponentWorkspace(workspaceDir string) (*Workspace, error) {
-	validateChart := containsFile("Chart.yaml")
-	return newWorkspace(workspaceDir, validateDir, validateChart)
+	return newWorkspace(workspaceDir, validateDir, containsComponent)
 }
 
 type KymaWorkspace struct {
@@ -0,0 +1,405 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitSSH "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// keys of the credential secrets
+const (
+	secretKeyToken                   = "token"
+	secretKeySSHPrivateKey           = "sshPrivateKey"
+	secretKeySSHPassphrase           = "sshPassphrase"
+	secretKeySSHKnownHosts           = "knownHosts"
+	secretKeyGitHubAppID             = "githubAppID"
+	secretKeyGitHubAppInstallationID = "githubAppInstallationID"
+	secretKeyGitHubAppPrivateKey     = "githubAppPrivateKey"
+	secretKeyGitHubAPIURL            = "githubAPIURL"
+)
+
+const (
+	defaultSSHUser          = "git"
+	tokenUsername           = "xxx" //anything but an empty string
+	githubAppTokenUsername  = "x-access-token"
+	githubAPIURL            = "https://api.github.com"
+	githubHost              = "github.com"
+	githubJWTLifetime       = 9 * time.Minute //GitHub accepts JWTs with a lifetime of max. 10 minutes
+	githubTokenExpiryMargin = 1 * time.Minute
+)
+
+var (
+	//scpURLPattern matches SCP-like repository URLs, e.g. 'git@github.com:kyma-project/kyma.git'
+	scpURLPattern       = regexp.MustCompile(`^(?:([^@/]+)@)?([^:/]+):(.*)$`)
+	portPattern         = regexp.MustCompile(`^\d+(/|$)`)
+	invalidSecretChars  = regexp.MustCompile(`[^a-z0-9.-]+`)
+	githubHTTPClient    = &http.Client{Timeout: 30 * time.Second}
+	installationTokens  = make(map[string]*cachedInstallationToken) //cache of GitHub App installation tokens
+	installationTokenMu sync.Mutex                                  //guards the cache and the cached tokens
+)
+
+// cachedInstallationToken is the cache entry of a GitHub App installation. Its lock serializes the exchange of
+// tokens of the installation without blocking other installations.
+type cachedInstallationToken struct {
+	token      *installationToken
+	exchangeMu sync.Mutex
+}
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (t *installationToken) valid(now time.Time) bool {
+	return t != nil && t.Token != "" && now.Add(githubTokenExpiryMargin).Before(t.ExpiresAt)
+}
+
+func (r *Cloner) buildAuth() (transport.AuthMethod, error) {
+	if r.inClusterClientSet == nil {
+		return nil, nil
+	}
+
+	secret, err := r.credentialSecret()
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		r.logger.Info("Token not found or forbidden")
+		return nil, nil
+	}
+	return r.authFromSecret(secret)
+}
+
+// credentialSecret returns the secret with the credentials of the repository. A secret which is specific for
+// the repository (e.g. 'github.com.kyma-project.kyma') is preferred over the secret of the host (e.g. 'github.com').
+func (r *Cloner) credentialSecret() (*v1.Secret, error) {
+	tokenNamespace := "default"
+	if r.repo.TokenNamespace != "" {
+		tokenNamespace = r.repo.TokenNamespace
+	}
+
+	hostKey, err := mapSecretKey(r.repo.URL)
+	if err != nil {
+		return nil, err
+	}
+	secretKeys := []string{hostKey}
+	if repoKey, err := mapRepositorySecretKey(r.repo.URL); err == nil && repoKey != hostKey {
+		secretKeys = []string{repoKey, hostKey}
+	}
+
+	for _, secretKey := range secretKeys {
+		secret, err := r.inClusterClientSet.CoreV1().
+			Secrets(tokenNamespace).
+			Get(context.Background(), secretKey, metav1.GetOptions{})
+		if err == nil && secret != nil {
+			r.logger.Debugf("Using credentials of secret '%s:%s' for repository '%s'",
+				tokenNamespace, secretKey, r.repo.URL)
+			return secret, nil
+		}
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Cloner) authFromSecret(secret *v1.Secret) (transport.AuthMethod, error) {
+	switch {
+	case len(secret.Data[secretKeySSHPrivateKey]) > 0:
+		return r.sshAuth(secret)
+	case len(secret.Data[secretKeyGitHubAppPrivateKey]) > 0:
+		token, err := r.githubAppToken(secret)
+		if err != nil {
+			return nil, err
+		}
+		return &gitHttp.BasicAuth{
+			Username: githubAppTokenUsername,
+			Password: token,
+		}, nil
+	default:
+		return &gitHttp.BasicAuth{
+			Username: tokenUsername,
+			Password: strings.Trim(string(secret.Data[secretKeyToken]), "\n"),
+		}, nil
+	}
+}
+
+// sshAuth returns the SSH key authentication. Host keys are verified against the known hosts of the secret or,
+// if the secret defines no known hosts, against the known_hosts files of the system.
+func (r *Cloner) sshAuth(secret *v1.Secret) (transport.AuthMethod, error) {
+	repoURL, err := parseRepositoryURL(r.repo.URL)
+	if err != nil {
+		return nil, err
+	}
+	user := defaultSSHUser
+	if repoURL.User != nil && repoURL.User.Username() != "" {
+		user = repoURL.User.Username()
+	}
+
+	auth, err := gitSSH.NewPublicKeys(user, secret.Data[secretKeySSHPrivateKey],
+		strings.TrimSpace(string(secret.Data[secretKeySSHPassphrase])))
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to parse SSH private key of secret '%s'", secret.Name))
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	if knownHosts := secret.Data[secretKeySSHKnownHosts]; len(knownHosts) > 0 {
+		hostKeyCallback, err = knownHostsCallback(knownHosts)
+	} else {
+		hostKeyCallback, err = gitSSH.NewKnownHostsCallback()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load known hosts for SSH host key verification")
+	}
+	auth.HostKeyCallback = hostKeyCallback
+	return auth, nil
+}
+
+func knownHostsCallback(knownHosts []byte) (ssh.HostKeyCallback, error) {
+	//the knownhosts package reads files only: the file is removed after it was parsed
+	knownHostsFile, err := ioutil.TempFile("", "known_hosts-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(knownHostsFile.Name())
+	}()
+	if _, err := knownHostsFile.Write(knownHosts); err != nil {
+		_ = knownHostsFile.Close()
+		return nil, err
+	}
+	if err := knownHostsFile.Close(); err != nil {
+		return nil, err
+	}
+	return knownhosts.New(knownHostsFile.Name())
+}
+
+// githubAppToken returns an installation token of the GitHub App. Tokens are cached until they expire.
+func (r *Cloner) githubAppToken(secret *v1.Secret) (string, error) {
+	appID := strings.TrimSpace(string(secret.Data[secretKeyGitHubAppID]))
+	installationID := strings.TrimSpace(string(secret.Data[secretKeyGitHubAppInstallationID]))
+	if appID == "" || installationID == "" {
+		return "", fmt.Errorf("secret '%s' has to define '%s' and '%s' to authenticate as GitHub App",
+			secret.Name, secretKeyGitHubAppID, secretKeyGitHubAppInstallationID)
+	}
+	apiURL := strings.TrimSuffix(strings.TrimSpace(string(secret.Data[secretKeyGitHubAPIURL])), "/")
+	if apiURL == "" {
+		apiURL, _ = githubAPIURLOf(r.repo.URL)
+	}
+
+	cached := cachedInstallationTokenOf(fmt.Sprintf("%s|%s|%s", apiURL, appID, installationID))
+	if token := cached.get(); token.valid(time.Now()) {
+		return token.Token, nil
+	}
+	cached.exchangeMu.Lock()
+	defer cached.exchangeMu.Unlock()
+	if token := cached.get(); token.valid(time.Now()) { //exchanged by a concurrent request
+		return token.Token, nil
+	}
+
+	jwt, err := githubAppJWT(appID, secret.Data[secretKeyGitHubAppPrivateKey], time.Now())
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to create JWT for GitHub App '%s'", appID))
+	}
+	token, err := exchangeInstallationToken(apiURL, installationID, jwt)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to retrieve installation token of GitHub App '%s'", appID))
+	}
+	r.logger.Debugf("Retrieved installation token of GitHub App '%s' (installation: %s) which expires at %s",
+		appID, installationID, token.ExpiresAt.Format(time.RFC3339))
+	cached.set(token)
+	return token.Token, nil
+}
+
+func cachedInstallationTokenOf(cacheKey string) *cachedInstallationToken {
+	installationTokenMu.Lock()
+	defer installationTokenMu.Unlock()
+	cached, ok := installationTokens[cacheKey]
+	if !ok {
+		cached = &cachedInstallationToken{}
+		installationTokens[cacheKey] = cached
+	}
+	return cached
+}
+
+func (c *cachedInstallationToken) get() *installationToken {
+	installationTokenMu.Lock()
+	defer installationTokenMu.Unlock()
+	return c.token
+}
+
+func (c *cachedInstallationToken) set(token *installationToken) {
+	installationTokenMu.Lock()
+	defer installationTokenMu.Unlock()
+	c.token = token
+}
+
+// evictRejectedToken drops the cached installation token used by the authentication if the Git server rejected
+// it (e.g. because the token was revoked). The next request retrieves a new token. The error is returned unchanged.
+func evictRejectedToken(auth transport.AuthMethod, err error) error {
+	if !errors.Is(err, transport.ErrAuthenticationRequired) && !errors.Is(err, transport.ErrAuthorizationFailed) {
+		return err
+	}
+	basicAuth, ok := auth.(*gitHttp.BasicAuth)
+	if !ok || basicAuth.Username != githubAppTokenUsername {
+		return err
+	}
+	installationTokenMu.Lock()
+	defer installationTokenMu.Unlock()
+	for _, cached := range installationTokens {
+		if cached.token != nil && cached.token.Token == basicAuth.Password {
+			cached.token = nil
+		}
+	}
+	return err
+}
+
+// githubAppJWT returns the JWT (signed with RS256) used to authenticate as GitHub App
+func githubAppJWT(appID string, privateKey []byte, now time.Time) (string, error) {
+	key, err := parseRSAPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-1 * time.Minute).Unix(), //tolerate clock drift
+		"exp": now.Add(githubJWTLifetime).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func exchangeInstallationToken(apiURL, installationID, jwt string) (*installationToken, error) {
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/app/installations/%s/access_tokens", apiURL, url.PathEscape(installationID)), bytes.NewBuffer(nil))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := githubHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API responded with HTTP code %d: %s", resp.StatusCode, string(body))
+	}
+	token := &installationToken{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return nil, errors.Wrap(err, "failed to parse installation token")
+	}
+	if token.Token == "" {
+		return nil, errors.New("GitHub API returned an empty installation token")
+	}
+	return token, nil
+}
+
+// githubAPIURLOf returns the API URL of the GitHub instance hosting the repository
+func githubAPIURLOf(repoURL string) (string, error) {
+	parsed, err := parseRepositoryURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimPrefix(parsed.Hostname(), "www.")
+	if host == githubHost {
+		return githubAPIURL, nil
+	}
+	return fmt.Sprintf("https://%s/api/v3", host), nil //GitHub Enterprise
+}
+
+// parseRepositoryURL parses HTTP(S), SSH and SCP-like repository URLs. URLs without scheme are handled as HTTPS URLs.
+func parseRepositoryURL(repoURL string) (*url.URL, error) {
+	if !strings.Contains(repoURL, "://") {
+		if match := scpURLPattern.FindStringSubmatch(repoURL); match != nil && !portPattern.MatchString(match[3]) {
+			sshURL := &url.URL{Scheme: "ssh", Host: match[2], Path: "/" + strings.TrimPrefix(match[3], "/")}
+			if match[1] != "" {
+				sshURL.User = url.User(match[1])
+			}
+			return sshURL, nil
+		}
+		repoURL = "https://" + repoURL
+	}
+	return url.Parse(repoURL)
+}
+
+// mapRepositorySecretKey returns the name of the secret which contains the credentials for a particular
+// repository: the host followed by the path segments, e.g. 'github.com.kyma-project.kyma'
+func mapRepositorySecretKey(repoURL string) (string, error) {
+	parsed, err := parseRepositoryURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	segments := []string{strings.TrimPrefix(parsed.Hostname(), "www.")}
+	for _, segment := range strings.Split(strings.TrimSuffix(parsed.Path, ".git"), "/") {
+		segment = strings.Trim(invalidSecretChars.ReplaceAllString(strings.ToLower(segment), "-"), "-.")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "."), nil
+}
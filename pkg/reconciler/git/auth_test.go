@@ -0,0 +1,474 @@
+package git
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRepositoryURL(t *testing.T) {
+	t.Run("Should parse repository URLs", func(t *testing.T) {
+		testCases := []struct {
+			url    string
+			scheme string
+			host   string
+			user   string
+			path   string
+		}{
+			{"git@github.com:kyma-project/kyma.git", "ssh", "github.com", "git", "/kyma-project/kyma.git"},
+			{"github.com:kyma-project/kyma.git", "ssh", "github.com", "", "/kyma-project/kyma.git"},
+			{"ssh://git@github.com:2222/kyma-project/kyma.git", "ssh", "github.com", "git", "/kyma-project/kyma.git"},
+			{"https://github.com/kyma-project/kyma", "https", "github.com", "", "/kyma-project/kyma"},
+			{"github.com/kyma-project/kyma", "https", "github.com", "", "/kyma-project/kyma"},
+			{"localhost:8080/kyma", "https", "localhost", "", "/kyma"},
+		}
+		for _, testCase := range testCases {
+			parsed, err := parseRepositoryURL(testCase.url)
+			require.NoError(t, err)
+			require.Equal(t, testCase.scheme, parsed.Scheme, testCase.url)
+			require.Equal(t, testCase.host, parsed.Hostname(), testCase.url)
+			require.Equal(t, testCase.user, parsed.User.Username(), testCase.url)
+			require.Equal(t, testCase.path, parsed.Path, testCase.url)
+		}
+	})
+
+	t.Run("Should map URLs to secret names", func(t *testing.T) {
+		assertParsed(t, "github.com", "git@github.com:kyma-project/kyma.git")
+		assertParsed(t, "github.com", "ssh://git@www.github.com:2222/kyma-project/kyma.git")
+
+		for url, expected := range map[string]string{
+			"git@github.com:kyma-project/kyma.git":         "github.com.kyma-project.kyma",
+			"https://www.github.com/Kyma-Project/kyma.git": "github.com.kyma-project.kyma",
+			"github.com/kyma_project/kyma/":                "github.com.kyma-project.kyma",
+			"https://github.com":                           "github.com",
+		} {
+			key, err := mapRepositorySecretKey(url)
+			require.NoError(t, err)
+			require.Equal(t, expected, key, url)
+		}
+	})
+
+	t.Run("Should resolve GitHub API URL", func(t *testing.T) {
+		apiURL, err := githubAPIURLOf("git@github.com:kyma-project/kyma.git")
+		require.NoError(t, err)
+		require.Equal(t, "https://api.github.com", apiURL)
+
+		apiURL, err = githubAPIURLOf("https://github.tools.example.com/kyma/kyma")
+		require.NoError(t, err)
+		require.Equal(t, "https://github.tools.example.com/api/v3", apiURL)
+	})
+}
+
+func TestHTTPAuth(t *testing.T) {
+	repoRoot := newTestRepository(t)
+
+	t.Run("Should clone using token of repository specific secret", func(t *testing.T) {
+		server := newHTTPGitServer(t, repoRoot, "xxx", "repoToken")
+		repoURL := server.URL + "/repo.git"
+		repoKey, err := mapRepositorySecretKey(repoURL)
+		require.NoError(t, err)
+
+		clientSet := fake.NewSimpleClientset(
+			newSecret("127.0.0.1", map[string]string{secretKeyToken: "hostToken"}),
+			newSecret(repoKey, map[string]string{secretKeyToken: "repoToken\n"}))
+		requireClone(t, repoURL, clientSet)
+	})
+
+	t.Run("Should fall back to secret of host", func(t *testing.T) {
+		server := newHTTPGitServer(t, repoRoot, "xxx", "hostToken")
+		requireClone(t, server.URL+"/repo.git",
+			fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{secretKeyToken: "hostToken"})))
+	})
+
+	t.Run("Should fail with invalid token", func(t *testing.T) {
+		server := newHTTPGitServer(t, repoRoot, "xxx", "hostToken")
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: server.URL + "/repo.git"}, true,
+			fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{secretKeyToken: "invalid"})),
+			logger.NewLogger(true))
+		require.NoError(t, err)
+		_, err = cloner.Clone(filepath.Join(t.TempDir(), "clone"))
+		require.Error(t, err)
+	})
+}
+
+func TestGitHubAppAuth(t *testing.T) {
+	repoRoot := newTestRepository(t)
+	appKey := newRSAKey(t)
+
+	var exchanges int32
+	server := newHTTPGitServer(t, repoRoot, githubAppTokenUsername, "installationToken")
+	server.mux.HandleFunc("/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&exchanges, 1)
+		if r.Method != http.MethodPost || !validJWT(r.Header.Get("Authorization"), "4711", &appKey.PublicKey) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(installationToken{
+			Token:     "installationToken",
+			ExpiresAt: time.Now().Add(1 * time.Hour),
+		})
+	})
+
+	secretData := map[string]string{
+		secretKeyGitHubAppID:             "4711",
+		secretKeyGitHubAppInstallationID: "42",
+		secretKeyGitHubAppPrivateKey:     string(pemEncode(appKey)),
+		secretKeyGitHubAPIURL:            server.URL,
+	}
+
+	t.Run("Should clone using installation token and cache the token", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(newSecret("127.0.0.1", secretData))
+		requireClone(t, server.URL+"/repo.git", clientSet)
+		requireClone(t, server.URL+"/repo.git", clientSet)
+		require.Equal(t, int32(1), atomic.LoadInt32(&exchanges))
+	})
+
+	t.Run("Should refresh expired installation token", func(t *testing.T) {
+		cacheKey := fmt.Sprintf("%s|%s|%s", server.URL, "4711", "42")
+		installationTokenMu.Lock()
+		installationTokens[cacheKey].token.ExpiresAt = time.Now().Add(30 * time.Second) //within expiry margin
+		installationTokenMu.Unlock()
+
+		requireClone(t, server.URL+"/repo.git", fake.NewSimpleClientset(newSecret("127.0.0.1", secretData)))
+		require.Equal(t, int32(2), atomic.LoadInt32(&exchanges))
+	})
+
+	t.Run("Should evict installation token rejected by the Git server", func(t *testing.T) {
+		cacheKey := fmt.Sprintf("%s|%s|%s", server.URL, "4711", "42")
+		installationTokenMu.Lock()
+		installationTokens[cacheKey].token = &installationToken{Token: "revokedToken", ExpiresAt: time.Now().Add(time.Hour)}
+		installationTokenMu.Unlock()
+
+		clientSet := fake.NewSimpleClientset(newSecret("127.0.0.1", secretData))
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: server.URL + "/repo.git"}, true,
+			clientSet, logger.NewLogger(true))
+		require.NoError(t, err)
+		_, err = cloner.Clone(filepath.Join(t.TempDir(), "clone"))
+		require.Error(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&exchanges))
+
+		requireClone(t, server.URL+"/repo.git", clientSet)
+		require.Equal(t, int32(3), atomic.LoadInt32(&exchanges))
+	})
+
+	t.Run("Should not block other installations while exchanging a token", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		server.mux.HandleFunc("/app/installations/43/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(installationToken{Token: "otherToken", ExpiresAt: time.Now().Add(time.Hour)})
+		})
+		otherData := map[string]string{}
+		for key, value := range secretData {
+			otherData[key] = value
+		}
+		otherData[secretKeyGitHubAppInstallationID] = "43"
+
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: server.URL + "/repo.git"}, true,
+			fake.NewSimpleClientset(), logger.NewLogger(true))
+		require.NoError(t, err)
+		blocked := make(chan error)
+		go func() {
+			_, err := cloner.githubAppToken(newSecret("127.0.0.1", otherData))
+			blocked <- err
+		}()
+		<-started //exchange of installation 43 is in progress
+
+		cacheKey := fmt.Sprintf("%s|%s|%s", server.URL, "4711", "42")
+		installationTokenMu.Lock()
+		installationTokens[cacheKey].token = nil
+		installationTokenMu.Unlock()
+		requireClone(t, server.URL+"/repo.git", fake.NewSimpleClientset(newSecret("127.0.0.1", secretData)))
+
+		close(release)
+		require.NoError(t, <-blocked)
+	})
+
+	t.Run("Should fail if GitHub App is not authorized", func(t *testing.T) {
+		invalidData := map[string]string{}
+		for key, value := range secretData {
+			invalidData[key] = value
+		}
+		invalidData[secretKeyGitHubAppID] = "1234"
+
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: server.URL + "/repo.git"}, true,
+			fake.NewSimpleClientset(newSecret("127.0.0.1", invalidData)), logger.NewLogger(true))
+		require.NoError(t, err)
+		_, err = cloner.Clone(filepath.Join(t.TempDir(), "clone"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "installation token")
+	})
+}
+
+func TestSSHAuth(t *testing.T) {
+	repoRoot := newTestRepository(t)
+	clientKey := newRSAKey(t)
+	hostKey := newRSAKey(t)
+
+	addr := newSSHGitServer(t, clientKey, hostKey)
+	repoURL := fmt.Sprintf("ssh://git@%s%s", addr, filepath.Join(repoRoot, "repo.git"))
+
+	t.Run("Should clone using SSH key and verify host key", func(t *testing.T) {
+		requireClone(t, repoURL, fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{
+			secretKeySSHPrivateKey: string(pemEncode(clientKey)),
+			secretKeySSHKnownHosts: knownHostsLine(t, addr, hostKey),
+		})))
+	})
+
+	t.Run("Should reject unknown host key", func(t *testing.T) {
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: repoURL}, true,
+			fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{
+				secretKeySSHPrivateKey: string(pemEncode(clientKey)),
+				secretKeySSHKnownHosts: knownHostsLine(t, addr, newRSAKey(t)),
+			})), logger.NewLogger(true))
+		require.NoError(t, err)
+		_, err = cloner.Clone(filepath.Join(t.TempDir(), "clone"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key mismatch")
+	})
+
+	t.Run("Should reject unauthorized SSH key", func(t *testing.T) {
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: repoURL}, true,
+			fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{
+				secretKeySSHPrivateKey: string(pemEncode(newRSAKey(t))),
+				secretKeySSHKnownHosts: knownHostsLine(t, addr, hostKey),
+			})), logger.NewLogger(true))
+		require.NoError(t, err)
+		_, err = cloner.Clone(filepath.Join(t.TempDir(), "clone"))
+		require.Error(t, err)
+	})
+}
+
+func requireClone(t *testing.T, repoURL string, clientSet *fake.Clientset) {
+	cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: repoURL}, true, clientSet, logger.NewLogger(true))
+	require.NoError(t, err)
+
+	dstDir := filepath.Join(t.TempDir(), "clone")
+	_, err = cloner.Clone(dstDir)
+	require.NoError(t, err)
+	readme, err := ioutil.ReadFile(filepath.Join(dstDir, "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "test repository\n", string(readme))
+}
+
+//newTestRepository creates a bare repository 'repo.git' with a single commit and returns its parent directory
+func newTestRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping test because git binary is not installed")
+	}
+	rootDir := t.TempDir()
+	workDir := filepath.Join(rootDir, "work")
+	for _, args := range [][]string{
+		{"init", "-q", workDir},
+		{"-C", workDir, "add", "README.md"},
+		{"-C", workDir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+		{"clone", "-q", "--bare", workDir, filepath.Join(rootDir, "repo.git")},
+	} {
+		if args[0] == "-C" && args[2] == "add" {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(workDir, "README.md"), []byte("test repository\n"), 0600))
+		}
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return rootDir
+}
+
+type httpGitServer struct {
+	*httptest.Server
+	mux *http.ServeMux
+}
+
+//newHTTPGitServer serves the repositories in the root directory using the smart HTTP protocol of git
+func newHTTPGitServer(t *testing.T, rootDir, user, password string) *httpGitServer {
+	gitBinary, err := exec.LookPath("git")
+	require.NoError(t, err)
+
+	backend := &cgi.Handler{
+		Path: gitBinary,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + rootDir, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		reqUser, reqPassword, ok := r.BasicAuth()
+		if !ok || reqUser != user || reqPassword != password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		backend.ServeHTTP(w, r)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &httpGitServer{Server: server, mux: mux}
+}
+
+//newSSHGitServer starts an SSH server which serves 'git-upload-pack' requests of the authorized client
+func newSSHGitServer(t *testing.T, clientKey, hostKey *rsa.PrivateKey) string {
+	authorizedKey, err := ssh.NewPublicKey(&clientKey.PublicKey)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == defaultSSHUser && string(key.Marshal()) == string(authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unauthorized key for user '%s'", conn.User())
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSHConn(conn, config)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, chRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go serveSSHSession(channel, chRequests)
+	}
+}
+
+func serveSSHSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer func() {
+		_ = channel.Close()
+	}()
+	for req := range requests {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		args := strings.SplitN(payload.Command, " ", 2)
+		if len(args) != 2 || args[0] != "git-upload-pack" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+
+		cmd := exec.Command("git", "upload-pack", strings.Trim(args[1], "'"))
+		cmd.Stdin = channel
+		cmd.Stdout = channel
+		cmd.Stderr = channel.Stderr()
+		exitStatus := 0
+		if err := cmd.Run(); err != nil {
+			exitStatus = 1
+		}
+		_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(exitStatus)}))
+		return
+	}
+}
+
+func knownHostsLine(t *testing.T, addr string, hostKey *rsa.PrivateKey) string {
+	publicKey, err := ssh.NewPublicKey(&hostKey.PublicKey)
+	require.NoError(t, err)
+	return knownhosts.Line([]string{knownhosts.Normalize(addr)}, publicKey) + "\n"
+}
+
+func validJWT(authHeader, appID string, publicKey *rsa.PublicKey) bool {
+	parts := strings.Split(strings.TrimPrefix(authHeader, "Bearer "), ".")
+	if len(parts) != 3 {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature) != nil {
+		return false
+	}
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Exp int64  `json:"exp"`
+	}
+	return json.Unmarshal(claimsJSON, &claims) == nil && claims.Iss == appID && claims.Exp > time.Now().Unix()
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func pemEncode(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func newSecret(name string, data map[string]string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Data: make(map[string][]byte),
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
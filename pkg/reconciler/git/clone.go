@@ -3,18 +3,14 @@ package git
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"os"
 	"strings"
 
-	"github.com/go-git/go-git/v5/plumbing/transport"
 	"go.uber.org/zap"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8s "k8s.io/client-go/kubernetes"
 
 	"github.com/go-git/go-git/v5"
 	gitp "github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/pkg/errors"
 )
@@ -22,6 +18,8 @@ import (
 type Cloner struct {
 	repo         *reconciler.Repository
 	autoCheckout bool
+	shallow      bool
+	sparseDirs   []string
 
 	repoClient         RepoClient
 	inClusterClientSet k8s.Interface
@@ -54,13 +52,14 @@ func (r *Cloner) Clone(path string) (*git.Repository, error) {
 	if err != nil {
 		return nil, err
 	}
-	return r.repoClient.Clone(context.Background(), path, false, &git.CloneOptions{
+	repo, err := r.repoClient.Clone(context.Background(), path, false, &git.CloneOptions{
 		Depth:             0,
 		URL:               r.repo.URL,
 		NoCheckout:        !r.autoCheckout,
 		Auth:              auth,
 		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
 	})
+	return repo, evictRejectedToken(auth, err)
 
 }
 
@@ -97,6 +96,18 @@ func (r *Cloner) Checkout(rev string, repo *git.Repository) error {
 }
 
 func (r *Cloner) CloneAndCheckout(dstPath, rev string) error {
+	if r.shallow {
+		err := r.shallowCloneAndCheckout(dstPath, rev)
+		if err == nil || !isShallowCloneFallbackErr(err) {
+			return err
+		}
+		r.logger.Infof("Shallow clone of repository '%s' with revision '%s' not possible, falling back to full clone: %s",
+			r.repo.URL, rev, err)
+		if err := os.RemoveAll(dstPath); err != nil {
+			return err
+		}
+	}
+
 	repo, err := r.Clone(dstPath)
 	if err != nil {
 		return errors.Wrapf(err, "Error downloading Git repository (%s)", r.repo)
@@ -111,62 +122,12 @@ func (r *Cloner) CloneAndCheckout(dstPath, rev string) error {
 	return r.Checkout(rev, repo)
 }
 
-func (r *Cloner) buildAuth() (transport.AuthMethod, error) {
-	tokenNamespace := "default"
-	if r.repo.TokenNamespace != "" {
-		tokenNamespace = r.repo.TokenNamespace
-	}
-
-	if r.inClusterClientSet == nil {
-		return nil, nil
-	}
-
-	secretKey, err := mapSecretKey(r.repo.URL)
-	if err != nil {
-		return nil, err
-	}
-
-	secret, err := r.inClusterClientSet.CoreV1().
-		Secrets(tokenNamespace).
-		Get(context.Background(), secretKey, v1.GetOptions{})
-
-	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
-		return nil, err
-	}
-
-	if secret != nil && err == nil {
-		return &http.BasicAuth{
-			Username: "xxx", // anything but an empty string
-			Password: strings.Trim(string(secret.Data["token"]), "\n"),
-		}, nil
-	}
-
-	r.logger.Info("Token not found or forbidden")
-
-	return nil, nil
-}
-
 func mapSecretKey(URL string) (string, error) {
-	if !strings.HasPrefix(URL, "http") {
-		URL = "https://" + URL
-	}
-
-	URL = strings.ReplaceAll(URL, "www.", "")
-
-	parsed, err := url.Parse(URL)
-
+	parsed, err := parseRepositoryURL(strings.ReplaceAll(URL, "www.", ""))
 	if err != nil {
 		return "", err
 	}
-
-	if parsed.Scheme == "" {
-		return parsed.Path, nil
-	}
-
-	output := strings.ReplaceAll(parsed.Host, ":"+parsed.Port(), "")
-	output = strings.ReplaceAll(output, "www.", "")
-
-	return output, nil
+	return strings.ReplaceAll(parsed.Hostname(), "www.", ""), nil
 }
 
 func (r *Cloner) FetchAndCheckout(path, version string) error {
@@ -183,7 +144,7 @@ func (r *Cloner) FetchAndCheckout(path, version string) error {
 		RemoteName: "origin",
 	})
 	if err != nil {
-		return err
+		return evictRejectedToken(auth, err)
 	}
 	if version != "" {
 		defaultBranch, err := gitClient.DefaultBranch()
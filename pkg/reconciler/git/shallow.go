@@ -0,0 +1,240 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	gitp "github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+)
+
+//errShallowUnsupportedRevision indicates a revision which cannot be fetched shallowly (e.g. a commit hash)
+var errShallowUnsupportedRevision = errors.New("revision cannot be fetched shallowly")
+
+//WithShallowClone enables shallow clones: only the requested revision is fetched. If directories are passed,
+//only the files below these directories are checked out (sparse checkout).
+func (r *Cloner) WithShallowClone(sparseDirs ...string) *Cloner {
+	r.shallow = true
+	r.sparseDirs = sparseDirs
+	return r
+}
+
+//shallowCloneAndCheckout fetches the commit of the revision with depth 1 and checks it out
+func (r *Cloner) shallowCloneAndCheckout(dstPath, rev string) error {
+	auth, err := r.buildAuth()
+	if err != nil {
+		return err
+	}
+	repo, err := git.PlainInit(dstPath, false)
+	if err != nil {
+		return err
+	}
+	remote, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{r.repo.URL},
+	})
+	if err != nil {
+		return err
+	}
+
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return evictRejectedToken(auth, err)
+	}
+	remoteRef, localRef, err := shallowRefs(rev, refs)
+	if err != nil {
+		return err
+	}
+
+	err = remote.Fetch(&git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", remoteRef, localRef))},
+		Depth:      1,
+		Auth:       auth,
+		Tags:       git.NoTags,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return evictRejectedToken(auth, err)
+	}
+
+	commit, err := peeledCommit(repo, localRef)
+	if err != nil {
+		return err
+	}
+	r.logger.Debugf("Fetched commit '%s' of revision '%s' shallowly from repository '%s'",
+		commit.Hash, rev, r.repo.URL)
+
+	if len(r.sparseDirs) == 0 {
+		w, err := repo.Worktree()
+		if err != nil {
+			return err
+		}
+		return w.Checkout(&git.CheckoutOptions{Hash: commit.Hash})
+	}
+
+	//go-git doesn't support sparse checkouts: files are written directly from the commit tree
+	if err := repo.Storer.SetReference(gitp.NewHashReference(gitp.HEAD, commit.Hash)); err != nil {
+		return err
+	}
+	return checkoutDirs(commit, dstPath, r.sparseDirs)
+}
+
+//shallowRefs returns the remote reference of a revision and the local reference it is fetched into.
+//Revisions can be empty (default branch), a branch, a tag or a pull request (e.g. PR-9486).
+func shallowRefs(rev string, refs []*gitp.Reference) (gitp.ReferenceName, gitp.ReferenceName, error) {
+	refsByName := make(map[gitp.ReferenceName]*gitp.Reference, len(refs))
+	for _, ref := range refs {
+		refsByName[ref.Name()] = ref
+	}
+
+	switch {
+	case rev == "" || rev == gitp.HEAD.String():
+		branch, err := defaultBranch(refsByName)
+		if err != nil {
+			return "", "", err
+		}
+		return branch, gitp.NewRemoteReferenceName(git.DefaultRemoteName, branch.Short()), nil
+	case strings.HasPrefix(rev, prPrefix):
+		pr := strings.TrimPrefix(rev, prPrefix)
+		prRef := gitp.ReferenceName(fmt.Sprintf("refs/pull/%s/head", pr))
+		if _, ok := refsByName[prRef]; !ok {
+			return "", "", fmt.Errorf("could not find HEAD of pull request %s", pr)
+		}
+		return prRef, gitp.NewRemoteReferenceName(git.DefaultRemoteName, "pr/"+pr), nil
+	}
+
+	if _, ok := refsByName[gitp.NewBranchReferenceName(rev)]; ok {
+		return gitp.NewBranchReferenceName(rev), gitp.NewRemoteReferenceName(git.DefaultRemoteName, rev), nil
+	}
+	if _, ok := refsByName[gitp.NewTagReferenceName(rev)]; ok {
+		return gitp.NewTagReferenceName(rev), gitp.NewTagReferenceName(rev), nil
+	}
+	return "", "", errors.Wrap(errShallowUnsupportedRevision, fmt.Sprintf("revision '%s' is neither a branch, "+
+		"a tag nor a pull request", rev))
+}
+
+//defaultBranch returns the branch the remote HEAD points to
+func defaultBranch(refsByName map[gitp.ReferenceName]*gitp.Reference) (gitp.ReferenceName, error) {
+	head, ok := refsByName[gitp.HEAD]
+	if !ok {
+		return "", errors.Wrap(errShallowUnsupportedRevision, "remote repository does not advertise a HEAD")
+	}
+	if head.Type() == gitp.SymbolicReference {
+		return head.Target(), nil
+	}
+
+	//server didn't advertise the symbolic reference: use the branch with the same commit
+	var branches []string
+	for name, ref := range refsByName {
+		if name.IsBranch() && ref.Hash() == head.Hash() {
+			branches = append(branches, name.String())
+		}
+	}
+	if len(branches) == 0 {
+		return "", errors.Wrap(errShallowUnsupportedRevision, "remote HEAD does not point to a branch")
+	}
+	sort.Strings(branches)
+	return gitp.ReferenceName(branches[0]), nil
+}
+
+//peeledCommit returns the commit of the reference (annotated tags are resolved to the tagged commit)
+func peeledCommit(repo *git.Repository, refName gitp.ReferenceName) (*object.Commit, error) {
+	ref, err := repo.Reference(refName, true)
+	if err != nil {
+		return nil, err
+	}
+	if tag, err := repo.TagObject(ref.Hash()); err == nil {
+		return tag.Commit()
+	}
+	return repo.CommitObject(ref.Hash())
+}
+
+//checkoutDirs writes the files below the directories of the commit into the destination path
+func checkoutDirs(commit *object.Commit, dstPath string, dirs []string) error {
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	for _, dir := range sparseRoots(dirs) {
+		subTree, err := tree.Tree(dir)
+		if err == object.ErrDirectoryNotFound {
+			continue //missing directories are reported by the validation of the workspace
+		}
+		if err != nil {
+			return err
+		}
+		err = subTree.Files().ForEach(func(f *object.File) error {
+			return writeFile(f, filepath.Join(dstPath, filepath.FromSlash(dir), filepath.FromSlash(f.Name)))
+		})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("failed to check out directory '%s' of commit '%s'", dir, commit.Hash))
+		}
+	}
+	return nil
+}
+
+func writeFile(f *object.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	content, err := f.Contents()
+	if err != nil {
+		return err
+	}
+	if f.Mode == filemode.Symlink {
+		return os.Symlink(content, path)
+	}
+	mode, err := f.Mode.ToOSFileMode()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), mode.Perm())
+}
+
+//sparseRoots returns the normalized directories without directories which are nested in other directories
+func sparseRoots(dirs []string) []string {
+	normalized := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		normalized = append(normalized, strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/"))
+	}
+	sort.Strings(normalized) //parent directories are sorted before their sub-directories
+
+	var roots []string
+	for _, dir := range normalized {
+		nested := false
+		for _, root := range roots {
+			if dir == root || strings.HasPrefix(dir, root+"/") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			roots = append(roots, dir)
+		}
+	}
+	return roots
+}
+
+//isShallowCloneFallbackErr returns true if a failed shallow clone can be retried as full clone
+func isShallowCloneFallbackErr(err error) bool {
+	for _, fatalErr := range []error{
+		transport.ErrAuthenticationRequired,
+		transport.ErrAuthorizationFailed,
+		transport.ErrRepositoryNotFound,
+		transport.ErrEmptyRemoteRepository,
+	} {
+		if errors.Is(err, fatalErr) {
+			return false
+		}
+	}
+	return true
+}
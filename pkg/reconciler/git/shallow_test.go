@@ -0,0 +1,150 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var testSparseDirs = []string{"resources", "installation/resources", "installation/resources/crds"}
+
+func TestShallowClone(t *testing.T) {
+	repoRoot, commits := newVersionedTestRepository(t)
+	server := newHTTPGitServer(t, repoRoot, "xxx", "token")
+	repoURL := server.URL + "/repo.git"
+
+	testCases := []struct {
+		summary  string
+		rev      string
+		expected string
+	}{
+		{"default branch", "", "v2"},
+		{"branch", "release-1", "v1"},
+		{"annotated tag", "1.0.0", "v1"},
+		{"lightweight tag", "2.0.0", "v2"},
+		{"pull request", "PR-1", "pr"},
+	}
+	for _, testCase := range testCases {
+		t.Run("Should shallow clone "+testCase.summary, func(t *testing.T) {
+			dstDir := cloneTestRepository(t, repoURL, testCase.rev, testSparseDirs...)
+			require.True(t, isShallow(dstDir))
+			requireVersion(t, dstDir, testCase.expected)
+			require.True(t, file.Exists(filepath.Join(dstDir, "installation", "resources", "crds", "crd.yaml")))
+			require.False(t, file.Exists(filepath.Join(dstDir, "docs", "README.md")), "directory is not sparse")
+			require.False(t, file.Exists(filepath.Join(dstDir, "README.md")), "directory is not sparse")
+		})
+	}
+
+	t.Run("Should shallow clone without sparse checkout", func(t *testing.T) {
+		dstDir := cloneTestRepository(t, repoURL, "release-1")
+		require.True(t, isShallow(dstDir))
+		requireVersion(t, dstDir, "v1")
+		require.True(t, file.Exists(filepath.Join(dstDir, "docs", "README.md")))
+	})
+
+	t.Run("Should fall back to full clone for commit hashes", func(t *testing.T) {
+		dstDir := cloneTestRepository(t, repoURL, commits[0], testSparseDirs...)
+		require.False(t, isShallow(dstDir))
+		requireVersion(t, dstDir, "v1")
+		require.True(t, file.Exists(filepath.Join(dstDir, "docs", "README.md")))
+	})
+
+	t.Run("Should not fall back to full clone if authentication fails", func(t *testing.T) {
+		cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: repoURL}, true,
+			fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{secretKeyToken: "invalid"})),
+			logger.NewLogger(true))
+		require.NoError(t, err)
+		err = cloner.WithShallowClone(testSparseDirs...).CloneAndCheckout(filepath.Join(t.TempDir(), "clone"), "")
+		require.Error(t, err)
+		require.False(t, isShallowCloneFallbackErr(err))
+	})
+}
+
+func TestSparseRoots(t *testing.T) {
+	require.Equal(t, []string{"a", "a-b", "c/d"}, sparseRoots([]string{"a/b", "a-b", "/c/d/", "a", "a/b/c", "c/d/e"}))
+	require.Empty(t, sparseRoots(nil))
+}
+
+func cloneTestRepository(t *testing.T, repoURL, rev string, sparseDirs ...string) string {
+	cloner, err := NewCloner(&Client{}, &reconciler.Repository{URL: repoURL}, true,
+		fake.NewSimpleClientset(newSecret("127.0.0.1", map[string]string{secretKeyToken: "token"})),
+		logger.NewLogger(true))
+	require.NoError(t, err)
+
+	dstDir := filepath.Join(t.TempDir(), "clone")
+	require.NoError(t, cloner.WithShallowClone(sparseDirs...).CloneAndCheckout(dstDir, rev))
+	return dstDir
+}
+
+func requireVersion(t *testing.T, dstDir, expected string) {
+	version, err := ioutil.ReadFile(filepath.Join(dstDir, "resources", "version.txt"))
+	require.NoError(t, err)
+	require.Equal(t, expected, strings.TrimSpace(string(version)))
+}
+
+func isShallow(dstDir string) bool {
+	return file.Exists(filepath.Join(dstDir, ".git", "shallow"))
+}
+
+//newVersionedTestRepository creates a bare repository 'repo.git' which contains the Kyma directory structure
+//with two commits on the main branch, a release branch, tags and a pull request. The commit hashes are returned
+//in chronological order.
+func newVersionedTestRepository(t *testing.T) (string, []string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping test because git binary is not installed")
+	}
+	rootDir := t.TempDir()
+	workDir := filepath.Join(rootDir, "work")
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", workDir, "-c", "user.name=test",
+			"-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(version string) string {
+		for name, content := range map[string]string{
+			"README.md":                               "Kyma " + version,
+			"docs/README.md":                          "docs " + version,
+			"resources/version.txt":                   version,
+			"resources/component/Chart.yaml":          "name: component",
+			"installation/resources/components.yaml":  "components: []",
+			"installation/resources/crds/crd.yaml":    "kind: CustomResourceDefinition",
+			"installation/resources/crds/nested/a.sh": "#!/bin/sh",
+		} {
+			path := filepath.Join(workDir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+			require.NoError(t, ioutil.WriteFile(path, []byte(content+"\n"), 0600))
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", version)
+		return git("rev-parse", "HEAD")
+	}
+
+	_, err := exec.Command("git", "init", "-q", "-b", "main", workDir).CombinedOutput()
+	require.NoError(t, err)
+	commits := []string{commit("v1")}
+	git("tag", "-a", "1.0.0", "-m", "release 1.0.0")
+	git("branch", "release-1")
+	commits = append(commits, commit("v2"))
+	git("tag", "2.0.0")
+
+	git("checkout", "-q", "-b", "feature")
+	commit("pr")
+	git("update-ref", "refs/pull/1/head", "HEAD")
+	git("checkout", "-q", "main")
+
+	out, err := exec.Command("git", "clone", "-q", "--bare", workDir, filepath.Join(rootDir, "repo.git")).CombinedOutput()
+	require.NoError(t, err, string(out))
+	//pull request references are not copied by a clone
+	git("push", "-q", filepath.Join(rootDir, "repo.git"), "refs/pull/1/head:refs/pull/1/head")
+	return rootDir, commits
+}
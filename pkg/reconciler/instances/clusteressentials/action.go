@@ -47,5 +47,5 @@ func (a *CustomAction) Run(context *service.ActionContext) error {
 		utils.SetOverrideFromSecret(logger, secret, context.Task.Configuration, "tls.key", "pod-preset.key")
 	}
 
-	return service.NewInstall(context.Logger).Invoke(context.Context, context.ChartProvider, context.Task, context.KubeClient)
+	return context.Install.Invoke(context.Context, context.ChartProvider, context.Task, context.KubeClient)
 }
@@ -164,6 +164,7 @@ func setup() (kubernetes.Interface, CustomAction, *service.ActionContext) {
 		Manifest: "",
 	}
 	mockProvider.On("RenderManifest", mock.Anything).Return(&mockManifest, nil)
+	mockProvider.On("Validate", mock.Anything).Return(nil)
 
 	actionContext := &service.ActionContext{
 		KubeClient:    &mockClient,
@@ -171,6 +172,7 @@ func setup() (kubernetes.Interface, CustomAction, *service.ActionContext) {
 		Logger:        logger.NewLogger(false),
 		ChartProvider: &mockProvider,
 		Task:          &reconciler.Task{Version: "test", Configuration: configuration},
+		Install:       service.NewInstall(logger.NewLogger(false)),
 	}
 	return k8sClient, action, actionContext
 }
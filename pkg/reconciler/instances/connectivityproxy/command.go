@@ -32,7 +32,7 @@ type NewTargetClientSet func(context *service.ActionContext) (kubernetes.Interfa
 type CommandActions struct {
 	clientSetFactory       NewInClusterClientSet
 	targetClientSetFactory NewTargetClientSet
-	install                service.Operation
+	install                service.Operation //overrides the install operation of the action context
 	copyFactory            []CopyFactory
 }
 
@@ -73,7 +73,11 @@ func (a *CommandActions) InstallOnReleaseChange(context *service.ActionContext,
 }
 
 func (a *CommandActions) installOnCondition(context *service.ActionContext) error {
-	err := a.install.Invoke(context.Context, context.ChartProvider, context.Task, context.KubeClient)
+	var install service.Operation = context.Install
+	if a.install != nil {
+		install = a.install
+	}
+	err := install.Invoke(context.Context, context.ChartProvider, context.Task, context.KubeClient)
 	if err != nil {
 		return errors.Wrap(err, "failed to invoke conditional installation")
 	}
@@ -121,6 +125,8 @@ func (a *CommandActions) Remove(context *service.ActionContext) error {
 	component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
 		WithNamespace(context.Task.Namespace).
 		WithProfile(context.Task.Profile).
+		WithProfileValues(context.Task.ProfileValues).
+		WithLandscape(context.Task.Landscape).
 		WithConfiguration(context.Task.Configuration).
 		WithURL(context.Task.URL).
 		Build()
@@ -111,6 +111,7 @@ func TestCommands(t *testing.T) {
 		chartProvider := &chartmocks.Provider{}
 		chartProvider.On("WithFilter", mock.AnythingOfType("chart.Filter")).
 			Return(chartProvider)
+		chartProvider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(nil)
 		chartProvider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).
 			Return(&chart.Manifest{
 				Type:     chart.HelmChart,
@@ -156,6 +157,7 @@ func TestCommands(t *testing.T) {
 		chartProvider := &chartmocks.Provider{}
 		chartProvider.On("WithFilter", mock.AnythingOfType("chart.Filter")).
 			Return(chartProvider)
+		chartProvider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(nil)
 		chartProvider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).
 			Return(&chart.Manifest{
 				Type:     chart.HelmChart,
@@ -196,6 +198,7 @@ func TestCommands(t *testing.T) {
 		}
 
 		chartProvider := &chartmocks.Provider{}
+		chartProvider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(nil)
 		chartProvider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).
 			Return(&chart.Manifest{
 				Type:     chart.HelmChart,
@@ -234,6 +237,7 @@ func TestCommands(t *testing.T) {
 		chartProvider := &chartmocks.Provider{}
 		chartProvider.On("WithFilter", mock.AnythingOfType("chart.Filter")).
 			Return(chartProvider)
+		chartProvider.On("Validate", mock.AnythingOfType("*chart.Component")).Return(nil)
 		chartProvider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).
 			Return(&chart.Manifest{
 				Type:     chart.HelmChart,
@@ -35,7 +35,6 @@ func init() {
 			targetClientSetFactory: func(context *service.ActionContext) (k8s.Interface, error) {
 				return context.KubeClient.Clientset()
 			},
-			install: service.NewInstall(log),
 			copyFactory: []CopyFactory{
 				istioSecretCopy,
 			},
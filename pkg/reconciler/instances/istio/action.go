@@ -72,6 +72,8 @@ func (a *MainReconcileAction) Run(context *service.ActionContext) error {
 	component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
 		WithNamespace(istioNamespace).
 		WithProfile(context.Task.Profile).
+		WithProfileValues(context.Task.ProfileValues).
+		WithLandscape(context.Task.Landscape).
 		WithConfiguration(context.Task.Configuration).Build()
 	istioManifest, err := context.ChartProvider.RenderManifest(component)
 	if err != nil {
@@ -202,6 +204,8 @@ func (a *UninstallAction) Run(context *service.ActionContext) error {
 		component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
 			WithNamespace(istioNamespace).
 			WithProfile(context.Task.Profile).
+			WithProfileValues(context.Task.ProfileValues).
+			WithLandscape(context.Task.Landscape).
 			WithConfiguration(context.Task.Configuration).Build()
 		istioManifest, err := context.ChartProvider.RenderManifest(component)
 		if err != nil {
@@ -243,6 +247,8 @@ func (a *ReconcileIstioConfigurationAction) Run(context *service.ActionContext)
 	component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
 		WithNamespace(istioNamespace).
 		WithProfile(context.Task.Profile).
+		WithProfileValues(context.Task.ProfileValues).
+		WithLandscape(context.Task.Landscape).
 		WithConfiguration(context.Task.Configuration).Build()
 	istioManifest, err := context.ChartProvider.RenderManifest(component)
 	if err != nil {
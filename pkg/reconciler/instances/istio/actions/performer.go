@@ -306,6 +306,7 @@ func getTargetVersionFromIstioChart(workspace chart.Factory, branch string, isti
 	if err != nil {
 		return "", err
 	}
+	defer ws.Release()
 
 	helmChart, err := loader.Load(filepath.Join(ws.ResourceDir, istioChart))
 	if err != nil {
@@ -252,6 +252,8 @@ func readActionContext(context *service.ActionContext) (*zap.SugaredLogger, inte
 	component := chart.NewComponentBuilder(context.Task.Version, oryChart).
 		WithNamespace(oryNamespace).
 		WithProfile(context.Task.Profile).
+		WithProfileValues(context.Task.ProfileValues).
+		WithLandscape(context.Task.Landscape).
 		WithConfiguration(context.Task.Configuration).Build()
 
 	chartValues, err := context.ChartProvider.Configuration(component)
@@ -113,6 +113,7 @@ func readRafterControllerValues(ctx *service.ActionContext, version string) (*ra
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve Kyma workspace for rafter action")
 	}
+	defer ws.Release()
 	valuesFile := filepath.Join(ws.WorkspaceDir, rafterValuesRelativePath)
 
 	return readValues(valuesFile)
@@ -44,7 +44,7 @@ func (a *ReconcileCustomAction) Run(svcCtx *service.ActionContext) error {
 			setOverridesFromDeployment(deployment, svcCtx.Task.Configuration)
 		}
 	}
-	return service.NewInstall(svcCtx.Logger).Invoke(svcCtx.Context, svcCtx.ChartProvider, svcCtx.Task, svcCtx.KubeClient)
+	return svcCtx.Install.Invoke(svcCtx.Context, svcCtx.ChartProvider, svcCtx.Task, svcCtx.KubeClient)
 }
 
 func setOverridesFromDeployment(deployment *appsv1.Deployment, configuration map[string]interface{}) {
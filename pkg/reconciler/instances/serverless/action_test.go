@@ -163,6 +163,38 @@ func TestServerlessReconciliation(t *testing.T) {
 
 }
 
+func TestServerlessReconciliationInterceptors(t *testing.T) {
+	hasLabelsInterceptor := func(t *testing.T, actionContext *service.ActionContext) bool {
+		kubeClient := actionContext.KubeClient.(*mocks.Client)
+		for _, call := range kubeClient.Calls {
+			if call.Method != "Deploy" {
+				continue
+			}
+			for _, interceptor := range call.Arguments[3:] {
+				if _, ok := interceptor.(*service.LabelsInterceptor); ok {
+					return true
+				}
+			}
+			return false
+		}
+		t.Fatal("manifest was not deployed")
+		return false
+	}
+
+	t.Run("Enabled interceptor is applied", func(t *testing.T) {
+		_, action, actionContext := setup()
+		require.NoError(t, action.Run(actionContext))
+		require.True(t, hasLabelsInterceptor(t, actionContext))
+	})
+
+	t.Run("Disabled interceptor stays disabled", func(t *testing.T) {
+		_, action, actionContext := setup()
+		actionContext.Install = actionContext.Install.WithoutInterceptors(service.LabelsInterceptorName)
+		require.NoError(t, action.Run(actionContext))
+		require.False(t, hasLabelsInterceptor(t, actionContext))
+	})
+}
+
 func createSecret(ctx context.Context, client kubernetes.Interface, secret *corev1.Secret) (*corev1.Secret, error) {
 	return client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
 }
@@ -220,6 +252,7 @@ func setup() (kubernetes.Interface, ReconcileCustomAction, *service.ActionContex
 		Manifest: "",
 	}
 	mockProvider.On("RenderManifest", mock.Anything).Return(&mockManifest, nil)
+	mockProvider.On("Validate", mock.Anything).Return(nil)
 
 	actionContext := &service.ActionContext{
 		KubeClient:    &mockClient,
@@ -227,6 +260,7 @@ func setup() (kubernetes.Interface, ReconcileCustomAction, *service.ActionContex
 		Logger:        logger.NewLogger(false),
 		ChartProvider: &mockProvider,
 		Task:          &reconciler.Task{Version: "test", Configuration: configuration},
+		Install:       service.NewInstall(logger.NewLogger(false)),
 	}
 	return k8sClient, action, actionContext
 }
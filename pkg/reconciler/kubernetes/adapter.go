@@ -19,6 +19,7 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -32,6 +33,7 @@ import (
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	apiMeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sutilerr "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -247,27 +249,79 @@ func (g *kubeClientAdapter) deployResources(ctx context.Context, infoOriginalLis
 	}
 
 	var deployedResources []*Resource
+	infoOriginals := make(map[*resource.Info]*resource.Info, len(infoTargetList))
 	for _, infoTarget := range infoTargetList {
 		//Do intersect to make sure helmclient only do create/update but not delete resource which exists in original but not in target.
 		intersectOriginal := kube.ResourceList{infoTarget}.Intersect(infoOriginalList)
 		if len(intersectOriginal) == 0 {
 			return nil, fmt.Errorf("could not find intersect between original and target resource")
 		}
+		infoOriginals[infoTarget] = intersectOriginal[0]
 
 		deployingResource := g.addWatchableResourceInfoToProgressTracker(infoTarget, pt)
 		deployedResources = append(deployedResources, deployingResource)
+	}
 
-		err = g.deployResource(ctx, intersectOriginal[0], infoTarget, crdGroupKinds)
-		if err != nil {
-			g.logger.Errorf("Failed to apply Kubernetes unstructured entity: %s", err)
+	//apply resources group by group: resources within a group are applied concurrently
+	for _, group := range groupByInstallOrder(infoTargetList) {
+		if err := g.deployResourceGroup(ctx, group, infoOriginals, crdGroupKinds); err != nil {
 			return nil, err
 		}
-		g.logger.Debugf("Kubernetes deployingResource '%v' successfully deployed", deployingResource)
 	}
 
 	return deployedResources, pt.Watch(ctx, progress.ReadyState)
 }
 
+func (g *kubeClientAdapter) deployResourceGroup(ctx context.Context, group kube.ResourceList, infoOriginals map[*resource.Info]*resource.Info, crdGroupKinds []schema.GroupKind) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	semaphore := make(chan struct{}, g.config.DeployParallelism)
+
+	for _, infoTarget := range group {
+		if err := acquireSlot(ctx, semaphore); err != nil { //don't start further deployments if ctx is done
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			break
+		}
+		wg.Add(1)
+		go func(infoTarget *resource.Info) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			err := g.deployResource(ctx, infoOriginals[infoTarget], infoTarget, crdGroupKinds)
+			if err != nil {
+				g.logger.Errorf("Failed to apply Kubernetes unstructured entity: %s", err)
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				return
+			}
+			g.logger.Debugf("Kubernetes resource %s '%s' (namespace: %s) successfully deployed",
+				infoTarget.Object.GetObjectKind().GroupVersionKind().Kind, infoTarget.Name, infoTarget.Namespace)
+		}(infoTarget)
+	}
+	wg.Wait()
+
+	return k8sutilerr.NewAggregate(errs)
+}
+
+//acquireSlot blocks until the semaphore has a free slot or the context is done
+func acquireSlot(ctx context.Context, semaphore chan struct{}) error {
+	select {
+	case semaphore <- struct{}{}:
+		if err := ctx.Err(); err != nil { //select picks randomly if the context is done and a slot is free
+			<-semaphore
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (g *kubeClientAdapter) getUpdateStrategy(infoTarget *resource.Info) (UpdateStrategy, error) {
 	helper := resource.NewHelper(infoTarget.Client, infoTarget.Mapping)
 	strategy, err := newDefaultUpdateStrategyResolver(helper, g.logger).Resolve(infoTarget)
@@ -426,7 +480,7 @@ func (g *kubeClientAdapter) deployResource(ctx context.Context, infoOriginal, in
 		retry.Attempts(uint(g.config.MaxRetries)),
 		retry.Delay(g.config.RetryDelay),
 		retry.LastErrorOnly(false),
-		retry.Context(context.Background()))
+		retry.Context(ctx))
 
 	if err != nil {
 		return errors.Wrapf(err, "kubeClient failed to update %s '%s' (namespace: %s)",
@@ -666,9 +720,27 @@ func (g *kubeClientAdapter) Delete(ctx context.Context, manifestTarget, namespac
 		}
 	}
 
+	//watching the deleted resources and verifying their removal share the progress timeout
+	deadline := time.Now().Add(g.config.ProgressTimeout)
+
 	//wait until all resources were deleted
 	if err := pt.Watch(ctx, progress.TerminatedState); err != nil {
 		g.logger.Warnf("Watching progress of deleted resources failed: %s", err)
+		if ctx.Err() != nil {
+			return deletedResources, err
+		}
+	}
+
+	//ensure no deleted resource was left behind (e.g. because a finalizer is blocking its deletion)
+	orphans, err := g.verifyDeletion(ctx, resourceInfoTarget, deadline)
+	if err != nil {
+		g.logger.Errorf("Failed to verify that deleted resources were removed: %s", err)
+		return deletedResources, err
+	}
+	if len(orphans) > 0 {
+		orphanErr := &OrphanedResourcesError{Resources: orphans}
+		g.logger.Warnf("Deletion of manifest left resources behind: %s", orphanErr)
+		return deletedResources, orphanErr
 	}
 
 	if err = g.DeleteNamespace(namespace); err != nil && !k8serr.IsNotFound(err) {
@@ -700,6 +772,14 @@ func (g *kubeClientAdapter) DeleteNamespace(namespace string) error {
 		err = g.dynamicClient.
 			Resource(namespaceRes).
 			Delete(context.TODO(), namespace, metav1.DeleteOptions{})
+	} else {
+		var remaining []string
+		for _, info := range infos {
+			remaining = append(remaining, fmt.Sprintf("%s/%s",
+				info.Object.GetObjectKind().GroupVersionKind().Kind, info.Name))
+		}
+		g.logger.Infof("Namespace '%s' will not be deleted because %d resource(s) still exist in it: %s",
+			namespace, len(infos), strings.Join(remaining, ", "))
 	}
 	return err
 }
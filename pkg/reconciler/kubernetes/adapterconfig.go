@@ -10,6 +10,7 @@ const (
 	progressTrackerTimeout  = 2 * time.Minute
 	maxRetries              = 10
 	retryDelay              = 1 * time.Second
+	deployParallelism       = 5
 )
 
 type Config struct {
@@ -17,6 +18,11 @@ type Config struct {
 	ProgressTimeout  time.Duration
 	MaxRetries       int
 	RetryDelay       time.Duration
+	//DeployParallelism defines how many resources of the same install group are applied concurrently
+	DeployParallelism int
+	//RemoveKymaFinalizers enables the removal of finalizers from custom resources deployed by the reconciler
+	//if they block the deletion of the resource (only resources with the managed-by label are considered)
+	RemoveKymaFinalizers bool
 }
 
 func (c *Config) validate() error {
@@ -30,6 +36,8 @@ func (c *Config) validate() error {
 		return fmt.Errorf("config ProgressInterval cannot be < 0 (got %d)", c.ProgressInterval)
 	case c.ProgressTimeout < 0:
 		return fmt.Errorf("config ProgressTimeout cannot be < 0 (got %d)", c.ProgressTimeout)
+	case c.DeployParallelism < 0:
+		return fmt.Errorf("config DeployParallelism cannot be < 0 (got %d)", c.DeployParallelism)
 	}
 
 	if c.MaxRetries == 0 {
@@ -44,5 +52,8 @@ func (c *Config) validate() error {
 	if c.ProgressTimeout == 0 {
 		c.ProgressTimeout = progressTrackerTimeout
 	}
+	if c.DeployParallelism == 0 {
+		c.DeployParallelism = deployParallelism
+	}
 	return nil
 }
@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"strings"
+
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+//installOrder defines the groups of kinds in the order they have to be applied to a cluster (similar to Helm's
+//install order). Resources within the same group don't depend on each other and can be applied concurrently.
+//Kinds which are not listed (e.g. custom resources) are applied after the workloads but before the webhooks.
+var installOrder = [][]string{
+	{"Namespace"},
+	{"CustomResourceDefinition"},
+	{"PriorityClass", "ResourceQuota", "LimitRange", "NetworkPolicy", "PodSecurityPolicy", "PodDisruptionBudget"},
+	{"ServiceAccount", "ClusterRole", "ClusterRoleList", "ClusterRoleBinding", "ClusterRoleBindingList",
+		"Role", "RoleList", "RoleBinding", "RoleBindingList"},
+	{"ConfigMap", "Secret", "SecretList"},
+	{"StorageClass", "PersistentVolume", "PersistentVolumeClaim"},
+	{"Service"},
+	{"Pod", "ReplicationController", "ReplicaSet", "Deployment", "DaemonSet", "StatefulSet",
+		"Job", "CronJob", "HorizontalPodAutoscaler", "Ingress", "IngressClass"},
+	nil, //placeholder for all unknown kinds
+	{"APIService", "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"},
+}
+
+var installGroupOfKind = func() map[string]int {
+	result := make(map[string]int)
+	for idx, kinds := range installOrder {
+		for _, kind := range kinds {
+			result[strings.ToLower(kind)] = idx
+		}
+	}
+	return result
+}()
+
+//unknownKindsGroup is the index of the install group used for kinds which are not part of the install order
+var unknownKindsGroup = func() int {
+	for idx, kinds := range installOrder {
+		if kinds == nil {
+			return idx
+		}
+	}
+	panic("install order has no group for unknown kinds")
+}()
+
+//groupByInstallOrder splits the resources into groups which have to be applied sequentially. The order
+//of the resources within a group is equal to their order in the provided list. Empty groups are dropped.
+func groupByInstallOrder(infos kube.ResourceList) []kube.ResourceList {
+	groups := make([]kube.ResourceList, len(installOrder))
+	for _, info := range infos {
+		idx := installGroup(info)
+		groups[idx] = append(groups[idx], info)
+	}
+
+	var result []kube.ResourceList
+	for _, group := range groups {
+		if len(group) > 0 {
+			result = append(result, group)
+		}
+	}
+	return result
+}
+
+func installGroup(info *resource.Info) int {
+	kind := strings.ToLower(info.Object.GetObjectKind().GroupVersionKind().Kind)
+	if idx, ok := installGroupOfKind[kind]; ok {
+		return idx
+	}
+	return unknownKindsGroup
+}
@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestGroupByInstallOrder(t *testing.T) {
+	newInfo := func(kind, name string) *resource.Info {
+		u := &unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetName(name)
+		return &resource.Info{Name: name, Object: u}
+	}
+
+	infos := kube.ResourceList{
+		newInfo("ValidatingWebhookConfiguration", "webhook"),
+		newInfo("Deployment", "deployment1"),
+		newInfo("MyCustomResource", "cr"),
+		newInfo("Service", "service"),
+		newInfo("ConfigMap", "configmap"),
+		newInfo("Deployment", "deployment2"),
+		newInfo("ClusterRoleBinding", "binding"),
+		newInfo("ServiceAccount", "sa"),
+		newInfo("CustomResourceDefinition", "crd"),
+		newInfo("Namespace", "namespace"),
+		newInfo("Secret", "secret"),
+	}
+
+	var actual [][]string
+	for _, group := range groupByInstallOrder(infos) {
+		var names []string
+		for _, info := range group {
+			names = append(names, info.Name)
+		}
+		actual = append(actual, names)
+	}
+
+	require.Equal(t, [][]string{
+		{"namespace"},
+		{"crd"},
+		{"binding", "sa"},
+		{"configmap", "secret"},
+		{"service"},
+		{"deployment1", "deployment2"},
+		{"cr"},
+		{"webhook"},
+	}, actual)
+}
+
+func TestDeployResourceGroupWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	semaphore := make(chan struct{}, 1)
+	require.Equal(t, context.Canceled, acquireSlot(ctx, semaphore))
+	require.Empty(t, semaphore) //free slot is not consumed
+	semaphore <- struct{}{}
+	require.Equal(t, context.Canceled, acquireSlot(ctx, semaphore)) //doesn't block if no slot is free
+
+	//no resource gets deployed
+	adapter := &kubeClientAdapter{logger: logger.NewLogger(true), config: &Config{DeployParallelism: 1}}
+	err := adapter.deployResourceGroup(ctx, kube.ResourceList{&resource.Info{Name: "cm"}}, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), context.Canceled.Error())
+}
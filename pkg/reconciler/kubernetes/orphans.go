@@ -0,0 +1,171 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	apiMeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	//ManagedByLabel marks resources which were deployed by the reconciler
+	ManagedByLabel      = "reconciler.kyma-project.io/managed-by"
+	ManagedByLabelValue = "reconciler"
+	removeFinalizers    = `{"metadata":{"finalizers":null}}`
+)
+
+//OrphanedResource is a resource which still exists in the cluster after it was deleted
+type OrphanedResource struct {
+	Resource
+	Finalizers  []string
+	Terminating bool
+	labels      map[string]string
+	info        *resource.Info
+}
+
+func (o *OrphanedResource) String() string {
+	result := fmt.Sprintf("%s [namespace:%s|name:%s]", o.Kind, o.Namespace, o.Name)
+	if o.Terminating {
+		result += " (terminating)"
+	}
+	if len(o.Finalizers) > 0 {
+		result += fmt.Sprintf(" blocked by finalizers: %s", strings.Join(o.Finalizers, ", "))
+	}
+	return result
+}
+
+//isKymaCustomResource returns true if the orphan is a custom resource which was deployed by the reconciler.
+//Only resources labeled by the reconciler are detected: resources created by an older reconciler version (or
+//by an operator of the component) don't have the managed-by label and keep their finalizers.
+func (o *OrphanedResource) isKymaCustomResource(crdGroupKinds []schema.GroupKind) bool {
+	gvk := o.info.Object.GetObjectKind().GroupVersionKind()
+	return o.labels[ManagedByLabel] == ManagedByLabelValue && containsGroupKind(crdGroupKinds, gvk.GroupKind())
+}
+
+//isPending returns true if the deletion of the orphan is in progress and isn't blocked by any finalizer
+func (o *OrphanedResource) isPending() bool {
+	return o.Terminating && len(o.Finalizers) == 0
+}
+
+//OrphanedResourcesError is returned if deleted resources still exist in the cluster
+type OrphanedResourcesError struct {
+	Resources []*OrphanedResource
+}
+
+func (e *OrphanedResourcesError) Error() string {
+	var orphans []string
+	for _, orphan := range e.Resources {
+		orphans = append(orphans, orphan.String())
+	}
+	return fmt.Sprintf("%d resource(s) still exist after deletion: %s", len(e.Resources), strings.Join(orphans, ", "))
+}
+
+//verifyDeletion checks whether any of the deleted resources still exists. Finalizers of custom resources
+//deployed by the reconciler are removed if enabled in the config. The remaining resources are returned.
+//Terminating resources are awaited until the deadline of the deletion is reached.
+func (g *kubeClientAdapter) verifyDeletion(ctx context.Context, infos []*resource.Info, deadline time.Time) ([]*OrphanedResource, error) {
+	orphans, err := g.waitForOrphans(ctx, infos, deadline)
+	if err != nil || len(orphans) == 0 || !g.config.RemoveKymaFinalizers {
+		return orphans, err
+	}
+
+	crdGroupKinds, err := g.getCRDGroupKinds(ctx)
+	if err != nil {
+		return orphans, err
+	}
+
+	var patchedInfos []*resource.Info
+	for _, orphan := range orphans {
+		if len(orphan.Finalizers) == 0 || !orphan.isKymaCustomResource(crdGroupKinds) {
+			continue
+		}
+		g.logger.Infof("Removing finalizers %s from %s which blocked its deletion",
+			strings.Join(orphan.Finalizers, ","), orphan)
+		helper := resource.NewHelper(orphan.info.Client, orphan.info.Mapping)
+		_, err := helper.Patch(orphan.Namespace, orphan.Name, types.MergePatchType, []byte(removeFinalizers), nil)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return orphans, err
+		}
+		patchedInfos = append(patchedInfos, orphan.info)
+	}
+	if len(patchedInfos) == 0 {
+		return orphans, nil
+	}
+
+	//verify again that all deleted resources are gone
+	return g.waitForOrphans(ctx, infos, deadline)
+}
+
+//waitForOrphans returns the deleted resources which still exist. Resources which are terminating and not
+//blocked by a finalizer are awaited until the deadline is reached.
+func (g *kubeClientAdapter) waitForOrphans(ctx context.Context, infos []*resource.Info, deadline time.Time) ([]*OrphanedResource, error) {
+	return waitForOrphans(ctx, deadline, g.config.RetryDelay, func() ([]*OrphanedResource, error) {
+		return g.findOrphans(infos)
+	})
+}
+
+//waitForOrphans checks at least once for orphans, also if the deadline has already passed
+func waitForOrphans(ctx context.Context, deadline time.Time, interval time.Duration,
+	findOrphans func() ([]*OrphanedResource, error)) ([]*OrphanedResource, error) {
+	timeoutCh := time.After(time.Until(deadline))
+	for {
+		orphans, err := findOrphans()
+		if err != nil {
+			return nil, err
+		}
+		var blocked []*OrphanedResource
+		for _, orphan := range orphans {
+			if !orphan.isPending() {
+				blocked = append(blocked, orphan)
+			}
+		}
+		if len(blocked) == len(orphans) {
+			return orphans, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return blocked, ctx.Err()
+		case <-timeoutCh:
+			//resources without finalizers are removed by the API server and aren't reported as orphans
+			return blocked, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (g *kubeClientAdapter) findOrphans(infos []*resource.Info) ([]*OrphanedResource, error) {
+	var orphans []*OrphanedResource
+	for _, info := range infos {
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		obj, err := helper.Get(info.Namespace, info.Name)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		objMeta, err := apiMeta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, &OrphanedResource{
+			Resource: Resource{
+				Kind:      info.Object.GetObjectKind().GroupVersionKind().Kind,
+				Name:      info.Name,
+				Namespace: info.Namespace,
+			},
+			Finalizers:  objMeta.GetFinalizers(),
+			Terminating: objMeta.GetDeletionTimestamp() != nil,
+			labels:      objMeta.GetLabels(),
+			info:        info,
+		})
+	}
+	return orphans, nil
+}
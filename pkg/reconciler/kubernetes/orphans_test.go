@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestOrphanedResources(t *testing.T) {
+	newOrphan := func(apiVersion, kind, name string, labels map[string]string, finalizers ...string) *OrphanedResource {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		return &OrphanedResource{
+			Resource: Resource{
+				Kind:      kind,
+				Name:      name,
+				Namespace: "kyma-system",
+			},
+			Finalizers:  finalizers,
+			Terminating: len(finalizers) > 0,
+			labels:      labels,
+			info:        &resource.Info{Name: name, Namespace: "kyma-system", Object: u},
+		}
+	}
+	managedByReconciler := map[string]string{ManagedByLabel: ManagedByLabelValue}
+	crdGroupKinds := []schema.GroupKind{{Group: "kyma-project.io", Kind: "Function"}}
+
+	t.Run("Detect custom resources deployed by the reconciler", func(t *testing.T) {
+		require.True(t, newOrphan("kyma-project.io/v1", "Function", "fct", managedByReconciler).
+			isKymaCustomResource(crdGroupKinds))
+		//custom resources without the managed-by label (e.g. deployed by older reconciler versions) are ignored
+		require.False(t, newOrphan("kyma-project.io/v1", "Function", "fct", nil).
+			isKymaCustomResource(crdGroupKinds))
+		require.False(t, newOrphan("v1", "ConfigMap", "cm", managedByReconciler).
+			isKymaCustomResource(crdGroupKinds))
+	})
+
+	t.Run("Report orphaned resources", func(t *testing.T) {
+		err := &OrphanedResourcesError{
+			Resources: []*OrphanedResource{
+				newOrphan("kyma-project.io/v1", "Function", "fct", managedByReconciler, "serverless.kyma-project.io/deletion-hook"),
+				newOrphan("v1", "ConfigMap", "cm", nil),
+			},
+		}
+		require.Equal(t, "2 resource(s) still exist after deletion: "+
+			"Function [namespace:kyma-system|name:fct] (terminating) blocked by finalizers: serverless.kyma-project.io/deletion-hook, "+
+			"ConfigMap [namespace:kyma-system|name:cm]", err.Error())
+	})
+	t.Run("Wait for terminating resources without finalizers", func(t *testing.T) {
+		terminating := newOrphan("v1", "ConfigMap", "terminating", nil)
+		terminating.Terminating = true
+		blocked := newOrphan("kyma-project.io/v1", "Function", "fct", managedByReconciler, "serverless.kyma-project.io/deletion-hook")
+
+		calls := 0
+		orphans, err := waitForOrphans(context.Background(), time.Now().Add(time.Minute), time.Millisecond, func() ([]*OrphanedResource, error) {
+			calls++
+			if calls < 3 {
+				return []*OrphanedResource{terminating, blocked}, nil
+			}
+			return []*OrphanedResource{blocked}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+		require.Equal(t, []*OrphanedResource{blocked}, orphans)
+
+		//terminating resources without finalizers aren't reported after the timeout
+		orphans, err = waitForOrphans(context.Background(), time.Now().Add(10*time.Millisecond), time.Millisecond, func() ([]*OrphanedResource, error) {
+			return []*OrphanedResource{terminating}, nil
+		})
+		require.NoError(t, err)
+		require.Empty(t, orphans)
+
+		//an expired deadline doesn't extend the deletion: orphans are checked only once
+		calls = 0
+		orphans, err = waitForOrphans(context.Background(), time.Now().Add(-time.Second), time.Hour, func() ([]*OrphanedResource, error) {
+			calls++
+			return []*OrphanedResource{terminating, blocked}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+		require.Equal(t, []*OrphanedResource{blocked}, orphans)
+	})
+}
@@ -0,0 +1,478 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	maxWarningEventsPerObject = 3
+	jobLogTailLines           = int64(20)
+	maxJobLogLength           = 2000
+	warningEventType          = "Warning"
+)
+
+// TimeoutError is returned by the progress tracker if the watched resources didn't reach
+// their target state in time. It includes the diagnostics collected when the timeout was reached.
+type TimeoutError struct {
+	Message     string
+	Diagnostics *Diagnostics
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Diagnostics == nil || e.Diagnostics.Empty() {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, e.Diagnostics)
+}
+
+// Diagnostics contains the details about tracked resources which explain why they didn't reach their target state
+type Diagnostics struct {
+	UnreadyPods   []*PodDiagnostic
+	WarningEvents []*EventDiagnostic
+	JobLogs       []*JobLogDiagnostic
+	PendingPVCs   []*PVCDiagnostic
+	//Incomplete is set if the collection of the diagnostics was stopped by its timeout
+	Incomplete bool
+}
+
+func (d *Diagnostics) Empty() bool {
+	return len(d.UnreadyPods) == 0 && len(d.WarningEvents) == 0 && len(d.JobLogs) == 0 && len(d.PendingPVCs) == 0
+}
+
+func (d *Diagnostics) String() string {
+	var sections []string
+	if len(d.UnreadyPods) > 0 {
+		sections = append(sections, "unready pods: "+joinStrings(len(d.UnreadyPods), func(i int) string {
+			return d.UnreadyPods[i].String()
+		}))
+	}
+	if len(d.PendingPVCs) > 0 {
+		sections = append(sections, "unbound PVCs: "+joinStrings(len(d.PendingPVCs), func(i int) string {
+			return d.PendingPVCs[i].String()
+		}))
+	}
+	if len(d.WarningEvents) > 0 {
+		sections = append(sections, "warning events: "+joinStrings(len(d.WarningEvents), func(i int) string {
+			return d.WarningEvents[i].String()
+		}))
+	}
+	if len(d.JobLogs) > 0 {
+		sections = append(sections, "failed job logs: "+joinStrings(len(d.JobLogs), func(i int) string {
+			return d.JobLogs[i].String()
+		}))
+	}
+	if d.Incomplete && len(sections) > 0 {
+		sections = append(sections, "further diagnostics were skipped because their collection timed out")
+	}
+	return strings.Join(sections, "; ")
+}
+
+type PodDiagnostic struct {
+	Namespace  string
+	Name       string
+	Phase      corev1.PodPhase
+	Containers []*ContainerDiagnostic
+}
+
+func (p *PodDiagnostic) String() string {
+	if len(p.Containers) == 0 {
+		return fmt.Sprintf("%s/%s [phase:%s]", p.Namespace, p.Name, p.Phase)
+	}
+	containers := joinStrings(len(p.Containers), func(i int) string {
+		return p.Containers[i].String()
+	})
+	return fmt.Sprintf("%s/%s [phase:%s|%s]", p.Namespace, p.Name, p.Phase, containers)
+}
+
+type ContainerDiagnostic struct {
+	Name         string
+	State        string
+	Reason       string
+	Message      string
+	ExitCode     int32
+	RestartCount int32
+}
+
+func (c *ContainerDiagnostic) String() string {
+	result := fmt.Sprintf("container '%s' %s", c.Name, c.State)
+	if c.Reason != "" {
+		result = fmt.Sprintf("%s: %s", result, c.Reason)
+	}
+	if c.State == "terminated" {
+		result = fmt.Sprintf("%s (exit code %d)", result, c.ExitCode)
+	}
+	if c.Message != "" {
+		result = fmt.Sprintf("%s - %s", result, c.Message)
+	}
+	if c.RestartCount > 0 {
+		result = fmt.Sprintf("%s (restarts: %d)", result, c.RestartCount)
+	}
+	return result
+}
+
+type EventDiagnostic struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Reason    string
+	Message   string
+	Count     int32
+}
+
+func (e *EventDiagnostic) String() string {
+	return fmt.Sprintf("%s %s/%s %s: %s (x%d)", e.Kind, e.Namespace, e.Name, e.Reason, e.Message, e.Count)
+}
+
+type JobLogDiagnostic struct {
+	Namespace string
+	Job       string
+	Pod       string
+	Container string
+	Log       string
+}
+
+func (j *JobLogDiagnostic) String() string {
+	return fmt.Sprintf("job %s/%s pod '%s' container '%s': %s", j.Namespace, j.Job, j.Pod, j.Container, j.Log)
+}
+
+type PVCDiagnostic struct {
+	Namespace    string
+	Name         string
+	Phase        corev1.PersistentVolumeClaimPhase
+	StorageClass string
+}
+
+func (p *PVCDiagnostic) String() string {
+	return fmt.Sprintf("%s/%s [phase:%s|storageClass:%s]", p.Namespace, p.Name, p.Phase, p.StorageClass)
+}
+
+func joinStrings(count int, item func(i int) string) string {
+	result := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		result = append(result, item(i))
+	}
+	return strings.Join(result, ", ")
+}
+
+// collectDiagnostics gathers details about all tracked resources. Errors are logged but not returned
+// as the diagnostics are a best-effort attempt to explain a failure. The collection is bounded by the
+// diagnostics timeout: the details gathered until then are returned.
+func (pt *Tracker) collectDiagnostics(ctx context.Context) *Diagnostics {
+	ctx, cancel := context.WithTimeout(ctx, pt.diagnosticsTimeout)
+	defer cancel()
+
+	diag := &Diagnostics{}
+	expired := func() bool {
+		if ctx.Err() == nil {
+			return false
+		}
+		if !diag.Incomplete {
+			pt.logger.Debugf("Collection of diagnostics stopped after %.0f secs: reporting partial diagnostics",
+				pt.diagnosticsTimeout.Seconds())
+		}
+		diag.Incomplete = true
+		return true
+	}
+	var pvcs []string
+	pvcsAdded := make(map[string]bool)
+	eventTargets := make(map[string]*corev1.ObjectReference)
+
+	for _, object := range pt.objects {
+		if expired() {
+			break
+		}
+		eventTargets[eventTargetKey(object.namespace, string(object.kind), object.name)] = &corev1.ObjectReference{
+			Kind: string(object.kind), Namespace: object.namespace, Name: object.name,
+		}
+
+		pods, job, err := pt.podsOf(ctx, object)
+		if err != nil {
+			pt.logger.Debugf("Failed to collect pods of %v for diagnostics: %s", object, err)
+			continue
+		}
+		for i := range pods {
+			pod := &pods[i]
+			if isPodHealthy(pod) {
+				continue
+			}
+			diag.UnreadyPods = append(diag.UnreadyPods, newPodDiagnostic(pod))
+			eventTargets[eventTargetKey(pod.Namespace, "Pod", pod.Name)] = &corev1.ObjectReference{
+				Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name,
+			}
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+					if !pvcsAdded[pvcKey] {
+						pvcsAdded[pvcKey] = true
+						pvcs = append(pvcs, pvcKey)
+					}
+				}
+			}
+			if job != nil {
+				diag.JobLogs = append(diag.JobLogs, pt.failedJobPodLogs(ctx, job, pod)...)
+			}
+		}
+	}
+
+	for _, pvcKey := range pvcs {
+		if expired() {
+			break
+		}
+		nsAndName := strings.SplitN(pvcKey, "/", 2)
+		pvc, err := pt.client.CoreV1().PersistentVolumeClaims(nsAndName[0]).Get(ctx, nsAndName[1], metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				diag.PendingPVCs = append(diag.PendingPVCs, &PVCDiagnostic{
+					Namespace: nsAndName[0], Name: nsAndName[1], Phase: "Missing",
+				})
+			} else {
+				pt.logger.Debugf("Failed to get PVC '%s' for diagnostics: %s", pvcKey, err)
+			}
+			continue
+		}
+		if pvc.Status.Phase == corev1.ClaimBound {
+			continue
+		}
+		pvcDiag := &PVCDiagnostic{Namespace: pvc.Namespace, Name: pvc.Name, Phase: pvc.Status.Phase}
+		if pvc.Spec.StorageClassName != nil {
+			pvcDiag.StorageClass = *pvc.Spec.StorageClassName
+		}
+		diag.PendingPVCs = append(diag.PendingPVCs, pvcDiag)
+		eventTargets[eventTargetKey(pvc.Namespace, "PersistentVolumeClaim", pvc.Name)] = &corev1.ObjectReference{
+			Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name,
+		}
+	}
+
+	for _, key := range sortedKeys(eventTargets) {
+		if expired() {
+			break
+		}
+		diag.WarningEvents = append(diag.WarningEvents, pt.warningEvents(ctx, eventTargets[key])...)
+	}
+
+	return diag
+}
+
+// podsOf returns the pods belonging to the tracked resource (and the job if the resource is of kind Job)
+func (pt *Tracker) podsOf(ctx context.Context, object *trackerResource) ([]corev1.Pod, *batchv1.Job, error) {
+	var selector *metav1.LabelSelector
+	switch object.kind {
+	case Pod:
+		pod, err := pt.client.CoreV1().Pods(object.namespace).Get(ctx, object.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		return []corev1.Pod{*pod}, nil, nil
+	case Deployment:
+		deployment, err := pt.client.AppsV1().Deployments(object.namespace).Get(ctx, object.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		selector = deployment.Spec.Selector
+	case DaemonSet:
+		daemonSet, err := pt.client.AppsV1().DaemonSets(object.namespace).Get(ctx, object.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		selector = daemonSet.Spec.Selector
+	case StatefulSet:
+		statefulSet, err := pt.client.AppsV1().StatefulSets(object.namespace).Get(ctx, object.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		selector = statefulSet.Spec.Selector
+	case Job:
+		job, err := pt.client.BatchV1().Jobs(object.namespace).Get(ctx, object.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		selector = job.Spec.Selector
+		if selector == nil {
+			selector = &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": job.Name}}
+		}
+		pods, err := pt.listPods(ctx, object.namespace, selector)
+		return pods, job, err
+	default:
+		return nil, nil, nil
+	}
+	if selector == nil {
+		return nil, nil, nil
+	}
+	pods, err := pt.listPods(ctx, object.namespace, selector)
+	return pods, nil, err
+}
+
+func (pt *Tracker) listPods(ctx context.Context, namespace string, labelSelector *metav1.LabelSelector) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() { //an empty selector would match all pods in the namespace
+		return nil, nil
+	}
+	podList, err := pt.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (pt *Tracker) failedJobPodLogs(ctx context.Context, job *batchv1.Job, pod *corev1.Pod) []*JobLogDiagnostic {
+	var result []*JobLogDiagnostic
+	for _, status := range pod.Status.ContainerStatuses {
+		failed := pod.Status.Phase == corev1.PodFailed ||
+			(status.State.Terminated != nil && status.State.Terminated.ExitCode != 0) ||
+			(status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.ExitCode != 0)
+		if !failed {
+			continue
+		}
+		tailLines := jobLogTailLines
+		logs, err := pt.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container: status.Name,
+			TailLines: &tailLines,
+		}).DoRaw(ctx)
+		if err != nil {
+			pt.logger.Debugf("Failed to retrieve logs of container '%s' in pod '%s/%s' for diagnostics: %s",
+				status.Name, pod.Namespace, pod.Name, err)
+			continue
+		}
+		log := strings.TrimSpace(string(logs))
+		if len(log) > maxJobLogLength {
+			log = "..." + log[len(log)-maxJobLogLength:]
+		}
+		result = append(result, &JobLogDiagnostic{
+			Namespace: job.Namespace,
+			Job:       job.Name,
+			Pod:       pod.Name,
+			Container: status.Name,
+			Log:       log,
+		})
+	}
+	return result
+}
+
+func (pt *Tracker) warningEvents(ctx context.Context, ref *corev1.ObjectReference) []*EventDiagnostic {
+	eventList, err := pt.client.CoreV1().Events(ref.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.name": ref.Name,
+			"involvedObject.kind": ref.Kind,
+			"type":                warningEventType,
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		pt.logger.Debugf("Failed to retrieve events of %s '%s/%s' for diagnostics: %s", ref.Kind, ref.Namespace, ref.Name, err)
+		return nil
+	}
+
+	var events []corev1.Event
+	for _, event := range eventList.Items {
+		//filter again as not all clients support field selectors
+		if event.Type == warningEventType && event.InvolvedObject.Name == ref.Name && event.InvolvedObject.Kind == ref.Kind {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { //most recent events first
+		return events[j].LastTimestamp.Before(&events[i].LastTimestamp)
+	})
+	if len(events) > maxWarningEventsPerObject {
+		events = events[:maxWarningEventsPerObject]
+	}
+
+	var result []*EventDiagnostic
+	for _, event := range events {
+		result = append(result, &EventDiagnostic{
+			Namespace: ref.Namespace,
+			Kind:      ref.Kind,
+			Name:      ref.Name,
+			Reason:    event.Reason,
+			Message:   strings.TrimSpace(event.Message),
+			Count:     event.Count,
+		})
+	}
+	return result
+}
+
+func isPodHealthy(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return true
+	}
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+func newPodDiagnostic(pod *corev1.Pod) *PodDiagnostic {
+	result := &PodDiagnostic{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Phase:     pod.Status.Phase,
+	}
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.Ready {
+			continue
+		}
+		container := &ContainerDiagnostic{
+			Name:         status.Name,
+			RestartCount: status.RestartCount,
+		}
+		switch {
+		case status.State.Waiting != nil:
+			container.State = "waiting"
+			container.Reason = status.State.Waiting.Reason
+			container.Message = status.State.Waiting.Message
+		case status.State.Terminated != nil:
+			container.State = "terminated"
+			container.Reason = status.State.Terminated.Reason
+			container.Message = status.State.Terminated.Message
+			container.ExitCode = status.State.Terminated.ExitCode
+		default:
+			container.State = "running"
+			if status.LastTerminationState.Terminated != nil {
+				container.Reason = status.LastTerminationState.Terminated.Reason
+			}
+		}
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 &&
+			status.State.Terminated.Reason == "Completed" {
+			continue //successfully finished (init-)containers are not relevant
+		}
+		result.Containers = append(result.Containers, container)
+	}
+	return result
+}
+
+func eventTargetKey(namespace, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, kind, name)
+}
+
+func sortedKeys(m map[string]*corev1.ObjectReference) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
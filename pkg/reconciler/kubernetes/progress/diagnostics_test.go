@@ -0,0 +1,198 @@
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCollectDiagnostics(t *testing.T) {
+	storageClass := "standard"
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	objects := []*trackerResource{
+		{kind: Deployment, namespace: "kyma-system", name: "foo"},
+		{kind: Job, namespace: "kyma-system", name: "bar"},
+	}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "kyma-system"},
+			Spec:       appsv1.DeploymentSpec{Selector: selector},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-123", Namespace: "kyma-system", Labels: map[string]string{"app": "foo"}},
+			Spec: v1.PodSpec{
+				Volumes: []v1.Volume{
+					{
+						Name: "data",
+						VolumeSource: v1.VolumeSource{
+							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "foo-data"},
+						},
+					},
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "foo",
+						State: v1.ContainerState{
+							Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+						},
+					},
+				},
+			},
+		},
+		&v1.Pod{ //ready pod is not reported
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-456", Namespace: "kyma-system", Labels: map[string]string{"app": "foo"}},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-data", Namespace: "kyma-system"},
+			Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		},
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "foo-123.1", Namespace: "kyma-system"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "foo-123", Namespace: "kyma-system"},
+			Type:           "Warning",
+			Reason:         "Failed",
+			Message:        "Failed to pull image",
+			Count:          4,
+		},
+		&v1.Event{ //normal events are not reported
+			ObjectMeta:     metav1.ObjectMeta{Name: "foo-123.2", Namespace: "kyma-system"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "foo-123", Namespace: "kyma-system"},
+			Type:           "Normal",
+			Reason:         "Scheduled",
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "kyma-system"},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-123", Namespace: "kyma-system", Labels: map[string]string{"job-name": "bar"}},
+			Status: v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "bar",
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	pt, err := NewProgressTracker(client, zap.NewNop().Sugar(), Config{Interval: 1 * time.Second, Timeout: 1 * time.Minute})
+	require.NoError(t, err)
+	pt.objects = objects
+
+	diag := pt.collectDiagnostics(context.Background())
+
+	require.Len(t, diag.UnreadyPods, 2)
+	require.Equal(t, "foo-123", diag.UnreadyPods[0].Name)
+	require.Equal(t, "ImagePullBackOff", diag.UnreadyPods[0].Containers[0].Reason)
+	require.Equal(t, "bar-123", diag.UnreadyPods[1].Name)
+	require.Equal(t, int32(1), diag.UnreadyPods[1].Containers[0].ExitCode)
+
+	require.Len(t, diag.PendingPVCs, 1)
+	require.Equal(t, "foo-data", diag.PendingPVCs[0].Name)
+	require.Equal(t, v1.ClaimPending, diag.PendingPVCs[0].Phase)
+
+	require.Len(t, diag.WarningEvents, 1)
+	require.Equal(t, "Failed", diag.WarningEvents[0].Reason)
+	require.Equal(t, "foo-123", diag.WarningEvents[0].Name)
+
+	require.Len(t, diag.JobLogs, 1)
+	require.Equal(t, "bar-123", diag.JobLogs[0].Pod)
+	require.Equal(t, "fake logs", diag.JobLogs[0].Log) //returned by the fake client
+
+	msg := diag.String()
+	require.Contains(t, msg, "unready pods: kyma-system/foo-123")
+	require.Contains(t, msg, "ImagePullBackOff")
+	require.Contains(t, msg, "unbound PVCs: kyma-system/foo-data")
+	require.Contains(t, msg, "warning events: Pod kyma-system/foo-123 Failed: Failed to pull image (x4)")
+	require.Contains(t, msg, "failed job logs: job kyma-system/bar")
+}
+
+func TestWatchTimeoutIncludesDiagnostics(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "kyma-system"},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "foo",
+						State: v1.ContainerState{
+							Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+						RestartCount: 3,
+					},
+				},
+			},
+		},
+	)
+
+	pt, err := NewProgressTracker(client, zap.NewNop().Sugar(), Config{Interval: 50 * time.Millisecond, Timeout: 200 * time.Millisecond})
+	require.NoError(t, err)
+	pt.AddResource(Pod, "kyma-system", "foo")
+
+	err = pt.Watch(context.Background(), ReadyState)
+	require.Error(t, err)
+	timeoutErr, ok := err.(*TimeoutError)
+	require.True(t, ok)
+	require.Len(t, timeoutErr.Diagnostics.UnreadyPods, 1)
+	require.Contains(t, err.Error(), "progress tracker reached timeout")
+	require.Contains(t, err.Error(), "container 'foo' waiting: CrashLoopBackOff (restarts: 3)")
+}
+
+func TestCollectDiagnosticsTimeout(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "kyma-system"},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "kyma-system"},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+	)
+	//slow API server: listing events exceeds the diagnostics timeout
+	client.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		time.Sleep(100 * time.Millisecond)
+		return false, nil, nil
+	})
+
+	pt, err := NewProgressTracker(client, zap.NewNop().Sugar(), Config{
+		Interval:           1 * time.Second,
+		Timeout:            1 * time.Minute,
+		DiagnosticsTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	pt.AddResource(Pod, "kyma-system", "foo")
+	pt.AddResource(Pod, "kyma-system", "bar")
+
+	start := time.Now()
+	diag := pt.collectDiagnostics(context.Background())
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	//pods were collected before the timeout expired
+	require.Len(t, diag.UnreadyPods, 2)
+	require.True(t, diag.Incomplete)
+	require.Contains(t, diag.String(), "further diagnostics were skipped")
+}
@@ -18,6 +18,8 @@ import (
 const (
 	defaultProgressInterval = 20 * time.Second
 	defaultProgressTimeout  = 10 * time.Minute
+	//defaultDiagnosticsTimeout limits the collection of diagnostics after the progress timeout was reached
+	defaultDiagnosticsTimeout = 10 * time.Second
 
 	ReadyState      State = "ready"
 	TerminatedState State = "terminated"
@@ -39,6 +41,9 @@ func (o *trackerResource) String() string {
 type Config struct {
 	Interval time.Duration
 	Timeout  time.Duration
+	//DiagnosticsTimeout limits the collection of diagnostics when the timeout is reached (partial diagnostics
+	//are reported if it expires)
+	DiagnosticsTimeout time.Duration
 }
 
 func (ptc *Config) validate() error {
@@ -54,6 +59,12 @@ func (ptc *Config) validate() error {
 	if ptc.Timeout == 0 {
 		ptc.Timeout = defaultProgressTimeout
 	}
+	if ptc.DiagnosticsTimeout < 0 {
+		return fmt.Errorf("progress tracker diagnostics timeout cannot be < 0")
+	}
+	if ptc.DiagnosticsTimeout == 0 {
+		ptc.DiagnosticsTimeout = defaultDiagnosticsTimeout
+	}
 	if ptc.Timeout <= ptc.Interval {
 		return fmt.Errorf("progress tracker will never run because configured timeout "+
 			"is <= as the check interval :%.0f secs <= %.0f secs", ptc.Timeout.Seconds(), ptc.Interval.Seconds())
@@ -62,11 +73,12 @@ func (ptc *Config) validate() error {
 }
 
 type Tracker struct {
-	objects  []*trackerResource
-	client   kubernetes.Interface
-	interval time.Duration
-	timeout  time.Duration
-	logger   *zap.SugaredLogger
+	objects            []*trackerResource
+	client             kubernetes.Interface
+	interval           time.Duration
+	timeout            time.Duration
+	diagnosticsTimeout time.Duration
+	logger             *zap.SugaredLogger
 }
 
 func NewProgressTracker(client kubernetes.Interface, logger *zap.SugaredLogger, config Config) (*Tracker, error) {
@@ -75,10 +87,11 @@ func NewProgressTracker(client kubernetes.Interface, logger *zap.SugaredLogger,
 	}
 
 	return &Tracker{
-		client:   client,
-		interval: config.Interval,
-		timeout:  config.Timeout,
-		logger:   logger,
+		client:             client,
+		interval:           config.Interval,
+		timeout:            config.Timeout,
+		diagnosticsTimeout: config.DiagnosticsTimeout,
+		logger:             logger,
 	}, nil
 }
 
@@ -125,9 +138,12 @@ func (pt *Tracker) Watch(ctx context.Context, targetState State) error {
 					"transition is treated as failed", targetState),
 			}
 		case <-timeout:
-			err := fmt.Errorf("progress tracker reached timeout (%.0f secs): "+
-				"stop checking progress of resource transition to state '%s'",
-				pt.timeout.Seconds(), targetState)
+			err := &TimeoutError{
+				Message: fmt.Sprintf("progress tracker reached timeout (%.0f secs): "+
+					"stop checking progress of resource transition to state '%s'",
+					pt.timeout.Seconds(), targetState),
+				Diagnostics: pt.collectDiagnostics(ctx),
+			}
 			pt.logger.Warn(err.Error())
 			pt.dumpWatchableResourcesAsInfo(ctx)
 			return err
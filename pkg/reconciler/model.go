@@ -42,6 +42,8 @@ type Task struct {
 	Version                string                 `json:"version"`
 	URL                    string                 `json:"url"`
 	Profile                string                 `json:"profile"`
+	ProfileValues          map[string]interface{} `json:"profileValues"` //ProfileValues override the values of the profile (e.g. managed in KV buckets)
+	Landscape              string                 `json:"landscape"`
 	Configuration          map[string]interface{} `json:"configuration"`
 	Kubeconfig             string                 `json:"kubeconfig"`
 	Metadata               keb.Metadata           `json:"metadata"`
@@ -94,6 +96,20 @@ func (r *Task) Validate() error {
 	return err
 }
 
+//PrefetchRequest asks a component reconciler to prepare the workspace of a Kyma version in the background
+type PrefetchRequest struct {
+	Version    string      `json:"version"`
+	Repository *Repository `json:"repository,omitempty"` //default Kyma repository is used if undefined
+}
+
+func (r *PrefetchRequest) Validate() error {
+	r.Version = strings.TrimSpace(r.Version)
+	if r.Version == "" {
+		return fmt.Errorf("mandatory fields are undefined: Version")
+	}
+	return nil
+}
+
 type Repository struct {
 	URL            string `json:"url"`
 	TokenNamespace string `json:"tokenNamespace"`
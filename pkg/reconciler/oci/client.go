@@ -0,0 +1,396 @@
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	file "github.com/kyma-incubator/reconciler/pkg/files"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	MediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeHelmChart      = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	MediaTypeOCILayer       = "application/vnd.oci.image.layer.v1.tar+gzip"
+	MediaTypeDockerLayer    = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+	maxManifestSize         = 4 * 1024 * 1024
+	//requestTimeout limits each request to the registry including the download of the response body
+	//(blobs of Kyma workspaces can be large)
+	requestTimeout = 10 * time.Minute
+)
+
+//supportedLayerTypes lists the media types of layers which can be extracted (in order of preference)
+var supportedLayerTypes = []string{MediaTypeHelmChart, MediaTypeOCILayer, MediaTypeDockerLayer}
+
+//Extractor extracts a pulled layer (a tar.gz archive) into the destination directory. Layers are untrusted content:
+//the extractor has to protect against path traversal, links and archives of excessive size.
+type Extractor func(layerFile, dstDir string) error
+
+type Credentials struct {
+	Username string
+	Password string
+}
+
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+//Client pulls artifacts from an OCI registry. Downloaded blobs are stored in a content-addressed cache.
+type Client struct {
+	cacheDir    string
+	credentials *Credentials
+	httpClient  *http.Client
+	logger      *zap.SugaredLogger
+	tokens      map[string]string //bearer tokens per scope
+	mu          sync.Mutex
+}
+
+func NewClient(cacheDir string, credentials *Credentials, logger *zap.SugaredLogger) *Client {
+	return &Client{
+		cacheDir:    cacheDir,
+		credentials: credentials,
+		httpClient:  &http.Client{Timeout: requestTimeout},
+		logger:      logger,
+		tokens:      make(map[string]string),
+	}
+}
+
+//Resolve returns the digest of the manifest the reference points to
+func (c *Client) Resolve(ref *Reference) (string, error) {
+	_, digest, err := c.manifest(ref)
+	return digest, err
+}
+
+//Pull downloads the artifact the reference points to and extracts its content with the extractor into the
+//destination directory. The digest of the pulled manifest is returned.
+func (c *Client) Pull(ref *Reference, dstDir string, extract Extractor) (string, error) {
+	manifest, digest, err := c.manifest(ref)
+	if err != nil {
+		return "", err
+	}
+
+	layer, err := c.selectLayer(ref, manifest)
+	if err != nil {
+		return "", err
+	}
+
+	blobFile, err := c.blob(ref, layer)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Infof("Extracting layer '%s' of OCI artifact '%s' into directory '%s'", layer.Digest, ref, dstDir)
+	if err := os.MkdirAll(dstDir, 0700); err != nil {
+		return "", err
+	}
+	if err := extract(blobFile, dstDir); err != nil {
+		return "", errors.Wrapf(err, "failed to extract layer '%s' of OCI artifact '%s'", layer.Digest, ref)
+	}
+	return digest, nil
+}
+
+func (c *Client) selectLayer(ref *Reference, manifest *Manifest) (*Descriptor, error) {
+	for _, mediaType := range supportedLayerTypes {
+		for idx := range manifest.Layers {
+			if manifest.Layers[idx].MediaType == mediaType {
+				return &manifest.Layers[idx], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("OCI artifact '%s' contains no layer of a supported media type (%s)",
+		ref, strings.Join(supportedLayerTypes, ", "))
+}
+
+func (c *Client) manifest(ref *Reference) (*Manifest, string, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url(ref, "manifests", ref.reference()), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", strings.Join([]string{MediaTypeOCIManifest, MediaTypeDockerManifest}, ", "))
+
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to retrieve manifest of OCI artifact '%s': registry returned status %d",
+			ref, resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return nil, "", err
+	}
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+	if ref.Digest != "" && ref.Digest != digest {
+		return nil, "", fmt.Errorf("digest of manifest of OCI artifact '%s' is '%s' "+
+			"but expected was '%s'", ref, digest, ref.Digest)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse manifest of OCI artifact '%s'", ref)
+	}
+	return manifest, digest, nil
+}
+
+//blob returns the path to the blob in the cache. The blob is downloaded if it's not cached yet.
+func (c *Client) blob(ref *Reference, layer *Descriptor) (string, error) {
+	if !digestRegex.MatchString(layer.Digest) {
+		return "", fmt.Errorf("digest '%s' of layer in OCI artifact '%s' is not supported", layer.Digest, ref)
+	}
+	blobDir := BlobDir(c.cacheDir)
+	blobFile := filepath.Join(blobDir, strings.TrimPrefix(layer.Digest, "sha256:"))
+
+	if file.Exists(blobFile) {
+		if err := verifyDigest(blobFile, layer.Digest); err == nil {
+			c.logger.Debugf("Using cached blob '%s' of OCI artifact '%s'", layer.Digest, ref)
+			return blobFile, nil
+		}
+		c.logger.Warnf("Cached blob '%s' is corrupted and will be downloaded again", blobFile)
+	}
+
+	if err := os.MkdirAll(blobDir, 0700); err != nil {
+		return "", err
+	}
+
+	c.logger.Infof("Downloading blob '%s' of OCI artifact '%s'", layer.Digest, ref)
+	req, err := http.NewRequest(http.MethodGet, c.url(ref, "blobs", layer.Digest), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download blob '%s' of OCI artifact '%s': registry returned status %d",
+			layer.Digest, ref, resp.StatusCode)
+	}
+
+	tmpFile, err := ioutil.TempFile(blobDir, "download-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := os.Remove(tmpFile.Name()); err != nil && !os.IsNotExist(err) {
+			c.logger.Warnf("Failed to remove temporary blob file '%s': %s", tmpFile.Name(), err)
+		}
+	}()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmpFile, hash), resp.Body)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if layer.Size > 0 && written != layer.Size {
+		return "", fmt.Errorf("size of blob '%s' of OCI artifact '%s' is %d bytes but expected were %d bytes",
+			layer.Digest, ref, written, layer.Size)
+	}
+	if digest := "sha256:" + hex.EncodeToString(hash.Sum(nil)); digest != layer.Digest {
+		return "", fmt.Errorf("digest of downloaded blob of OCI artifact '%s' is '%s' but expected was '%s'",
+			ref, digest, layer.Digest)
+	}
+
+	return blobFile, os.Rename(tmpFile.Name(), blobFile)
+}
+
+//BlobDir returns the directory which contains the cached blobs of a client using the given cache directory
+func BlobDir(cacheDir string) string {
+	return filepath.Join(cacheDir, "blobs", "sha256")
+}
+
+func verifyDigest(fileName, expectedDigest string) error {
+	fileHandler, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer fileHandler.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, fileHandler); err != nil {
+		return err
+	}
+	if digest := "sha256:" + hex.EncodeToString(hash.Sum(nil)); digest != expectedDigest {
+		return fmt.Errorf("digest of file '%s' is '%s' but expected was '%s'", fileName, digest, expectedDigest)
+	}
+	return nil
+}
+
+func (c *Client) url(ref *Reference, kind, reference string) string {
+	scheme := "https"
+	if isLoopback(ref.Registry) { //local registries are accessed via plain HTTP (same behaviour as Docker)
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s/%s", scheme, ref.Registry, ref.Repository, kind, reference)
+}
+
+func isLoopback(registry string) bool {
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+//do sends the request and handles the authentication challenge of the registry
+func (c *Client) do(req *http.Request, ref *Reference) (*http.Response, error) {
+	scope := fmt.Sprintf("repository:%s:pull", ref.Repository)
+
+	c.mu.Lock()
+	token, ok := c.tokens[scope]
+	c.mu.Unlock()
+	if ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	retryReq := req.Clone(req.Context())
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if c.credentials == nil {
+			return nil, fmt.Errorf("OCI registry '%s' requires credentials but none were provided", ref.Registry)
+		}
+		retryReq.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	case "bearer":
+		token, err := c.fetchToken(params, scope)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to authenticate at OCI registry '%s'", ref.Registry)
+		}
+		c.mu.Lock()
+		c.tokens[scope] = token
+		c.mu.Unlock()
+		retryReq.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return nil, fmt.Errorf("OCI registry '%s' requested unsupported authentication '%s'", ref.Registry, challenge)
+	}
+	return c.httpClient.Do(retryReq)
+}
+
+func (c *Client) fetchToken(params map[string]string, scope string) (string, error) {
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("authentication challenge of registry contains no realm")
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	query := tokenURL.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	if challengeScope, ok := params["scope"]; ok {
+		scope = challengeScope
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.credentials != nil {
+		req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint '%s' returned status %d", realm, resp.StatusCode)
+	}
+
+	tokenResp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, nil
+	}
+	return "", fmt.Errorf("token endpoint '%s' returned no token", realm)
+}
+
+//parseChallenge parses a WWW-Authenticate header (e.g. 'Bearer realm="https://auth.io/token",service="registry"')
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	tokens := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	scheme := strings.ToLower(tokens[0])
+	if len(tokens) == 1 {
+		return scheme, params
+	}
+	for _, param := range splitParams(tokens[1]) {
+		keyValue := strings.SplitN(param, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		params[strings.ToLower(strings.TrimSpace(keyValue[0]))] = strings.Trim(strings.TrimSpace(keyValue[1]), `"`)
+	}
+	return scheme, params
+}
+
+//splitParams splits comma separated parameters but ignores commas in quoted values
+func splitParams(params string) []string {
+	var result []string
+	var current strings.Builder
+	quoted := false
+	for _, char := range params {
+		switch {
+		case char == '"':
+			quoted = !quoted
+			current.WriteRune(char)
+		case char == ',' && !quoted:
+			result = append(result, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	return append(result, current.String())
+}
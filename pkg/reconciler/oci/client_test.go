@@ -0,0 +1,192 @@
+package oci
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/oci/test"
+	"github.com/mholt/archiver/v3"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var chartFiles = map[string]string{
+	"mychart/Chart.yaml":       "apiVersion: v2\nname: mychart\nversion: 1.0.0\n",
+	"mychart/values.yaml":      "replicas: 1\n",
+	"mychart/templates/a.yaml": "kind: ConfigMap\n",
+}
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	t.Run("Parse valid references", func(t *testing.T) {
+		testCases := map[string]*Reference{
+			"oci://eu.gcr.io/kyma/charts/serverless:1.0.0": {
+				Registry: "eu.gcr.io", Repository: "kyma/charts/serverless", Tag: "1.0.0"},
+			"oci://localhost:5000/kyma": {
+				Registry: "localhost:5000", Repository: "kyma"},
+			"oci://localhost:5000/kyma@" + digest: {
+				Registry: "localhost:5000", Repository: "kyma", Digest: digest},
+			"oci://localhost:5000/kyma:2.0.0@" + digest: {
+				Registry: "localhost:5000", Repository: "kyma", Tag: "2.0.0", Digest: digest},
+		}
+		for url, expected := range testCases {
+			ref, err := ParseReference(url)
+			require.NoError(t, err, url)
+			require.Equal(t, expected, ref, url)
+			require.Equal(t, url, ref.String())
+		}
+	})
+
+	t.Run("Reject invalid references", func(t *testing.T) {
+		for _, url := range []string{
+			"https://eu.gcr.io/kyma",
+			"oci://eu.gcr.io",
+			"oci://eu.gcr.io/",
+			"oci://eu.gcr.io/kyma@md5:123",
+			"oci://eu.gcr.io/kyma/:1.0.0",
+		} {
+			_, err := ParseReference(url)
+			require.Error(t, err, url)
+		}
+	})
+
+	t.Run("Apply default tag", func(t *testing.T) {
+		ref, err := ParseReference("oci://eu.gcr.io/kyma")
+		require.NoError(t, err)
+		require.Equal(t, "2.0.0", ref.WithDefaultTag("2.0.0").reference())
+		require.Equal(t, "", ref.Tag) //original reference is unchanged
+		require.Equal(t, "latest", ref.reference())
+
+		ref, err = ParseReference("oci://eu.gcr.io/kyma:1.0.0")
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0", ref.WithDefaultTag("2.0.0").reference())
+		require.Equal(t, digest, ref.WithDigest(digest).reference())
+	})
+}
+
+func TestClient(t *testing.T) {
+	log := logger.NewLogger(true)
+
+	t.Run("Pull artifact and use cache", func(t *testing.T) {
+		registry := test.NewRegistry(t)
+		digest := registry.Push(t, "charts/mychart", "1.0.0", chartFiles)
+
+		client := NewClient(t.TempDir(), nil, log)
+		ref, err := ParseReference("oci://" + registry.Host() + "/charts/mychart:1.0.0")
+		require.NoError(t, err)
+
+		resolved, err := client.Resolve(ref)
+		require.NoError(t, err)
+		require.Equal(t, digest, resolved)
+
+		dstDir := t.TempDir()
+		pulled, err := client.Pull(ref, dstDir, extractTarGz)
+		require.NoError(t, err)
+		require.Equal(t, digest, pulled)
+		requireFileContent(t, filepath.Join(dstDir, "mychart", "Chart.yaml"), chartFiles["mychart/Chart.yaml"])
+		require.Equal(t, 1, registry.BlobRequests())
+
+		//second pull has to use the cached blob
+		_, err = client.Pull(ref.WithDigest(digest), t.TempDir(), extractTarGz)
+		require.NoError(t, err)
+		require.Equal(t, 1, registry.BlobRequests())
+	})
+
+	t.Run("Reject manifest with unexpected digest", func(t *testing.T) {
+		registry := test.NewRegistry(t)
+		registry.Push(t, "charts/mychart", "1.0.0", chartFiles)
+
+		client := NewClient(t.TempDir(), nil, log)
+		ref, err := ParseReference("oci://" + registry.Host() + "/charts/mychart:1.0.0@sha256:" + strings.Repeat("0", 64))
+		require.NoError(t, err)
+		_, err = client.Pull(ref, t.TempDir(), extractTarGz)
+		require.Error(t, err)
+	})
+
+	t.Run("Reject corrupted blob", func(t *testing.T) {
+		registry := test.NewRegistry(t)
+		registry.Push(t, "charts/mychart", "1.0.0", chartFiles)
+		registry.CorruptBlobs()
+
+		cacheDir := t.TempDir()
+		client := NewClient(cacheDir, nil, log)
+		ref, err := ParseReference("oci://" + registry.Host() + "/charts/mychart:1.0.0")
+		require.NoError(t, err)
+		_, err = client.Pull(ref, t.TempDir(), extractTarGz)
+		require.Error(t, err)
+
+		blobs, err := ioutil.ReadDir(filepath.Join(cacheDir, "blobs", "sha256"))
+		require.NoError(t, err)
+		require.Empty(t, blobs) //corrupted blob is not cached
+	})
+
+	t.Run("Authenticate with basic auth", func(t *testing.T) {
+		registry := test.NewRegistry(t).WithBasicAuth("user", "pwd")
+		registry.Push(t, "charts/mychart", "1.0.0", chartFiles)
+		ref, err := ParseReference("oci://" + registry.Host() + "/charts/mychart:1.0.0")
+		require.NoError(t, err)
+
+		_, err = NewClient(t.TempDir(), nil, log).Pull(ref, t.TempDir(), extractTarGz)
+		require.Error(t, err)
+
+		_, err = NewClient(t.TempDir(), &Credentials{Username: "user", Password: "pwd"}, log).Pull(ref, t.TempDir(), extractTarGz)
+		require.NoError(t, err)
+	})
+
+	t.Run("Authenticate with bearer token", func(t *testing.T) {
+		registry := test.NewRegistry(t).WithTokenAuth("user", "pwd")
+		registry.Push(t, "charts/mychart", "1.0.0", chartFiles)
+		ref, err := ParseReference("oci://" + registry.Host() + "/charts/mychart:1.0.0")
+		require.NoError(t, err)
+
+		_, err = NewClient(t.TempDir(), &Credentials{Username: "user", Password: "wrong"}, log).Pull(ref, t.TempDir(), extractTarGz)
+		require.Error(t, err)
+
+		_, err = NewClient(t.TempDir(), &Credentials{Username: "user", Password: "pwd"}, log).Pull(ref, t.TempDir(), extractTarGz)
+		require.NoError(t, err)
+	})
+}
+
+func extractTarGz(layerFile, dstDir string) error {
+	return archiver.NewTarGz().Unarchive(layerFile, dstDir)
+}
+
+func TestLoadCredentials(t *testing.T) {
+	log := logger.NewLogger(true)
+	clientSet := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "eu.gcr.io", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pwd\n")},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ghcr.io", Namespace: "kyma-system"},
+			Data:       map[string][]byte{"token": []byte("token")},
+		},
+	)
+
+	credentials, err := LoadCredentials(clientSet, "eu.gcr.io:443", "", log)
+	require.NoError(t, err)
+	require.Equal(t, &Credentials{Username: "user", Password: "pwd"}, credentials)
+
+	credentials, err = LoadCredentials(clientSet, "ghcr.io", "kyma-system", log)
+	require.NoError(t, err)
+	require.Equal(t, "token", credentials.Password)
+	require.NotEmpty(t, credentials.Username)
+
+	credentials, err = LoadCredentials(clientSet, "ghcr.io", "", log)
+	require.NoError(t, err)
+	require.Nil(t, credentials)
+}
+
+func requireFileContent(t *testing.T, file, expected string) {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(data))
+}
@@ -0,0 +1,58 @@
+package oci
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const defaultTokenNamespace = "default"
+
+//LoadCredentials reads the credentials of a registry from a secret in the given namespace. The secret has to be named
+//like the host of the registry (e.g. 'eu.gcr.io') and has to contain either the keys 'username' and 'password'
+//or the key 'token'. Nil is returned if no such secret exists or access to it is forbidden.
+func LoadCredentials(clientSet k8s.Interface, registry, tokenNamespace string, logger *zap.SugaredLogger) (*Credentials, error) {
+	if clientSet == nil {
+		return nil, nil
+	}
+	if tokenNamespace == "" {
+		tokenNamespace = defaultTokenNamespace
+	}
+
+	secretKey := secretName(registry)
+	secret, err := clientSet.CoreV1().
+		Secrets(tokenNamespace).
+		Get(context.Background(), secretKey, v1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			logger.Infof("Credentials for OCI registry '%s' not found or forbidden (secret '%s:%s')",
+				registry, tokenNamespace, secretKey)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if token := strings.Trim(string(secret.Data["token"]), "\n"); token != "" {
+		return &Credentials{
+			Username: "xxx", // anything but an empty string
+			Password: token,
+		}, nil
+	}
+	return &Credentials{
+		Username: strings.Trim(string(secret.Data["username"]), "\n"),
+		Password: strings.Trim(string(secret.Data["password"]), "\n"),
+	}, nil
+}
+
+func secretName(registry string) string {
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	return strings.TrimPrefix(host, "www.")
+}
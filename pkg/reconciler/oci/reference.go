@@ -0,0 +1,97 @@
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	Scheme     = "oci://"
+	defaultTag = "latest"
+)
+
+var digestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+//Reference points to an artifact in an OCI registry (e.g. 'oci://eu.gcr.io/kyma-project/charts/serverless:1.0.0')
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+//IsOCIURL returns true if the URL uses the OCI scheme
+func IsOCIURL(url string) bool {
+	return strings.HasPrefix(strings.ToLower(url), Scheme)
+}
+
+func ParseReference(url string) (*Reference, error) {
+	if !IsOCIURL(url) {
+		return nil, fmt.Errorf("OCI reference '%s' has to start with '%s'", url, Scheme)
+	}
+	path := url[len(Scheme):]
+	idx := strings.Index(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return nil, fmt.Errorf("OCI reference '%s' has to include a registry and a repository", url)
+	}
+
+	ref := &Reference{Registry: path[:idx]}
+	repository := path[idx+1:]
+	if idx := strings.Index(repository, "@"); idx >= 0 {
+		ref.Digest = repository[idx+1:]
+		repository = repository[:idx]
+		if !digestRegex.MatchString(ref.Digest) {
+			return nil, fmt.Errorf("digest '%s' of OCI reference '%s' is invalid: "+
+				"only sha256 digests are supported", ref.Digest, url)
+		}
+	}
+	if idx := strings.LastIndex(repository, ":"); idx >= 0 {
+		ref.Tag = repository[idx+1:]
+		repository = repository[:idx]
+	}
+	if repository == "" || strings.HasSuffix(repository, "/") {
+		return nil, fmt.Errorf("repository of OCI reference '%s' is invalid", url)
+	}
+	ref.Repository = repository
+	return ref, nil
+}
+
+//WithDefaultTag returns a copy of the reference which uses the given tag if the reference has neither a tag nor a digest
+func (r *Reference) WithDefaultTag(tag string) *Reference {
+	result := *r
+	if result.Tag == "" && result.Digest == "" {
+		result.Tag = tag
+	}
+	return &result
+}
+
+//WithDigest returns a copy of the reference which is pinned to the given digest
+func (r *Reference) WithDigest(digest string) *Reference {
+	result := *r
+	result.Digest = digest
+	return &result
+}
+
+//reference returns the tag or digest used to retrieve the manifest (a digest is preferred)
+func (r *Reference) reference() string {
+	switch {
+	case r.Digest != "":
+		return r.Digest
+	case r.Tag != "":
+		return r.Tag
+	default:
+		return defaultTag
+	}
+}
+
+func (r *Reference) String() string {
+	result := fmt.Sprintf("%s%s/%s", Scheme, r.Registry, r.Repository)
+	if r.Tag != "" {
+		result += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		result += "@" + r.Digest
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	manifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	configMediaType   = "application/vnd.cncf.helm.config.v1+json"
+	chartMediaType    = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	bearerToken       = "test-token"
+)
+
+//Registry is an in-process OCI registry which serves pushed artifacts (pull-only, no push API)
+type Registry struct {
+	server       *httptest.Server
+	username     string
+	password     string
+	bearer       bool
+	manifests    map[string][]byte //key is '<repository>:<tag or digest>'
+	blobs        map[string][]byte //key is digest
+	blobRequests int
+	mu           sync.Mutex
+}
+
+//NewRegistry starts a registry which is stopped when the test is finished
+func NewRegistry(t *testing.T) *Registry {
+	registry := &Registry{
+		manifests: make(map[string][]byte),
+		blobs:     make(map[string][]byte),
+	}
+	registry.server = httptest.NewServer(http.HandlerFunc(registry.handle))
+	t.Cleanup(registry.server.Close)
+	return registry
+}
+
+//WithBasicAuth requires basic authentication for all requests
+func (r *Registry) WithBasicAuth(username, password string) *Registry {
+	r.username = username
+	r.password = password
+	r.bearer = false
+	return r
+}
+
+//WithTokenAuth requires bearer tokens for all requests. Tokens are issued by the registry
+//if the token request contains the given credentials.
+func (r *Registry) WithTokenAuth(username, password string) *Registry {
+	r.username = username
+	r.password = password
+	r.bearer = true
+	return r
+}
+
+//Host returns the address of the registry (e.g. '127.0.0.1:12345')
+func (r *Registry) Host() string {
+	return strings.TrimPrefix(r.server.URL, "http://")
+}
+
+//BlobRequests returns how often blobs were downloaded
+func (r *Registry) BlobRequests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.blobRequests
+}
+
+//Push stores the files as gzipped tarball in a Helm chart artifact and returns the digest of its manifest
+func (r *Registry) Push(t *testing.T, repository, tag string, files map[string]string) string {
+	layer := newTarGz(t, files)
+	config := []byte("{}")
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     manifestMediaType,
+		"config":        descriptor(configMediaType, config),
+		"layers":        []interface{}{descriptor(chartMediaType, layer)},
+	})
+	require.NoError(t, err)
+	manifestDigest := digest(manifest)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs[digest(config)] = config
+	r.blobs[digest(layer)] = layer
+	r.manifests[fmt.Sprintf("%s:%s", repository, tag)] = manifest
+	r.manifests[fmt.Sprintf("%s:%s", repository, manifestDigest)] = manifest
+	return manifestDigest
+}
+
+//CorruptBlobs replaces the content of all stored blobs
+func (r *Registry) CorruptBlobs() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.blobs {
+		r.blobs[key] = []byte("corrupted")
+	}
+}
+
+func (r *Registry) handle(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		r.handleToken(w, req)
+		return
+	}
+	if !r.authorized(req) {
+		if r.bearer {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="test-registry"`, r.server.URL))
+		} else {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test-registry"`)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if idx := strings.LastIndex(path, "/manifests/"); idx > 0 {
+		r.mu.Lock()
+		manifest, ok := r.manifests[fmt.Sprintf("%s:%s", path[:idx], path[idx+len("/manifests/"):])]
+		r.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", manifestMediaType)
+		_, _ = w.Write(manifest)
+		return
+	}
+	if idx := strings.LastIndex(path, "/blobs/"); idx > 0 {
+		r.mu.Lock()
+		blob, ok := r.blobs[path[idx+len("/blobs/"):]]
+		r.blobRequests++
+		r.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(blob)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (r *Registry) handleToken(w http.ResponseWriter, req *http.Request) {
+	username, password, ok := req.BasicAuth()
+	if !ok || username != r.username || password != r.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"token": bearerToken})
+}
+
+func (r *Registry) authorized(req *http.Request) bool {
+	if r.username == "" {
+		return true
+	}
+	if r.bearer {
+		return req.Header.Get("Authorization") == "Bearer "+bearerToken
+	}
+	username, password, ok := req.BasicAuth()
+	return ok && username == r.username && password == r.password
+}
+
+func newTarGz(t *testing.T, files map[string]string) []byte {
+	var fileNames []string
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames) //ensure reproducible digests
+
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, fileName := range fileNames {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name: fileName,
+			Mode: 0600,
+			Size: int64(len(files[fileName])),
+		}))
+		_, err := tarWriter.Write([]byte(files[fileName]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
+
+func descriptor(mediaType string, content []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"mediaType": mediaType,
+		"digest":    digest(content),
+		"size":      len(content),
+	}
+}
+
+func digest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
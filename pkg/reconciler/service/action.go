@@ -17,6 +17,8 @@ type ActionContext struct {
 	Logger           *zap.SugaredLogger
 	Task             *reconciler.Task
 	ChartProvider    chart.Provider
+	//Install deploys manifests with the resource interceptors selected by the component reconciler
+	Install *Install
 }
 
 type Action interface {
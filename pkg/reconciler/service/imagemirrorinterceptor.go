@@ -0,0 +1,222 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	ImageMirrorInterceptorName = "imagemirror"
+	//ImageMirrorConfigPrefix is the prefix of configuration entries defining a mirror for a registry
+	//(e.g. 'global.imageMirror.eu.gcr.io=mirror.corp/eu-gcr')
+	ImageMirrorConfigPrefix = "global.imageMirror."
+	//ImagePullSecretsConfigKey is the configuration entry defining the pull-secrets added to all workloads
+	//(comma separated list of secret names)
+	ImagePullSecretsConfigKey = "global.imageMirrorPullSecrets"
+	defaultRegistry           = "docker.io"
+)
+
+//podSpecPaths defines for each supported kind the path to the pod specification
+var podSpecPaths = map[string][]string{
+	"pod":                   {"spec"},
+	"deployment":            {"spec", "template", "spec"},
+	"statefulset":           {"spec", "template", "spec"},
+	"daemonset":             {"spec", "template", "spec"},
+	"replicaset":            {"spec", "template", "spec"},
+	"replicationcontroller": {"spec", "template", "spec"},
+	"job":                   {"spec", "template", "spec"},
+	"cronjob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+//ImageMirrorInterceptor rewrites the images of all workloads to pull them from a mirror registry
+type ImageMirrorInterceptor struct {
+	mirrors     map[string]string //registry (optionally including a repository path) => mirror
+	pullSecrets []string
+}
+
+func newImageMirrorInterceptor(configuration map[string]interface{}) (*ImageMirrorInterceptor, error) {
+	interceptor := &ImageMirrorInterceptor{
+		mirrors: make(map[string]string),
+	}
+
+	for key, value := range configuration {
+		switch {
+		case strings.HasPrefix(key, ImageMirrorConfigPrefix):
+			mirror, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("value of image mirror configuration '%s' has to be a string but was '%T'",
+					key, value)
+			}
+			registry := strings.TrimSuffix(strings.TrimPrefix(key, ImageMirrorConfigPrefix), "/")
+			interceptor.mirrors[registry] = strings.TrimSuffix(strings.TrimSpace(mirror), "/")
+		case key == strings.TrimSuffix(ImageMirrorConfigPrefix, "."): //mirrors can also be provided as nested map
+			mirrors, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("value of image mirror configuration '%s' has to be a map but was '%T'",
+					key, value)
+			}
+			for registry, mirror := range mirrors {
+				interceptor.mirrors[strings.TrimSuffix(registry, "/")] = strings.TrimSuffix(fmt.Sprint(mirror), "/")
+			}
+		case key == ImagePullSecretsConfigKey:
+			pullSecrets, err := toStringList(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid image pull-secrets configuration '%s'", key)
+			}
+			interceptor.pullSecrets = pullSecrets
+		}
+	}
+
+	for registry, mirror := range interceptor.mirrors {
+		if registry == "" || mirror == "" {
+			return nil, fmt.Errorf("image mirror configuration is invalid: registry ('%s') "+
+				"and mirror ('%s') cannot be empty", registry, mirror)
+		}
+	}
+
+	return interceptor, nil
+}
+
+func toStringList(value interface{}) ([]string, error) {
+	var result []string
+	switch v := value.(type) {
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, fmt.Sprint(item))
+		}
+	case []string:
+		result = append(result, v...)
+	default:
+		return nil, fmt.Errorf("string or list expected but got '%T'", value)
+	}
+	return result, nil
+}
+
+func (i *ImageMirrorInterceptor) configured() bool {
+	return len(i.mirrors) > 0 || len(i.pullSecrets) > 0
+}
+
+func (i *ImageMirrorInterceptor) Intercept(resources *kubernetes.ResourceCacheList, _ string) error {
+	kinds := make([]string, 0, len(podSpecPaths))
+	for kind := range podSpecPaths {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		podSpecPath := podSpecPaths[kind]
+		err := resources.VisitByKind(kind, func(u *unstructured.Unstructured) error {
+			return i.interceptPodSpec(u, podSpecPath)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *ImageMirrorInterceptor) interceptPodSpec(u *unstructured.Unstructured, podSpecPath []string) error {
+	podSpec, found, err := unstructured.NestedMap(u.Object, podSpecPath...)
+	if err != nil {
+		return errors.Wrapf(err, "image mirror interceptor failed to read pod spec of %s '%s'",
+			u.GetKind(), u.GetName())
+	}
+	if !found {
+		return nil
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(podSpec, field)
+		if err != nil {
+			return errors.Wrapf(err, "image mirror interceptor failed to read %s of %s '%s'",
+				field, u.GetKind(), u.GetName())
+		}
+		if !found {
+			continue
+		}
+		for idx := range containers {
+			container, ok := containers[idx].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, ok := container["image"].(string)
+			if !ok {
+				continue
+			}
+			container["image"] = i.mirrorImage(image)
+		}
+		if err := unstructured.SetNestedSlice(podSpec, containers, field); err != nil {
+			return err
+		}
+	}
+
+	if len(i.pullSecrets) > 0 {
+		if err := i.addPullSecrets(podSpec); err != nil {
+			return errors.Wrapf(err, "image mirror interceptor failed to add image pull-secrets to %s '%s'",
+				u.GetKind(), u.GetName())
+		}
+	}
+
+	return unstructured.SetNestedMap(u.Object, podSpec, podSpecPath...)
+}
+
+func (i *ImageMirrorInterceptor) addPullSecrets(podSpec map[string]interface{}) error {
+	pullSecrets, _, err := unstructured.NestedSlice(podSpec, "imagePullSecrets")
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for _, pullSecret := range pullSecrets {
+		if pullSecretMap, ok := pullSecret.(map[string]interface{}); ok {
+			existing[fmt.Sprint(pullSecretMap["name"])] = true
+		}
+	}
+	for _, pullSecret := range i.pullSecrets {
+		if !existing[pullSecret] {
+			pullSecrets = append(pullSecrets, map[string]interface{}{"name": pullSecret})
+		}
+	}
+
+	return unstructured.SetNestedSlice(podSpec, pullSecrets, "imagePullSecrets")
+}
+
+//mirrorImage returns the image reference pointing to the mirror registry. The most specific
+//matching mirror is used. If no mirror matches, the image is returned unchanged.
+func (i *ImageMirrorInterceptor) mirrorImage(image string) string {
+	qualifiedImage := qualifyImage(image)
+
+	var bestMatch string
+	for registry := range i.mirrors {
+		if (qualifiedImage == registry || strings.HasPrefix(qualifiedImage, registry+"/")) && len(registry) > len(bestMatch) {
+			bestMatch = registry
+		}
+	}
+	if bestMatch == "" {
+		return image
+	}
+	return i.mirrors[bestMatch] + strings.TrimPrefix(qualifiedImage, bestMatch)
+}
+
+//qualifyImage adds the default registry to images which are referenced without a registry (e.g. 'nginx:1.21')
+func qualifyImage(image string) string {
+	tokens := strings.SplitN(image, "/", 2)
+	if len(tokens) == 1 {
+		return fmt.Sprintf("%s/library/%s", defaultRegistry, image)
+	}
+	if strings.ContainsAny(tokens[0], ".:") || tokens[0] == "localhost" {
+		return image
+	}
+	return fmt.Sprintf("%s/%s", defaultRegistry, image)
+}
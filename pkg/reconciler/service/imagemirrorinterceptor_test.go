@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestImageMirrorInterceptor(t *testing.T) {
+	interceptor, err := newImageMirrorInterceptor(map[string]interface{}{
+		"global.imageMirror.eu.gcr.io":               "mirror.corp/eu-gcr",
+		"global.imageMirror.eu.gcr.io/kyma-project/": "mirror.corp/kyma/",
+		"global.imageMirror.docker.io":               "mirror.corp/hub",
+		"global.imageMirrorPullSecrets":              "mirror-secret, other-secret",
+		"global.domainName":                          "example.com",
+	})
+	require.NoError(t, err)
+	require.True(t, interceptor.configured())
+
+	t.Run("Mirror images", func(t *testing.T) {
+		testCases := map[string]string{
+			"eu.gcr.io/kyma-project/foo:1.0":   "mirror.corp/kyma/foo:1.0",
+			"eu.gcr.io/other/bar@sha256:123":   "mirror.corp/eu-gcr/other/bar@sha256:123",
+			"nginx:1.21":                       "mirror.corp/hub/library/nginx:1.21",
+			"bitnami/redis":                    "mirror.corp/hub/bitnami/redis",
+			"eu.gcr.io.fake.com/foo:1.0":       "eu.gcr.io.fake.com/foo:1.0",
+			"localhost:5000/kyma-project/test": "localhost:5000/kyma-project/test",
+		}
+		for image, expected := range testCases {
+			require.Equal(t, expected, interceptor.mirrorImage(image), image)
+		}
+	})
+
+	t.Run("Intercept workloads", func(t *testing.T) {
+		deployment := newUnstructured(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "deployment"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"initContainers": []interface{}{
+							map[string]interface{}{"name": "init", "image": "busybox"},
+						},
+						"containers": []interface{}{
+							map[string]interface{}{"name": "app", "image": "eu.gcr.io/kyma-project/app:1.0"},
+						},
+						"imagePullSecrets": []interface{}{
+							map[string]interface{}{"name": "mirror-secret"},
+						},
+					},
+				},
+			},
+		})
+		cronJob := newUnstructured(map[string]interface{}{
+			"apiVersion": "batch/v1beta1",
+			"kind":       "CronJob",
+			"metadata":   map[string]interface{}{"name": "cronjob"},
+			"spec": map[string]interface{}{
+				"jobTemplate": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{"name": "job", "image": "eu.gcr.io/other/job:2.0"},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		configMap := newUnstructured(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "configmap"},
+			"data":       map[string]interface{}{"image": "eu.gcr.io/kyma-project/app:1.0"},
+		})
+
+		resources := kubernetes.NewResourceList([]*unstructured.Unstructured{deployment, cronJob, configMap})
+		require.NoError(t, interceptor.Intercept(resources, "default"))
+
+		podSpec, _, err := unstructured.NestedMap(deployment.Object, "spec", "template", "spec")
+		require.NoError(t, err)
+		require.Equal(t, "mirror.corp/hub/library/busybox", podSpec["initContainers"].([]interface{})[0].(map[string]interface{})["image"])
+		require.Equal(t, "mirror.corp/kyma/app:1.0", podSpec["containers"].([]interface{})[0].(map[string]interface{})["image"])
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"name": "mirror-secret"},
+			map[string]interface{}{"name": "other-secret"},
+		}, podSpec["imagePullSecrets"])
+
+		podSpec, _, err = unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate", "spec", "template", "spec")
+		require.NoError(t, err)
+		require.Equal(t, "mirror.corp/eu-gcr/other/job:2.0", podSpec["containers"].([]interface{})[0].(map[string]interface{})["image"])
+
+		data, _, err := unstructured.NestedStringMap(configMap.Object, "data")
+		require.NoError(t, err)
+		require.Equal(t, "eu.gcr.io/kyma-project/app:1.0", data["image"])
+	})
+
+	t.Run("Nested mirror configuration", func(t *testing.T) {
+		interceptor, err := newImageMirrorInterceptor(map[string]interface{}{
+			"global.imageMirror": map[string]interface{}{
+				"quay.io": "mirror.corp/quay",
+			},
+			"global.imageMirrorPullSecrets": []interface{}{"secret"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "mirror.corp/quay/foo/bar", interceptor.mirrorImage("quay.io/foo/bar"))
+		require.Equal(t, []string{"secret"}, interceptor.pullSecrets)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		_, err := newImageMirrorInterceptor(map[string]interface{}{
+			"global.imageMirror.quay.io": 123,
+		})
+		require.Error(t, err)
+
+		_, err = newImageMirrorInterceptor(map[string]interface{}{
+			"global.imageMirror.quay.io": "",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Unconfigured interceptor", func(t *testing.T) {
+		interceptor, err := newImageMirrorInterceptor(map[string]interface{}{
+			"global.domainName": "example.com",
+		})
+		require.NoError(t, err)
+		require.False(t, interceptor.configured())
+	})
+}
+
+func newUnstructured(obj map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: obj}
+}
@@ -13,13 +13,40 @@ import (
 )
 
 type Install struct {
-	logger *zap.SugaredLogger
+	logger       *zap.SugaredLogger
+	interceptors interceptorSelection
 }
 
 func NewInstall(logger *zap.SugaredLogger) *Install {
 	return &Install{logger: logger}
 }
 
+func newInstall(logger *zap.SugaredLogger, interceptors interceptorSelection) *Install {
+	return &Install{logger: logger, interceptors: interceptors}
+}
+
+//WithInterceptors enables the given resource interceptors in addition to the interceptors enabled by default
+func (r *Install) WithInterceptors(interceptorNames ...string) *Install {
+	return r.withSelection(true, interceptorNames)
+}
+
+//WithoutInterceptors disables the given resource interceptors (also if they are enabled by default)
+func (r *Install) WithoutInterceptors(interceptorNames ...string) *Install {
+	return r.withSelection(false, interceptorNames)
+}
+
+//withSelection returns a copy of the install operation which uses the updated interceptor selection
+func (r *Install) withSelection(enabled bool, interceptorNames []string) *Install {
+	selection := make(interceptorSelection, len(r.interceptors)+len(interceptorNames))
+	for name, enabled := range r.interceptors {
+		selection[name] = enabled
+	}
+	for _, name := range interceptorNames {
+		selection[name] = enabled
+	}
+	return newInstall(r.logger, selection)
+}
+
 //go:generate mockery --name=Operation --output=mocks --outpkg=mocks --case=underscore
 type Operation interface {
 	Invoke(ctx context.Context, chartProvider chart.Provider, model *reconciler.Task, kubeClient kubernetes.Client) error
@@ -49,16 +76,15 @@ func (r *Install) Invoke(ctx context.Context, chartProvider chart.Provider, task
 		if task.Component == model.CleanupComponent {
 			return nil
 		}
-		resources, err := kubeClient.Deploy(ctx, manifest, task.Namespace,
-			&LabelsInterceptor{
-				Version: task.Version,
-			},
-			&AnnotationsInterceptor{},
-			&ServicesInterceptor{
-				kubeClient: kubeClient,
-			},
-			newClusterWideResourceInterceptor(),
-		)
+		interceptors, err := newInterceptors(r.interceptors, &InterceptorContext{
+			Task:       task,
+			KubeClient: kubeClient,
+			Logger:     r.logger,
+		})
+		if err != nil {
+			return err
+		}
+		resources, err := kubeClient.Deploy(ctx, manifest, task.Namespace, interceptors...)
 		if err == nil {
 			r.logger.Debugf("Deployment of manifest finished successfully: %d resources deployed", len(resources))
 		} else {
@@ -72,11 +98,24 @@ func (r *Install) Invoke(ctx context.Context, chartProvider chart.Provider, task
 func (r *Install) renderManifest(chartProvider chart.Provider, model *reconciler.Task) (string, error) {
 	component := chart.NewComponentBuilder(model.Version, model.Component).
 		WithProfile(model.Profile).
+		WithProfileValues(model.ProfileValues).
+		WithLandscape(model.Landscape).
 		WithNamespace(model.Namespace).
 		WithConfiguration(model.Configuration).
 		WithURL(model.URL).
 		Build()
 
+	//validate configuration of component before rendering (errors in templates would be less expressive)
+	if err := chartProvider.Validate(component); err != nil {
+		if chart.IsValuesValidationError(err) {
+			r.logger.Errorf("Configuration of component '%s' in Kyma version '%s' is invalid: %s",
+				model.Component, model.Version, err)
+			return "", err
+		}
+		r.logger.Warnf("Failed to validate configuration of component '%s' in Kyma version '%s': %s",
+			model.Component, model.Version, err)
+	}
+
 	//get manifest of component
 	chartManifest, err := chartProvider.RenderManifest(component)
 	if err != nil {
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"go.uber.org/zap"
+)
+
+const (
+	LabelsInterceptorName              = "labels"
+	AnnotationsInterceptorName         = "annotations"
+	ServicesInterceptorName            = "services"
+	ClusterWideResourceInterceptorName = "clusterwideresources"
+	HPAInterceptorName                 = "hpa"
+	PVCInterceptorName                 = "pvc"
+)
+
+//InterceptorContext contains the information an InterceptorFactory can use to create a resource interceptor
+type InterceptorContext struct {
+	Task       *reconciler.Task
+	KubeClient kubernetes.Client
+	Logger     *zap.SugaredLogger
+}
+
+//InterceptorFactory creates a new resource interceptor for the given task.
+//A nil interceptor can be returned if the interceptor has nothing to do for this task.
+type InterceptorFactory func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error)
+
+type registeredInterceptor struct {
+	name             string
+	factory          InterceptorFactory
+	enabledByDefault bool
+}
+
+var (
+	interceptors  []*registeredInterceptor //ordered by registration: interceptors are applied in this order
+	interceptorMu sync.RWMutex
+)
+
+func init() {
+	mustRegisterInterceptor(LabelsInterceptorName, true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return &LabelsInterceptor{Version: ctx.Task.Version}, nil
+	})
+	mustRegisterInterceptor(AnnotationsInterceptorName, true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return &AnnotationsInterceptor{}, nil
+	})
+	mustRegisterInterceptor(ServicesInterceptorName, true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return &ServicesInterceptor{kubeClient: ctx.KubeClient}, nil
+	})
+	mustRegisterInterceptor(ClusterWideResourceInterceptorName, true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return newClusterWideResourceInterceptor(), nil
+	})
+	mustRegisterInterceptor(ImageMirrorInterceptorName, true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		interceptor, err := newImageMirrorInterceptor(ctx.Task.Configuration)
+		if err != nil || !interceptor.configured() {
+			return nil, err
+		}
+		return interceptor, nil
+	})
+	mustRegisterInterceptor(HPAInterceptorName, false, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return &HPAInterceptor{kubeClient: ctx.KubeClient, logger: ctx.Logger}, nil
+	})
+	mustRegisterInterceptor(PVCInterceptorName, false, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return &PVCInterceptor{kubeClient: ctx.KubeClient, logger: ctx.Logger}, nil
+	})
+}
+
+//RegisterInterceptor adds a resource interceptor to the interceptor registry. Interceptors which are enabled by
+//default are used by all component reconcilers which didn't opt out, all others have to be enabled explicitly.
+func RegisterInterceptor(name string, enabledByDefault bool, factory InterceptorFactory) error {
+	if name == "" {
+		return fmt.Errorf("interceptor name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("interceptor factory of interceptor '%s' cannot be nil", name)
+	}
+
+	interceptorMu.Lock()
+	defer interceptorMu.Unlock()
+
+	for _, interceptor := range interceptors {
+		if interceptor.name == name {
+			return fmt.Errorf("interceptor '%s' is already registered", name)
+		}
+	}
+	interceptors = append(interceptors, &registeredInterceptor{
+		name:             name,
+		factory:          factory,
+		enabledByDefault: enabledByDefault,
+	})
+	return nil
+}
+
+func mustRegisterInterceptor(name string, enabledByDefault bool, factory InterceptorFactory) {
+	if err := RegisterInterceptor(name, enabledByDefault, factory); err != nil {
+		panic(err)
+	}
+}
+
+//RegisteredInterceptors returns the names of all registered interceptors
+func RegisteredInterceptors() []string {
+	interceptorMu.RLock()
+	defer interceptorMu.RUnlock()
+
+	var names []string
+	for _, interceptor := range interceptors {
+		names = append(names, interceptor.name)
+	}
+	return names
+}
+
+//interceptorSelection defines which interceptors were explicitly enabled (true) or disabled (false)
+//by a component reconciler. Interceptors which are not part of the selection use their default.
+type interceptorSelection map[string]bool
+
+//validate ensures that all selected interceptors are registered
+func (s interceptorSelection) validate() error {
+	registered := make(map[string]bool)
+	for _, name := range RegisteredInterceptors() {
+		registered[name] = true
+	}
+	for name := range s {
+		if !registered[name] {
+			return fmt.Errorf("interceptor '%s' not found in interceptor registry", name)
+		}
+	}
+	return nil
+}
+
+func (s interceptorSelection) enabled(interceptor *registeredInterceptor) bool {
+	if enabled, ok := s[interceptor.name]; ok {
+		return enabled
+	}
+	return interceptor.enabledByDefault
+}
+
+//newInterceptors creates the interceptors which are enabled by the selection in their registration order
+func newInterceptors(selection interceptorSelection, ctx *InterceptorContext) ([]kubernetes.ResourceInterceptor, error) {
+	if err := selection.validate(); err != nil {
+		return nil, err
+	}
+
+	interceptorMu.RLock()
+	defer interceptorMu.RUnlock()
+
+	var result []kubernetes.ResourceInterceptor
+	for _, interceptor := range interceptors {
+		if !selection.enabled(interceptor) {
+			continue
+		}
+		resInterceptor, err := interceptor.factory(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create interceptor '%s': %s", interceptor.name, err)
+		}
+		if resInterceptor != nil {
+			result = append(result, resInterceptor)
+		}
+	}
+	return result, nil
+}
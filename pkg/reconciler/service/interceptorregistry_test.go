@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyInterceptor struct {
+	version string
+}
+
+func (i *dummyInterceptor) Intercept(_ *kubernetes.ResourceCacheList, _ string) error {
+	return nil
+}
+
+func TestInterceptorRegistry(t *testing.T) {
+	require.NoError(t, RegisterInterceptor("unittest-optin", false, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return &dummyInterceptor{version: ctx.Task.Version}, nil
+	}))
+	require.NoError(t, RegisterInterceptor("unittest-nil", true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return nil, nil
+	}))
+	require.NoError(t, RegisterInterceptor("unittest-failing", false, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+		return nil, fmt.Errorf("I failed")
+	}))
+
+	ctx := &InterceptorContext{
+		Task:   &reconciler.Task{Version: "1.2.3"},
+		Logger: logger.NewLogger(true),
+	}
+
+	t.Run("Register interceptor twice", func(t *testing.T) {
+		require.Error(t, RegisterInterceptor(LabelsInterceptorName, true, func(ctx *InterceptorContext) (kubernetes.ResourceInterceptor, error) {
+			return nil, nil
+		}))
+		require.Error(t, RegisterInterceptor("", true, nil))
+	})
+
+	t.Run("Default interceptors", func(t *testing.T) {
+		interceptors, err := newInterceptors(interceptorSelection{}, ctx)
+		require.NoError(t, err)
+		require.Len(t, interceptors, 4)
+		require.IsType(t, &LabelsInterceptor{}, interceptors[0])
+		require.IsType(t, &AnnotationsInterceptor{}, interceptors[1])
+		require.IsType(t, &ServicesInterceptor{}, interceptors[2])
+		require.IsType(t, &ClusterWideResourceInterceptor{}, interceptors[3])
+	})
+
+	t.Run("Opt-in and opt-out of interceptors", func(t *testing.T) {
+		recon, err := NewComponentReconciler("unittest-interceptors")
+		require.NoError(t, err)
+		recon.WithInterceptors(HPAInterceptorName, "unittest-optin").
+			WithoutInterceptors(AnnotationsInterceptorName, ServicesInterceptorName)
+		require.NoError(t, recon.validate())
+
+		interceptors, err := newInterceptors(recon.interceptors, ctx)
+		require.NoError(t, err)
+		require.Len(t, interceptors, 4)
+		require.IsType(t, &LabelsInterceptor{}, interceptors[0])
+		require.IsType(t, &ClusterWideResourceInterceptor{}, interceptors[1])
+		require.IsType(t, &HPAInterceptor{}, interceptors[2])
+		require.Equal(t, &dummyInterceptor{version: "1.2.3"}, interceptors[3])
+	})
+
+	t.Run("Unknown interceptor", func(t *testing.T) {
+		recon, err := NewComponentReconciler("unittest-interceptors")
+		require.NoError(t, err)
+		recon.WithInterceptors("i-dont-exist")
+		require.Error(t, recon.validate())
+
+		_, err = newInterceptors(recon.interceptors, ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("Failing interceptor factory", func(t *testing.T) {
+		_, err := newInterceptors(interceptorSelection{"unittest-failing": true}, ctx)
+		require.Error(t, err)
+	})
+}
@@ -6,9 +6,9 @@ import (
 )
 
 const (
-	ManagedByLabel       = "reconciler.kyma-project.io/managed-by"
+	ManagedByLabel       = kubernetes.ManagedByLabel
 	KymaVersionLabel     = "reconciler.kyma-project.io/origin-version"
-	LabelReconcilerValue = "reconciler"
+	LabelReconcilerValue = kubernetes.ManagedByLabelValue
 )
 
 type LabelsInterceptor struct {
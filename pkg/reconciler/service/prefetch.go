@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+)
+
+var prefetching sync.Map //versions which are currently prefetched
+
+//Prefetch prepares the workspace of a Kyma version in the background: the workspace is downloaded and validated
+//before the first reconciliation of this version is started. The sources are retrieved from the given repository
+//(or the default Kyma repository if undefined). Prefetches of a version which is already prefetched are ignored.
+//The returned channel is closed when the prefetch is finished.
+func (r *ComponentReconciler) Prefetch(version string, repo *reconciler.Repository) (<-chan struct{}, error) {
+	if version == "" || version == chart.VersionLocal {
+		return nil, fmt.Errorf("workspace of version '%s' cannot be prefetched", version)
+	}
+
+	done := make(chan struct{})
+	if inProgress, loaded := prefetching.LoadOrStore(version, done); loaded {
+		r.logger.Debugf("Workspace of version '%s' is already being prefetched", version)
+		return inProgress.(chan struct{}), nil
+	}
+
+	go func() {
+		defer close(done)
+		defer prefetching.Delete(version)
+
+		start := time.Now()
+		if err := r.prefetch(version, repo); err != nil {
+			r.logger.Warnf("Failed to prefetch workspace of version '%s': %s", version, err)
+			return
+		}
+		r.logger.Infof("Workspace of version '%s' prefetched in %.1f secs", version, time.Since(start).Seconds())
+	}()
+	return done, nil
+}
+
+func (r *ComponentReconciler) prefetch(version string, repo *reconciler.Repository) error {
+	wsFactory, err := r.workspaceFactory(repo)
+	if err != nil {
+		return err
+	}
+	ws, err := (*wsFactory).Get(version)
+	if err != nil {
+		return err
+	}
+	ws.Release()
+	return nil
+}
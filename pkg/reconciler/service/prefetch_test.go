@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefetch(t *testing.T) {
+	recon, err := NewComponentReconciler("prefetch-unittest")
+	require.NoError(t, err)
+
+	t.Run("Prefetch workspace in background", func(t *testing.T) {
+		proceed := make(chan time.Time)
+		wsFactory := &mocks.Factory{}
+		wsFactory.On("Get", "1.0.0").WaitUntil(proceed).Return(&chart.KymaWorkspace{}, nil).Once()
+		require.NoError(t, RefreshGlobalWorkspaceFactory(wsFactory))
+
+		done1, err := recon.Prefetch("1.0.0", nil)
+		require.NoError(t, err)
+		done2, err := recon.Prefetch("1.0.0", nil) //already in progress
+		require.NoError(t, err)
+		require.Equal(t, done1, done2)
+
+		close(proceed)
+		<-done1
+		wsFactory.AssertExpectations(t)
+	})
+
+	t.Run("Reject invalid versions", func(t *testing.T) {
+		_, err := recon.Prefetch("", nil)
+		require.Error(t, err)
+		_, err = recon.Prefetch(chart.VersionLocal, nil)
+		require.Error(t, err)
+	})
+}
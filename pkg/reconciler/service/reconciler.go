@@ -28,6 +28,12 @@ var (
 
 type ComponentReconciler struct {
 	workspace             string
+	workspaceCacheQuota   chart.CacheQuota
+	renderCacheConfig     chart.RenderCacheConfig
+	renderCache           *chart.RenderCache
+	renderCacheOnce       sync.Once
+	strictValidation      bool
+	trustedArchiveKeys    []string
 	heartbeatSenderConfig heartbeatSenderConfig
 	progressTrackerConfig progressTrackerConfig
 	//reconcile actions:
@@ -38,6 +44,10 @@ type ComponentReconciler struct {
 	preDeleteAction  Action
 	deleteAction     Action
 	postDeleteAction Action
+	//resource interceptors:
+	interceptors interceptorSelection
+	//deletion:
+	removeKymaFinalizers bool
 	//retry:
 	retryDelay time.Duration
 	//worker pool:
@@ -60,8 +70,9 @@ type progressTrackerConfig struct {
 
 func NewComponentReconciler(reconcilerName string) (*ComponentReconciler, error) {
 	recon := &ComponentReconciler{
-		workspace: defaultWorkspace,
-		logger:    logger.NewLogger(false),
+		workspace:    defaultWorkspace,
+		logger:       logger.NewLogger(false),
+		interceptors: make(interceptorSelection),
 	}
 
 	RegisterReconciler(reconcilerName, recon) //add reconciler to registry
@@ -94,7 +105,28 @@ func (r *ComponentReconciler) newChartProvider(repo *reconciler.Repository) (*ch
 	if err != nil {
 		return nil, err
 	}
-	return chart.NewDefaultProvider(*wsFact, r.logger)
+	provider, err := chart.NewDefaultProvider(*wsFact, r.logger)
+	if err != nil {
+		return nil, err
+	}
+	return provider.
+		WithStrictValidation(r.strictValidation).
+		WithRenderCache(r.getRenderCache()), nil
+}
+
+//getRenderCache returns the render cache shared by all chart providers of the reconciler (nil if disabled)
+func (r *ComponentReconciler) getRenderCache() *chart.RenderCache {
+	r.renderCacheOnce.Do(func() {
+		if r.renderCacheConfig.MaxBytes <= 0 {
+			return
+		}
+		var err error
+		r.renderCache, err = chart.NewRenderCache(r.renderCacheConfig, r.logger)
+		if err != nil {
+			r.logger.Warnf("Failed to create render cache: manifests will be rendered without caching: %s", err)
+		}
+	})
+	return r.renderCache
 }
 
 func (r *ComponentReconciler) workspaceFactory(repo *reconciler.Repository) (*chart.Factory, error) {
@@ -104,7 +136,13 @@ func (r *ComponentReconciler) workspaceFactory(repo *reconciler.Repository) (*ch
 	var err error
 	if wsFactory == nil {
 		r.logger.Debugf("Creating new workspace factory using storage directory '%s'", r.workspace)
-		wsFactory, err = chart.NewFactory(repo, r.workspace, r.logger)
+		var defaultFactory *chart.DefaultFactory
+		defaultFactory, err = chart.NewFactory(repo, r.workspace, r.logger)
+		if err == nil {
+			wsFactory = defaultFactory.
+				WithCacheQuota(r.workspaceCacheQuota).
+				WithTrustedKeys(r.trustedArchiveKeys)
+		}
 	}
 
 	return &wsFactory, err
@@ -160,7 +198,7 @@ func (r *ComponentReconciler) validate() error {
 	if r.timeout == 0 {
 		r.timeout = defaultTimeout
 	}
-	return nil
+	return r.interceptors.validate()
 }
 
 func (r *ComponentReconciler) Debug() *ComponentReconciler {
@@ -174,6 +212,38 @@ func (r *ComponentReconciler) WithWorkspace(workspace string) *ComponentReconcil
 	return r
 }
 
+//WithWorkspaceCacheQuota limits the disk usage of cached workspaces (0 = unlimited)
+func (r *ComponentReconciler) WithWorkspaceCacheQuota(maxBytes int64, maxWorkspaces int) *ComponentReconciler {
+	r.workspaceCacheQuota = chart.CacheQuota{
+		MaxBytes:      maxBytes,
+		MaxWorkspaces: maxWorkspaces,
+	}
+	return r
+}
+
+//WithRenderCache caches rendered manifests in memory up to the given size in bytes (0 = disabled). If a directory
+//is defined, the rendered manifests are also stored on disk up to dirMaxBytes and survive restarts.
+func (r *ComponentReconciler) WithRenderCache(maxBytes int64, dir string, dirMaxBytes int64) *ComponentReconciler {
+	r.renderCacheConfig = chart.RenderCacheConfig{
+		MaxBytes:    maxBytes,
+		Dir:         dir,
+		DirMaxBytes: dirMaxBytes,
+	}
+	return r
+}
+
+//WithStrictValuesValidation rejects configuration keys which are unknown to the chart of the component
+func (r *ComponentReconciler) WithStrictValuesValidation(strict bool) *ComponentReconciler {
+	r.strictValidation = strict
+	return r
+}
+
+//WithTrustedArchiveKeys requires downloaded component archives to be signed by one of the given public keys
+func (r *ComponentReconciler) WithTrustedArchiveKeys(keys []string) *ComponentReconciler {
+	r.trustedArchiveKeys = keys
+	return r
+}
+
 func (r *ComponentReconciler) WithRetryDelay(retryDelay time.Duration) *ComponentReconciler {
 	r.retryDelay = retryDelay
 	return r
@@ -215,6 +285,28 @@ func (r *ComponentReconciler) WithPostDeleteAction(postDeleteAction Action) *Com
 	return r
 }
 
+//WithInterceptors enables the given resource interceptors in addition to the interceptors enabled by default
+func (r *ComponentReconciler) WithInterceptors(interceptorNames ...string) *ComponentReconciler {
+	for _, interceptorName := range interceptorNames {
+		r.interceptors[interceptorName] = true
+	}
+	return r
+}
+
+//WithoutInterceptors disables the given resource interceptors (also if they are enabled by default)
+func (r *ComponentReconciler) WithoutInterceptors(interceptorNames ...string) *ComponentReconciler {
+	for _, interceptorName := range interceptorNames {
+		r.interceptors[interceptorName] = false
+	}
+	return r
+}
+
+//WithKymaFinalizerRemoval enables the removal of finalizers which block the deletion of custom resources deployed by the reconciler
+func (r *ComponentReconciler) WithKymaFinalizerRemoval() *ComponentReconciler {
+	r.removeKymaFinalizers = true
+	return r
+}
+
 func (r *ComponentReconciler) WithHeartbeatSenderConfig(interval, timeout time.Duration) *ComponentReconciler {
 	r.heartbeatSenderConfig.interval = interval
 	r.heartbeatSenderConfig.timeout = timeout
@@ -266,6 +358,6 @@ func (r *ComponentReconciler) newRunnerFunc(ctx context.Context, model *reconcil
 	return func() error {
 		timeoutCtx, cancel := context.WithTimeout(ctx, r.timeout)
 		defer cancel()
-		return (&runner{r, NewInstall(logger), logger}).Run(timeoutCtx, model, callback)
+		return (&runner{r, newInstall(logger, r.interceptors), logger}).Run(timeoutCtx, model, callback)
 	}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/callback"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/heartbeat"
 	"github.com/pkg/errors"
 )
@@ -46,6 +47,12 @@ func (r *runner) Run(ctx context.Context, task *reconciler.Task, callback callba
 			if heartbeatErr := heartbeatSender.Failed(err, retryID); heartbeatErr != nil {
 				err = errors.Wrap(err, heartbeatErr.Error())
 			}
+			if chart.IsVerificationError(err) { //retrying won't fix a manipulated or corrupted archive
+				return retry.Unrecoverable(err)
+			}
+			if chart.IsValuesValidationError(err) { //retrying won't fix an invalid configuration
+				return retry.Unrecoverable(err)
+			}
 		}
 		return err
 	}
@@ -82,8 +89,9 @@ func (r *runner) Run(ctx context.Context, task *reconciler.Task, callback callba
 
 func (r *runner) reconcile(ctx context.Context, task *reconciler.Task) error {
 	kubeClient, err := k8s.NewKubernetesClient(task.Kubeconfig, r.logger, &k8s.Config{
-		ProgressInterval: r.progressTrackerConfig.interval,
-		ProgressTimeout:  r.progressTrackerConfig.timeout,
+		ProgressInterval:     r.progressTrackerConfig.interval,
+		ProgressTimeout:      r.progressTrackerConfig.timeout,
+		RemoveKymaFinalizers: r.removeKymaFinalizers,
 	})
 	if err != nil {
 		return err
@@ -106,6 +114,7 @@ func (r *runner) reconcile(ctx context.Context, task *reconciler.Task) error {
 		Logger:           r.logger,
 		ChartProvider:    chartProvider,
 		Task:             task,
+		Install:          r.install,
 	}
 
 	// Identify the right action set to use (reconcile/delete)
@@ -22,7 +22,8 @@ type record struct {
 
 type invalidate struct {
 	*cacheDependencyManager
-	selector map[string]interface{}
+	selector  map[string]interface{}
+	reconcile bool
 }
 
 type get struct {
@@ -73,8 +74,8 @@ func (r *record) Exec(conn db.Connection) error {
 
 func (cdm *cacheDependencyManager) Invalidate() *invalidate {
 	return &invalidate{
-		cdm,
-		make(map[string]interface{}),
+		cacheDependencyManager: cdm,
+		selector:               make(map[string]interface{}),
 	}
 }
 
@@ -98,6 +99,13 @@ func (i *invalidate) WithCacheID(cacheID int64) *invalidate {
 	return i.with("CacheID", cacheID)
 }
 
+//WithReconciliation marks the clusters of the invalidated cache entries as changed: the scheduler
+//will reconcile them
+func (i *invalidate) WithReconciliation() *invalidate {
+	i.reconcile = true
+	return i
+}
+
 func (i *invalidate) with(colName string, colValue interface{}) *invalidate {
 	i.selector[colName] = colValue
 	return i
@@ -151,12 +159,37 @@ func (i *invalidate) Exec(conn db.Connection) error {
 		}
 		i.logger.Debugf("Deleted %d cache dependencies matching selector '%v'", deletedDeps, i.selector)
 
+		if i.reconcile {
+			return i.recordConfigChanges(tx, deps)
+		}
 		return nil
 	}
 
 	return db.Transaction(conn, dbOps, i.logger)
 }
 
+//recordConfigChanges stores a configuration change for each cluster which was using an invalidated cache entry
+func (i *invalidate) recordConfigChanges(tx *db.TxConnection, deps []db.DatabaseEntity) error {
+	runtimeIDs := make(map[string]interface{}, len(deps))
+	for _, dep := range deps {
+		depEntity := dep.(*model.CacheDependencyEntity)
+		if _, ok := runtimeIDs[depEntity.RuntimeID]; ok {
+			continue
+		}
+		runtimeIDs[depEntity.RuntimeID] = nil
+
+		q, err := db.NewQuery(tx, &model.ConfigChangeEntity{RuntimeID: depEntity.RuntimeID}, i.logger)
+		if err != nil {
+			return err
+		}
+		if err := q.Insert().Exec(); err != nil {
+			return err
+		}
+	}
+	i.logger.Debugf("Recorded configuration changes for %d clusters matching selector '%v'", len(runtimeIDs), i.selector)
+	return nil
+}
+
 func (i *invalidate) cacheIDsCSV(deps []db.DatabaseEntity) (string, int) {
 	deduplicate := make(map[int64]interface{}, len(deps))
 	var buffer bytes.Buffer
@@ -119,6 +119,34 @@ func TestCacheDependencyManager(t *testing.T) {
 		}, dbConn)
 	})
 
+	t.Run("Invalidate dependencies with reconciliation", func(t *testing.T) {
+		dbConn := db.NewTestConnection(t)
+		withTestData(t, func(t *testing.T, testEntries []*model.CacheEntryEntity, testDeps []*model.CacheDependencyEntity) {
+			q, err := db.NewQuery(dbConn, &model.ConfigChangeEntity{}, cacheDep.logger)
+			require.NoError(t, err)
+			defer func() {
+				_, err := q.Delete().Where(map[string]interface{}{}).Exec()
+				require.NoError(t, err)
+			}()
+
+			//invalidation without reconciliation doesn't record any configuration change
+			require.NoError(t, cacheDep.Invalidate().WithBucket("bucket3").Exec(dbConn))
+			changes, err := q.Select().GetMany()
+			require.NoError(t, err)
+			require.Empty(t, changes)
+
+			//key 'key1' is used by both clusters
+			require.NoError(t, cacheDep.Invalidate().WithKey("key1").WithReconciliation().Exec(dbConn))
+			changes, err = q.Select().GetMany()
+			require.NoError(t, err)
+			var runtimeIDs []string
+			for _, change := range changes {
+				runtimeIDs = append(runtimeIDs, change.(*model.ConfigChangeEntity).RuntimeID)
+			}
+			require.ElementsMatch(t, []string{"testCluster1", "testCluster2"}, runtimeIDs)
+		}, dbConn)
+	})
+
 	t.Run("Get dependencies", func(t *testing.T) {
 		dbConn := db.NewTestConnection(t)
 		withTestData(t, func(t *testing.T, testEntries []*model.CacheEntryEntity, testDeps []*model.CacheDependencyEntity) {
@@ -0,0 +1,61 @@
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+//Archive stores the exported rows before they get deleted
+type Archive interface {
+	Store(name string, data []byte) error
+}
+
+func NewArchive(config *ArchiveConfig) (Archive, error) {
+	switch config.Type {
+	case ArchiveTypeFile:
+		return NewFileArchive(config.Dir), nil
+	case ArchiveTypeS3:
+		return NewS3Archive(&config.S3)
+	default:
+		return nil, nil
+	}
+}
+
+//FileArchive stores the archived rows in files of a local directory
+type FileArchive struct {
+	dir string
+}
+
+func NewFileArchive(dir string) *FileArchive {
+	return &FileArchive{dir: dir}
+}
+
+func (a *FileArchive) Store(name string, data []byte) error {
+	file := filepath.Join(a.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return errors.Wrapf(err, "failed to create directory of archive file '%s'", file)
+	}
+	return errors.Wrapf(ioutil.WriteFile(file, data, 0600), "failed to write archive file '%s'", file)
+}
+
+//compressRows converts the rows to JSON lines (one JSON object per row) and compresses them with gzip
+func compressRows(rows []map[string]interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	encoder := json.NewEncoder(writer)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
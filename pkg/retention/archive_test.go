@@ -0,0 +1,29 @@
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileArchive(t *testing.T) {
+	dir := t.TempDir()
+	data, err := compressRows([]map[string]interface{}{
+		{"id": 1, "status": "ready"},
+		{"id": 2, "status": "error"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, NewFileArchive(dir).Store("statuses/inventory_cluster_config_statuses/1.jsonl.gz", data))
+
+	archived, err := ioutil.ReadFile(filepath.Join(dir, "statuses", "inventory_cluster_config_statuses", "1.jsonl.gz"))
+	require.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(archived))
+	require.NoError(t, err)
+	jsonl, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "{\"id\":1,\"status\":\"ready\"}\n{\"id\":2,\"status\":\"error\"}\n", string(jsonl))
+}
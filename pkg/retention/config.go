@@ -0,0 +1,101 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultBatchSize = 500
+	defaultS3Region  = "us-east-1"
+)
+
+//supported archive types
+const (
+	ArchiveTypeNone = "none"
+	ArchiveTypeFile = "file"
+	ArchiveTypeS3   = "s3"
+)
+
+type Config struct {
+	Interval  time.Duration //interval of the retention runs (0 disables the retention)
+	BatchSize int           //rows which are archived and deleted per transaction
+	Periods   Periods
+	Archive   ArchiveConfig
+}
+
+//Periods defines how long entities are kept: a period of 0 keeps the entities forever
+type Periods struct {
+	Operations      time.Duration //operations of finished reconciliations
+	Statuses        time.Duration //cluster statuses (the latest status of a cluster is always kept)
+	Configs         time.Duration //cluster configurations (the latest configuration of a cluster is always kept)
+	DeletedClusters time.Duration //all entities of a deleted cluster (measured from its last status change)
+}
+
+//ArchiveConfig defines where expired rows are exported to before they get deleted
+type ArchiveConfig struct {
+	Type string //'none', 'file' or 's3'
+	Dir  string //directory of the 'file' archive
+	S3   S3Config
+}
+
+//S3Config defines a bucket of an S3 compatible object store (e.g. AWS S3 or MinIO)
+type S3Config struct {
+	Endpoint  string //e.g. 'https://s3.eu-central-1.amazonaws.com' or 'http://localhost:9000'
+	Region    string
+	Bucket    string
+	Prefix    string //prefix of the object names (optional)
+	AccessKey string
+	SecretKey string
+}
+
+func (c *Config) Enabled() bool {
+	return c.Interval > 0 && (c.Periods.Operations > 0 || c.Periods.Statuses > 0 ||
+		c.Periods.Configs > 0 || c.Periods.DeletedClusters > 0)
+}
+
+func (c *Config) Validate() error {
+	if c.Interval < 0 || c.Periods.Operations < 0 || c.Periods.Statuses < 0 || c.Periods.Configs < 0 ||
+		c.Periods.DeletedClusters < 0 {
+		return fmt.Errorf("retention interval and periods cannot be < 0")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("retention batch size cannot be < 0")
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	switch c.Archive.Type {
+	case ArchiveTypeNone:
+		return nil
+	case ArchiveTypeFile:
+		if c.Archive.Dir == "" {
+			return fmt.Errorf("directory of the retention archive is undefined")
+		}
+		return nil
+	case ArchiveTypeS3:
+		return c.Archive.S3.validate()
+	case "":
+		return fmt.Errorf("type of the retention archive is undefined (use '%s' to delete expired rows without archiving them)",
+			ArchiveTypeNone)
+	default:
+		return fmt.Errorf("retention archive type '%s' not supported (supported types are '%s', '%s' and '%s')",
+			c.Archive.Type, ArchiveTypeNone, ArchiveTypeFile, ArchiveTypeS3)
+	}
+}
+
+func (c *S3Config) validate() error {
+	if c.Endpoint == "" {
+		return fmt.Errorf("endpoint of the S3 retention archive is undefined")
+	}
+	if c.Bucket == "" {
+		return fmt.Errorf("bucket of the S3 retention archive is undefined")
+	}
+	if c.AccessKey == "" || c.SecretKey == "" {
+		return fmt.Errorf("credentials of the S3 retention archive are undefined")
+	}
+	if c.Region == "" {
+		c.Region = defaultS3Region
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package retention
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+//categories of entities which are covered by the retention
+const (
+	CategoryDeletedClusters = "deletedClusters"
+	CategoryOperations      = "operations"
+	CategoryStatuses        = "statuses"
+	CategoryConfigs         = "configs"
+)
+
+//expiredDeletedClusters selects the runtime IDs of deleted clusters which weren't changed since the deadline
+const expiredDeletedClusters = "SELECT runtime_id FROM inventory_clusters WHERE runtime_id NOT IN (" +
+	"SELECT runtime_id FROM inventory_clusters WHERE created >= $1 " +
+	"UNION SELECT runtime_id FROM inventory_cluster_config_statuses WHERE created >= $1) AND deleted <> $2"
+
+const expiredDeletedClusterConfigs = "SELECT version FROM inventory_cluster_configs WHERE runtime_id IN (" +
+	expiredDeletedClusters + ")"
+
+//rule selects the expired rows of a table: the condition has to use the placeholders $1 (deadline) and $2 (FALSE)
+//in this order (SQLite binds the arguments in the order the placeholders appear)
+type rule struct {
+	category  string
+	table     string
+	keys      []string //columns which identify a row
+	condition string
+}
+
+//rules are ordered by their dependencies: rows have to be deleted before the rows they are referencing
+var rules = []*rule{
+	//all entities of deleted clusters
+	{
+		category:  CategoryDeletedClusters,
+		table:     "scheduler_operations",
+		keys:      []string{"scheduling_id", "correlation_id"},
+		condition: "cluster_config IN (" + expiredDeletedClusterConfigs + ")",
+	},
+	{
+		category:  CategoryDeletedClusters,
+		table:     "scheduler_reconciliations",
+		keys:      []string{"scheduling_id"},
+		condition: "cluster_config IN (" + expiredDeletedClusterConfigs + ")",
+	},
+	{
+		category:  CategoryDeletedClusters,
+		table:     "inventory_cluster_config_statuses",
+		keys:      []string{"id"},
+		condition: "runtime_id IN (" + expiredDeletedClusters + ")",
+	},
+	{
+		category:  CategoryDeletedClusters,
+		table:     "inventory_cluster_configs",
+		keys:      []string{"version"},
+		condition: "runtime_id IN (" + expiredDeletedClusters + ")",
+	},
+	{
+		category:  CategoryDeletedClusters,
+		table:     "inventory_clusters",
+		keys:      []string{"version"},
+		condition: "runtime_id IN (" + expiredDeletedClusters + ")",
+	},
+	//operations of finished reconciliations
+	{
+		category: CategoryOperations,
+		table:    "scheduler_operations",
+		keys:     []string{"scheduling_id", "correlation_id"},
+		condition: "created < $1 AND scheduling_id IN (" +
+			"SELECT scheduling_id FROM scheduler_reconciliations WHERE finished <> $2)",
+	},
+	//statuses which are neither the latest status of a cluster nor referenced by a reconciliation
+	{
+		category: CategoryStatuses,
+		table:    "inventory_cluster_config_statuses",
+		keys:     []string{"id"},
+		condition: "created < $1 AND deleted = $2 " +
+			"AND id NOT IN (SELECT MAX(id) FROM inventory_cluster_config_statuses GROUP BY runtime_id) " +
+			"AND id NOT IN (SELECT cluster_config_status FROM scheduler_reconciliations WHERE cluster_config_status IS NOT NULL)",
+	},
+	//configurations which are neither the latest configuration of a cluster nor referenced by other entities
+	{
+		category: CategoryConfigs,
+		table:    "inventory_cluster_configs",
+		keys:     []string{"version"},
+		condition: "created < $1 AND deleted = $2 " +
+			"AND version NOT IN (SELECT MAX(version) FROM inventory_cluster_configs GROUP BY runtime_id) " +
+			"AND version NOT IN (SELECT config_version FROM inventory_cluster_config_statuses) " +
+			"AND version NOT IN (SELECT cluster_config FROM scheduler_reconciliations) " +
+			"AND version NOT IN (SELECT cluster_config FROM scheduler_operations)",
+	},
+}
+
+//TableResult contains the rows of a table which were archived and deleted by a retention run
+type TableResult struct {
+	Category      string
+	Table         string
+	Deleted       int64
+	ArchivedBytes int64
+}
+
+type Result struct {
+	Started  time.Time
+	Duration time.Duration
+	Tables   []*TableResult
+	Err      error
+}
+
+//MetricsCollector is notified about the result of each retention run
+type MetricsCollector interface {
+	OnRetentionRun(result *Result)
+}
+
+//Retention exports expired rows to an archive and deletes them afterwards. Each batch of rows is archived before
+//the transaction which deletes the rows is opened: rows are only deleted if the archive has stored them. Archive
+//names are derived from the keys of the archived rows: a batch which is archived again (e.g. because the deletion
+//failed) overwrites the previous archive.
+type Retention struct {
+	conn             db.Connection
+	config           *Config
+	archive          Archive
+	metricsCollector MetricsCollector
+	logger           *zap.SugaredLogger
+}
+
+func NewRetention(conn db.Connection, config *Config, logger *zap.SugaredLogger) (*Retention, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	archive, err := NewArchive(&config.Archive)
+	if err != nil {
+		return nil, err
+	}
+	return &Retention{
+		conn:    conn,
+		config:  config,
+		archive: archive,
+		logger:  logger,
+	}, nil
+}
+
+func (r *Retention) WithArchive(archive Archive) *Retention {
+	r.archive = archive
+	return r
+}
+
+func (r *Retention) WithMetricsCollector(collector MetricsCollector) *Retention {
+	r.metricsCollector = collector
+	return r
+}
+
+func (r *Retention) Run(ctx context.Context) error {
+	if !r.config.Enabled() {
+		r.logger.Info("[RETENTION] Retention is disabled")
+		return nil
+	}
+	r.logger.Infof("[RETENTION] Starting retention: interval is %s", r.config.Interval)
+
+	ticker := time.NewTicker(r.config.Interval)
+	r.purge() //check for expired entities now, otherwise first check would be trigger by ticker
+	for {
+		select {
+		case <-ticker.C:
+			r.purge()
+		case <-ctx.Done():
+			r.logger.Info("[RETENTION] Stopping because parent context got closed")
+			ticker.Stop()
+			return nil
+		}
+	}
+}
+
+func (r *Retention) purge() {
+	result := r.Purge(time.Now())
+	if result.Err == nil {
+		r.logger.Infof("[RETENTION] Process finished in %s", result.Duration)
+	} else {
+		r.logger.Errorf("[RETENTION] Process failed after %s: %s", result.Duration, result.Err)
+	}
+}
+
+//Purge archives and deletes all entities which are expired at the given time
+func (r *Retention) Purge(now time.Time) *Result {
+	result := &Result{Started: time.Now()}
+	for _, rule := range rules {
+		period := r.period(rule.category)
+		if period <= 0 {
+			continue
+		}
+		tableResult := &TableResult{Category: rule.category, Table: rule.table}
+		result.Tables = append(result.Tables, tableResult)
+		if err := r.purgeTable(rule, now.Add(-period), tableResult); err != nil {
+			result.Err = errors.Wrapf(err, "failed to purge %s of table '%s'", rule.category, rule.table)
+			break
+		}
+	}
+	result.Duration = time.Since(result.Started)
+	if r.metricsCollector != nil {
+		r.metricsCollector.OnRetentionRun(result)
+	}
+	return result
+}
+
+func (r *Retention) period(category string) time.Duration {
+	switch category {
+	case CategoryDeletedClusters:
+		return r.config.Periods.DeletedClusters
+	case CategoryOperations:
+		return r.config.Periods.Operations
+	case CategoryStatuses:
+		return r.config.Periods.Statuses
+	case CategoryConfigs:
+		return r.config.Periods.Configs
+	default:
+		return 0
+	}
+}
+
+func (r *Retention) purgeTable(rule *rule, deadline time.Time, result *TableResult) error {
+	for {
+		deleted, archived, err := r.purgeBatch(rule, deadline)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return nil
+		}
+		result.Deleted += deleted
+		result.ArchivedBytes += archived
+		r.logger.Infof("[RETENTION] Deleted %d %s of table '%s' (created before %s)",
+			result.Deleted, rule.category, rule.table, deadline.UTC())
+	}
+}
+
+func (r *Retention) purgeBatch(rule *rule, deadline time.Time) (int64, int64, error) {
+	//raw queries are executed in transactions: the query validation of connections would reject them
+	var rows []map[string]interface{}
+	err := db.Transaction(r.conn, func(tx *db.TxConnection) error {
+		var err error
+		rows, err = r.expiredRows(tx, rule, deadline)
+		return err
+	}, r.logger)
+	if err != nil || len(rows) == 0 {
+		return 0, 0, err
+	}
+
+	//the archive is stored outside of a transaction to avoid holding locks while the rows are uploaded
+	var archived int64
+	if r.archive != nil {
+		data, err := compressRows(rows)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "failed to export rows")
+		}
+		if err := r.archive.Store(archiveName(rule, rows), data); err != nil {
+			return 0, 0, err
+		}
+		archived = int64(len(data))
+	}
+
+	//rows are only deleted if they are still expired (they could be referenced by new entities meanwhile)
+	conditions := make([]string, len(rule.keys))
+	for i, key := range rule.keys {
+		conditions[i] = fmt.Sprintf("%s=$%d", key, i+3)
+	}
+	deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE (%s) AND %s",
+		rule.table, rule.condition, strings.Join(conditions, " AND "))
+	var deleted int64
+	err = db.Transaction(r.conn, func(tx *db.TxConnection) error {
+		deleted = 0
+		for _, row := range rows {
+			args := append(expiryArgs(deadline), make([]interface{}, len(rule.keys))...)
+			for i, key := range rule.keys {
+				args[i+2] = row[key]
+			}
+			res, err := tx.Exec(deleteSQL, args...)
+			if err != nil {
+				return err
+			}
+			count, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += count
+		}
+		return nil
+	}, r.logger)
+	if err != nil {
+		return 0, 0, err
+	}
+	return deleted, archived, nil
+}
+
+//expiryArgs returns the arguments of the placeholders used by the conditions of the rules
+func expiryArgs(deadline time.Time) []interface{} {
+	return []interface{}{deadline.UTC().Format("2006-01-02 15:04:05.000"), false}
+}
+
+func (r *Retention) expiredRows(tx *db.TxConnection, rule *rule, deadline time.Time) ([]map[string]interface{}, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s ORDER BY %s LIMIT %d",
+		rule.table, rule.condition, strings.Join(rule.keys, ", "), r.config.BatchSize)
+	rows, err := tx.GetTx().Query(query, expiryArgs(deadline)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.Warnf("[RETENTION] Failed to close result of query '%s': %s", query, err)
+		}
+	}()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() { //all rows have to be read before the deletes are executed
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if value, ok := values[i].([]byte); ok {
+				row[column] = string(value)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+//archiveName returns the name of the archive for a batch of rows, e.g. 'operations/scheduler_operations/ab12cd34ef56ab78.jsonl.gz'.
+//The name is the hash of the keys of the rows: archiving the same rows again results in the same name.
+func archiveName(rule *rule, rows []map[string]interface{}) string {
+	hash := sha256.New()
+	for _, row := range rows {
+		for _, key := range rule.keys {
+			fmt.Fprintf(hash, "%s=%v\n", key, row[key])
+		}
+	}
+	return fmt.Sprintf("%s/%s/%s.jsonl.gz", rule.category, rule.table, hex.EncodeToString(hash.Sum(nil))[:16])
+}
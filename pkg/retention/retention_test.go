@@ -0,0 +1,272 @@
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type testArchive struct {
+	sync.Mutex
+	objects map[string][]byte
+	err     error
+	onStore func() //called before an object is stored
+}
+
+func (a *testArchive) Store(name string, data []byte) error {
+	a.Lock()
+	defer a.Unlock()
+	if a.onStore != nil {
+		a.onStore()
+	}
+	if a.err != nil {
+		return a.err
+	}
+	if a.objects == nil {
+		a.objects = make(map[string][]byte)
+	}
+	a.objects[name] = data
+	return nil
+}
+
+//rows returns the archived rows of a category and table
+func (a *testArchive) rows(t *testing.T, category, table string) []map[string]interface{} {
+	var result []map[string]interface{}
+	for name, data := range a.objects {
+		if !strings.HasPrefix(name, fmt.Sprintf("%s/%s/", category, table)) {
+			continue
+		}
+		require.True(t, strings.HasSuffix(name, ".jsonl.gz"))
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		jsonl, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		for _, line := range strings.Split(strings.TrimSpace(string(jsonl)), "\n") {
+			row := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(line), &row))
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+type testMetricsCollector struct {
+	results []*Result
+}
+
+func (c *testMetricsCollector) OnRetentionRun(result *Result) {
+	c.results = append(c.results, result)
+}
+
+func TestRetention(t *testing.T) {
+	config := &Config{
+		Interval:  time.Hour,
+		BatchSize: 1,
+		Periods: Periods{
+			Operations:      24 * time.Hour,
+			Statuses:        24 * time.Hour,
+			Configs:         24 * time.Hour,
+			DeletedClusters: 24 * time.Hour,
+		},
+		Archive: ArchiveConfig{Type: ArchiveTypeNone},
+	}
+
+	t.Run("Archive and delete expired entities", func(t *testing.T) {
+		conn := db.NewTestSqliteConnection(t)
+		insertHistory(t, conn)
+
+		archive := &testArchive{}
+		collector := &testMetricsCollector{}
+		retention, err := NewRetention(conn, config, logger.NewLogger(true))
+		require.NoError(t, err)
+		result := retention.WithArchive(archive).WithMetricsCollector(collector).Purge(time.Now())
+		require.NoError(t, result.Err)
+		require.Equal(t, []*Result{result}, collector.results)
+
+		deleted := make(map[string]int64)
+		for _, table := range result.Tables {
+			deleted[table.Category+"/"+table.Table] = table.Deleted
+			if table.Deleted > 0 {
+				require.NotZero(t, table.ArchivedBytes)
+			}
+		}
+		require.Equal(t, map[string]int64{
+			"deletedClusters/scheduler_operations":              1,
+			"deletedClusters/scheduler_reconciliations":         1,
+			"deletedClusters/inventory_cluster_config_statuses": 1,
+			"deletedClusters/inventory_cluster_configs":         1,
+			"deletedClusters/inventory_clusters":                1,
+			"operations/scheduler_operations":                   1,
+			"statuses/inventory_cluster_config_statuses":        1,
+			"configs/inventory_cluster_configs":                 1,
+		}, deleted)
+
+		//remaining entities
+		require.ElementsMatch(t, []string{"active", "deleted_2_recent"},
+			queryStrings(t, conn, "SELECT runtime_id FROM inventory_clusters"))
+		require.ElementsMatch(t, []string{"2", "4"},
+			queryStrings(t, conn, "SELECT version FROM inventory_cluster_configs"))
+		require.ElementsMatch(t, []string{"2", "3", "5"},
+			queryStrings(t, conn, "SELECT id FROM inventory_cluster_config_statuses"))
+		require.ElementsMatch(t, []string{"r1"},
+			queryStrings(t, conn, "SELECT scheduling_id FROM scheduler_reconciliations"))
+		require.ElementsMatch(t, []string{"o2"},
+			queryStrings(t, conn, "SELECT correlation_id FROM scheduler_operations"))
+
+		//archived rows contain all columns
+		clusters := archive.rows(t, CategoryDeletedClusters, "inventory_clusters")
+		require.Len(t, clusters, 1)
+		require.Equal(t, "deleted_1_gone", clusters[0]["runtime_id"])
+		require.Equal(t, "kubeconfig", clusters[0]["kubeconfig"])
+		operations := archive.rows(t, CategoryOperations, "scheduler_operations")
+		require.Len(t, operations, 1)
+		require.Equal(t, "o1", operations[0]["correlation_id"])
+
+		//nothing left to purge
+		result = retention.Purge(time.Now())
+		require.NoError(t, result.Err)
+		for _, table := range result.Tables {
+			require.Zero(t, table.Deleted)
+		}
+	})
+
+	t.Run("Keep entities if archive fails", func(t *testing.T) {
+		conn := db.NewTestSqliteConnection(t)
+		insertHistory(t, conn)
+
+		retention, err := NewRetention(conn, config, logger.NewLogger(true))
+		require.NoError(t, err)
+		result := retention.WithArchive(&testArchive{err: fmt.Errorf("archive not available")}).Purge(time.Now())
+		require.Error(t, result.Err)
+		require.Len(t, queryStrings(t, conn, "SELECT correlation_id FROM scheduler_operations"), 3)
+	})
+
+	t.Run("Keep entities which are no longer expired after archiving them", func(t *testing.T) {
+		conn := db.NewTestSqliteConnection(t)
+		insertHistory(t, conn)
+
+		archive := &testArchive{onStore: func() {
+			//a new reconciliation references the expired status 1 while the batch is archived
+			_, err := conn.DB().Exec(`INSERT INTO scheduler_reconciliations (scheduling_id, runtime_id, cluster_config,
+				cluster_config_status, status, finished, created) VALUES ('r3', 'active', 1, 1, 'ready', FALSE, '` +
+				time.Now().UTC().Format("2006-01-02 15:04:05") + `')`)
+			require.NoError(t, err)
+		}}
+		retention, err := NewRetention(conn, &Config{
+			Interval: time.Hour,
+			Periods:  Periods{Statuses: 24 * time.Hour},
+			Archive:  ArchiveConfig{Type: ArchiveTypeNone},
+		}, logger.NewLogger(true))
+		require.NoError(t, err)
+		result := retention.WithArchive(archive).Purge(time.Now())
+		require.NoError(t, result.Err)
+		require.Zero(t, result.Tables[0].Deleted)
+		require.ElementsMatch(t, []string{"1", "2", "3", "4", "5"},
+			queryStrings(t, conn, "SELECT id FROM inventory_cluster_config_statuses"))
+	})
+
+	t.Run("Derive archive names from the archived rows", func(t *testing.T) {
+		rule := rules[0]
+		rows := []map[string]interface{}{
+			{"scheduling_id": "r1", "correlation_id": "o1", "component": "istio"},
+			{"scheduling_id": "r1", "correlation_id": "o2", "component": "istio"},
+		}
+		name := archiveName(rule, rows)
+		require.Regexp(t, `^deletedClusters/scheduler_operations/[0-9a-f]{16}\.jsonl\.gz$`, name)
+		require.Equal(t, name, archiveName(rule, []map[string]interface{}{
+			{"scheduling_id": "r1", "correlation_id": "o1", "component": "changed"},
+			{"scheduling_id": "r1", "correlation_id": "o2", "component": "changed"},
+		}))
+		require.NotEqual(t, name, archiveName(rule, rows[:1]))
+	})
+
+	t.Run("Keep entities without retention period", func(t *testing.T) {
+		conn := db.NewTestSqliteConnection(t)
+		insertHistory(t, conn)
+
+		retention, err := NewRetention(conn, &Config{
+			Interval: time.Hour,
+			Periods:  Periods{Operations: 24 * time.Hour},
+			Archive:  ArchiveConfig{Type: ArchiveTypeNone},
+		}, logger.NewLogger(true))
+		require.NoError(t, err)
+		result := retention.Purge(time.Now())
+		require.NoError(t, result.Err)
+		require.Len(t, result.Tables, 1)
+		require.Len(t, queryStrings(t, conn, "SELECT runtime_id FROM inventory_clusters"), 3)
+		require.ElementsMatch(t, []string{"o2"},
+			queryStrings(t, conn, "SELECT correlation_id FROM scheduler_operations"))
+	})
+
+	t.Run("Validate configuration", func(t *testing.T) {
+		require.Error(t, (&Config{Interval: time.Hour}).Validate())
+		require.Error(t, (&Config{Archive: ArchiveConfig{Type: "tape"}}).Validate())
+		require.Error(t, (&Config{Archive: ArchiveConfig{Type: ArchiveTypeFile}}).Validate())
+		require.Error(t, (&Config{Archive: ArchiveConfig{Type: ArchiveTypeS3, S3: S3Config{Endpoint: "http://localhost:9000"}}}).Validate())
+
+		config := &Config{Archive: ArchiveConfig{Type: ArchiveTypeFile, Dir: t.TempDir()}}
+		require.NoError(t, config.Validate())
+		require.Equal(t, defaultBatchSize, config.BatchSize)
+		require.False(t, config.Enabled())
+	})
+}
+
+//insertHistory creates the history of an active cluster and of two deleted clusters (one was deleted recently)
+func insertHistory(t *testing.T, conn db.Connection) {
+	old := time.Now().UTC().Add(-48 * time.Hour).Format("2006-01-02 15:04:05")
+	recent := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	statements := []string{
+		`INSERT INTO inventory_clusters (version, runtime_id, runtime, metadata, kubeconfig, contract, deleted, created) VALUES
+			(1, 'active', '{}', '{}', 'kubeconfig', 1, FALSE, '` + old + `'),
+			(2, 'deleted_1_gone', '{}', '{}', 'kubeconfig', 1, 'TRUE', '` + old + `'),
+			(3, 'deleted_2_recent', '{}', '{}', 'kubeconfig', 1, 'TRUE', '` + old + `')`,
+		`INSERT INTO inventory_cluster_configs (version, runtime_id, cluster_version, kyma_version, contract, deleted, created) VALUES
+			(1, 'active', 1, '2.0.0', 1, FALSE, '` + old + `'),
+			(2, 'active', 1, '2.1.0', 1, FALSE, '` + old + `'),
+			(3, 'deleted_1_gone', 2, '2.0.0', 1, 'TRUE', '` + old + `'),
+			(4, 'deleted_2_recent', 3, '2.0.0', 1, 'TRUE', '` + old + `')`,
+		`INSERT INTO inventory_cluster_config_statuses (id, runtime_id, cluster_version, config_version, status, deleted, created) VALUES
+			(1, 'active', 1, 1, 'ready', FALSE, '` + old + `'),
+			(2, 'active', 1, 2, 'reconciling', FALSE, '` + old + `'),
+			(3, 'active', 1, 2, 'ready', FALSE, '` + recent + `'),
+			(4, 'deleted_1_gone', 2, 3, 'deleted', 'TRUE', '` + old + `'),
+			(5, 'deleted_2_recent', 3, 4, 'deleted', 'TRUE', '` + recent + `')`,
+		`INSERT INTO scheduler_reconciliations (scheduling_id, runtime_id, cluster_config, cluster_config_status, status, finished, created) VALUES
+			('r1', 'active', 2, 2, 'ready', TRUE, '` + old + `'),
+			('r2', 'gone', 3, 4, 'deleted', TRUE, '` + old + `')`,
+		`INSERT INTO scheduler_operations (priority, scheduling_id, correlation_id, runtime_id, cluster_config, component, type, state, retries, retry_id, created) VALUES
+			(1, 'r1', 'o1', 'active', 2, 'istio', 'reconcile', 'done', 0, 'o1', '` + old + `'),
+			(1, 'r1', 'o2', 'active', 2, 'istio', 'reconcile', 'done', 0, 'o2', '` + recent + `'),
+			(1, 'r2', 'o3', 'gone', 3, 'istio', 'delete', 'done', 0, 'o3', '` + old + `')`,
+	}
+	for _, statement := range statements {
+		_, err := conn.DB().Exec(statement)
+		require.NoError(t, err)
+	}
+}
+
+func queryStrings(t *testing.T, conn db.Connection, query string) []string {
+	rows, err := conn.DB().Query(query)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, rows.Close())
+	}()
+	var result []string
+	for rows.Next() {
+		var value string
+		require.NoError(t, rows.Scan(&value))
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+)
+
+const s3RequestTimeout = 60 * time.Second
+
+//S3Archive uploads the archived rows to a bucket of an S3 compatible object store. Objects are uploaded with
+//path-style URLs (supported by AWS S3 and MinIO).
+type S3Archive struct {
+	config *S3Config
+	client *s3.Client
+}
+
+func NewS3Archive(config *S3Config) (*S3Archive, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid S3 configuration")
+	}
+	client := s3.New(s3.Options{
+		Region: config.Region,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: config.AccessKey, SecretAccessKey: config.SecretKey}, nil
+		}),
+		EndpointResolver: s3.EndpointResolverFromURL(config.Endpoint),
+		UsePathStyle:     true,
+		HTTPClient:       &http.Client{Timeout: s3RequestTimeout},
+	})
+	return &S3Archive{
+		config: config,
+		client: client,
+	}, nil
+}
+
+func (a *S3Archive) Store(name string, data []byte) error {
+	object := strings.Trim(a.config.Prefix+"/"+name, "/")
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+	_, err := a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(a.config.Bucket),
+		Key:         aws.String(object),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/gzip"),
+	})
+	return errors.Wrapf(err, "failed to upload archive object '%s'", object)
+}
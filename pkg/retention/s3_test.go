@@ -0,0 +1,49 @@
+package retention
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Archive(t *testing.T) {
+	t.Run("Upload object", func(t *testing.T) {
+		var mu sync.Mutex
+		objects := make(map[string][]byte)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, http.MethodPut, r.Method)
+			require.Equal(t, "application/gzip", r.Header.Get("Content-Type"))
+			mu.Lock()
+			objects[r.URL.Path] = body
+			mu.Unlock()
+		}))
+		defer server.Close()
+
+		archive, err := NewS3Archive(&S3Config{
+			Endpoint:  server.URL,
+			Bucket:    "archive",
+			Prefix:    "reconciler",
+			AccessKey: "minio",
+			SecretKey: "minio123",
+		})
+		require.NoError(t, err)
+		require.NoError(t, archive.Store("operations/scheduler_operations/1.jsonl.gz", []byte("data")))
+		require.Equal(t, map[string][]byte{
+			"/archive/reconciler/operations/scheduler_operations/1.jsonl.gz": []byte("data"),
+		}, objects)
+
+		archive.config.AccessKey = "invalid"
+		require.Error(t, archive.Store("operations/scheduler_operations/2.jsonl.gz", []byte("data")))
+	})
+}
@@ -2,16 +2,32 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/kyma-incubator/reconciler/pkg/retention"
 	"github.com/pkg/errors"
 )
 
-const FallbackComponentReconciler = "base"
+const (
+	FallbackComponentReconciler = "base"
+
+	runPathSuffix      = "/run"
+	prefetchPathSuffix = "/prefetch"
+)
 
 type ComponentReconciler struct {
 	URL string
 }
 
+//PrefetchURL returns the URL of the workspace prefetch endpoint of the component reconciler. An empty string is
+//returned if the URL doesn't point to the run endpoint of a component reconciler.
+func (r ComponentReconciler) PrefetchURL() string {
+	if !strings.HasSuffix(r.URL, runPathSuffix) {
+		return ""
+	}
+	return strings.TrimSuffix(r.URL, runPathSuffix) + prefetchPathSuffix
+}
+
 type SchedulerConfig struct {
 	PreComponents  [][]string
 	Reconcilers    map[string]ComponentReconciler
@@ -22,7 +38,12 @@ type Config struct {
 	Scheme    string
 	Host      string
 	Port      int
-	Scheduler SchedulerConfig
+	Landscape string //landscape the mothership is running in (e.g. 'dev', 'stage', 'prod')
+	//ConfigBuckets defines the ordering of the KV buckets merged into the configuration of a cluster
+	//(e.g. 'default', 'landscape-${landscape}', '${globalAccountID}', '${runtimeID}')
+	ConfigBuckets []string
+	Scheduler     SchedulerConfig
+	Retention     retention.Config
 }
 
 func (c *Config) Validate() error {
@@ -19,3 +19,8 @@ func TestConfig(t *testing.T) {
 	require.NoError(t, viper.UnmarshalKey("mothership", cfg))
 	require.NotEmpty(t, cfg.Scheduler.Reconcilers[FallbackComponentReconciler])
 }
+
+func TestPrefetchURL(t *testing.T) {
+	require.Equal(t, "http://localhost:8081/v1/prefetch", ComponentReconciler{URL: "http://localhost:8081/v1/run"}.PrefetchURL())
+	require.Empty(t, ComponentReconciler{URL: "http://localhost:8081/v1/other"}.PrefetchURL())
+}
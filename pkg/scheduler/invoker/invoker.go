@@ -11,6 +11,14 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 )
 
+const redactedValue = "<redacted>"
+
+//ConfigurationSource provides the merged values of the KV buckets assigned to a cluster. The scheduling ID
+//identifies the reconciliation which consumes the values (empty if no reconciliation consumes them).
+type ConfigurationSource interface {
+	Values(schedulingID string, state *cluster.State) (map[string]interface{}, []string, error)
+}
+
 type Invoker interface {
 	Invoke(ctx context.Context, params *Params) error
 }
@@ -23,6 +31,8 @@ type Params struct {
 	CorrelationID        string
 	MaxOperationRetries  int
 	Type                 model.OperationType
+	Buckets              []string               //KV buckets of the cluster in their layering order
+	BucketValues         map[string]interface{} //merged values of the buckets, applied beneath the component configuration
 }
 
 func (p *Params) newLocalTask(callbackFunc func(msg *reconciler.CallbackMessage) error) *reconciler.Task {
@@ -47,7 +57,7 @@ func (p *Params) newTask() *reconciler.Task {
 		version = p.ComponentToReconcile.Version
 	}
 
-	configuration := p.ComponentToReconcile.ConfigurationAsMap()
+	configuration := p.configuration()
 	tokenNamespace := configuration["repo.token.namespace"]
 	if tokenNamespace == nil {
 		tokenNamespace = ""
@@ -74,3 +84,32 @@ func (p *Params) newTask() *reconciler.Task {
 		},
 	}
 }
+
+//configuration returns the merged bucket values overridden by the configuration entries of the component
+func (p *Params) configuration() map[string]interface{} {
+	result := make(map[string]interface{}, len(p.BucketValues)+len(p.ComponentToReconcile.Configuration))
+	for key, value := range p.BucketValues {
+		result[key] = value
+	}
+	for key, value := range p.ComponentToReconcile.ConfigurationAsMap() {
+		result[key] = value
+	}
+	return result
+}
+
+//effectiveConfig returns the configuration sent to the component reconciler with redacted secret values
+func (p *Params) effectiveConfig(task *reconciler.Task) *model.EffectiveConfig {
+	configuration := make(map[string]interface{}, len(task.Configuration))
+	for key, value := range task.Configuration {
+		configuration[key] = value
+	}
+	for _, entry := range p.ComponentToReconcile.Configuration {
+		if entry.Secret {
+			configuration[entry.Key] = redactedValue
+		}
+	}
+	return &model.EffectiveConfig{
+		Buckets:       p.Buckets,
+		Configuration: configuration,
+	}
+}
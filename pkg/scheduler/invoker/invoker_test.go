@@ -36,3 +36,33 @@ func TestInvoker(t *testing.T) {
 	assert.Equal(t, "", task.Repository.TokenNamespace, "Should parse repo token namespace correctly")
 	assert.Equal(t, model.OperationTypeDelete, task.Type, "Task type should equal operation type")
 }
+
+func TestTaskConfiguration(t *testing.T) {
+	params := Params{
+		ComponentToReconcile: &keb.Component{
+			Component: "test",
+			Configuration: []keb.Configuration{
+				{Key: "global.domainName", Value: "keb.domain"},
+				{Key: "password", Value: "secretValue", Secret: true},
+			},
+		},
+		ClusterState: clusterStateMock,
+		Buckets:      []string{"default"},
+		BucketValues: map[string]interface{}{
+			"global.domainName": "bucket.domain",
+			"bucket.key":        int64(123),
+		},
+	}
+
+	task := params.newTask()
+	assert.Equal(t, map[string]interface{}{
+		"global.domainName": "keb.domain",
+		"password":          "secretValue",
+		"bucket.key":        int64(123),
+	}, task.Configuration, "KEB configuration should override bucket values")
+
+	effectiveConfig := params.effectiveConfig(task)
+	assert.Equal(t, []string{"default"}, effectiveConfig.Buckets)
+	assert.Equal(t, redactedValue, effectiveConfig.Configuration["password"], "Secret values should be redacted")
+	assert.Equal(t, "secretValue", task.Configuration["password"], "Task configuration should not be modified")
+}
@@ -59,6 +59,11 @@ func (i *LocalReconcilerInvoker) Invoke(ctx context.Context, params *Params) err
 		component, params.SchedulingID, params.CorrelationID)
 
 	reconModel := params.newLocalTask(i.newCallbackFunc(params))
+	if err := i.reconRepo.UpdateOperationEffectiveConfig(params.SchedulingID, params.CorrelationID,
+		params.effectiveConfig(reconModel)); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("local invoker failed to record effective configuration of "+
+			"operation (schedulingID:%s/correlationID:%s)", params.SchedulingID, params.CorrelationID))
+	}
 
 	return compRecon.StartLocal(ctx, reconModel, i.logger)
 }
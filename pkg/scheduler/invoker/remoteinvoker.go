@@ -20,10 +20,16 @@ import (
 
 const callbackURLTemplate = "%s://%s:%d/v1/operations/%s/callback/%s"
 
+//ProfileValuesSource provides values which are injected into the profile of a component
+type ProfileValuesSource interface {
+	ProfileValues(component, profile, landscape string) (map[string]interface{}, error)
+}
+
 type RemoteReconcilerInvoker struct {
-	reconRepo reconciliation.Repository
-	config    *config.Config
-	logger    *zap.SugaredLogger
+	reconRepo     reconciliation.Repository
+	config        *config.Config
+	logger        *zap.SugaredLogger
+	profileValues ProfileValuesSource
 }
 
 func NewRemoteReconcilerInvoker(reconRepo reconciliation.Repository, cfg *config.Config, logger *zap.SugaredLogger) *RemoteReconcilerInvoker {
@@ -34,6 +40,11 @@ func NewRemoteReconcilerInvoker(reconRepo reconciliation.Repository, cfg *config
 	}
 }
 
+func (i *RemoteReconcilerInvoker) WithProfileValuesSource(source ProfileValuesSource) *RemoteReconcilerInvoker {
+	i.profileValues = source
+	return i
+}
+
 func (i *RemoteReconcilerInvoker) Invoke(_ context.Context, params *Params) error {
 	if err := i.ensureOperationNotInProgress(params); err != nil {
 		return err
@@ -124,6 +135,19 @@ func (i *RemoteReconcilerInvoker) sendHTTPRequest(params *Params) (*http.Respons
 		params.SchedulingID,
 		params.CorrelationID)
 	payload := params.newRemoteTask(callbackURL)
+	payload.Landscape = i.config.Landscape
+	if i.profileValues != nil {
+		profileValues, err := i.profileValues.ProfileValues(component, payload.Profile, payload.Landscape)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to retrieve profile values of component '%s'", component))
+		}
+		payload.ProfileValues = profileValues
+	}
+
+	if err := i.reconRepo.UpdateOperationEffectiveConfig(params.SchedulingID, params.CorrelationID,
+		params.effectiveConfig(payload)); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to record effective configuration of component '%s'", component))
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
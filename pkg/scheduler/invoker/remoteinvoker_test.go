@@ -154,6 +154,51 @@ func TestRemoteInvoker(t *testing.T) {
 
 		requireOperationState(t, reconRepo, opEntities[5], model.OperationStateClientError)
 	})
+
+	t.Run("Invoke component-reconciler: record effective configuration", func(t *testing.T) {
+		cfg := &config.Config{
+			Scheme: "https",
+			Host:   "mothership-reconciler",
+			Port:   443,
+			Scheduler: config.SchedulerConfig{
+				Reconcilers: map[string]config.ComponentReconciler{
+					"base": {
+						URL: "http://127.0.0.1:5555/200",
+					},
+				},
+			},
+		}
+		invoker := NewRemoteReconcilerInvoker(reconRepo, cfg, logger.NewLogger(true))
+		err := invoker.Invoke(context.Background(), &Params{
+			ComponentToReconcile: &keb.Component{
+				Component: model.CRDComponent,
+				Version:   "1.2.3",
+				Configuration: []keb.Configuration{
+					{Key: "global.domainName", Value: "keb.domain"},
+					{Key: "password", Value: "secretValue", Secret: true},
+				},
+			},
+			ClusterState:  clusterStateMock,
+			SchedulingID:  opEntities[6].SchedulingID,
+			CorrelationID: opEntities[6].CorrelationID,
+			Buckets:       []string{"default", "landscape-dev"},
+			BucketValues:  map[string]interface{}{"global.domainName": "bucket.domain", "bucket.key": "bucketValue"},
+		})
+		require.NoError(t, err)
+
+		op, err := reconRepo.GetOperation(opEntities[6].SchedulingID, opEntities[6].CorrelationID)
+		require.NoError(t, err)
+		effectiveConfig := &model.EffectiveConfig{}
+		require.NoError(t, json.Unmarshal([]byte(op.EffectiveConfig), effectiveConfig))
+		require.Equal(t, &model.EffectiveConfig{
+			Buckets: []string{"default", "landscape-dev"},
+			Configuration: map[string]interface{}{
+				"global.domainName": "keb.domain",
+				"bucket.key":        "bucketValue",
+				"password":          redactedValue,
+			},
+		}, effectiveConfig)
+	})
 }
 
 func invokeRemoteInvoker(reconRepo reconciliation.Repository, op *model.OperationEntity, cfg *config.Config) error {
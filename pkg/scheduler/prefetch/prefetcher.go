@@ -0,0 +1,177 @@
+package prefetch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/config"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout          = 30 * time.Second
+	versionLocal            = "local"
+	tokenNamespaceConfigKey = "repo.token.namespace"
+)
+
+//Prefetcher triggers the component reconcilers to prepare the workspace of a Kyma version as soon as
+//the version is referenced by a cluster configuration for the first time.
+type Prefetcher struct {
+	urls       []string
+	httpClient *http.Client
+	logger     *zap.SugaredLogger
+	mu         sync.Mutex
+	seen       map[string]bool
+	wg         sync.WaitGroup
+}
+
+func NewPrefetcher(reconcilers map[string]config.ComponentReconciler, logger *zap.SugaredLogger) *Prefetcher {
+	uniqueURLs := make(map[string]bool)
+	for _, recon := range reconcilers {
+		if url := recon.PrefetchURL(); url != "" {
+			uniqueURLs[url] = true
+		}
+	}
+	urls := make([]string, 0, len(uniqueURLs))
+	for url := range uniqueURLs {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	return &Prefetcher{
+		urls:       urls,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		logger:     logger,
+		seen:       make(map[string]bool),
+	}
+}
+
+//Notify triggers the prefetching of all Kyma versions referenced by the cluster configuration which weren't seen
+//before. The prefetch requests are sent in the background and the names of the triggered versions are returned.
+func (p *Prefetcher) Notify(configuration *keb.KymaConfig) []string {
+	if configuration == nil {
+		return nil
+	}
+
+	var versions []string
+	p.mu.Lock()
+	for _, version := range referencedVersions(configuration) {
+		if p.seen[version] {
+			continue
+		}
+		p.seen[version] = true
+		versions = append(versions, version)
+	}
+	p.mu.Unlock()
+
+	for _, version := range versions {
+		p.wg.Add(1)
+		go func(version string, repo *reconciler.Repository) {
+			defer p.wg.Done()
+			if err := p.prefetch(version, repo); err != nil {
+				p.logger.Warnf("Failed to trigger prefetching of workspace for Kyma version '%s': %s", version, err)
+				p.forget(version) //retry when the version is referenced the next time
+			}
+		}(version, kymaRepository(configuration, version))
+	}
+	return versions
+}
+
+//Wait blocks until all prefetch requests were sent
+func (p *Prefetcher) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Prefetcher) forget(version string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.seen, version)
+}
+
+func (p *Prefetcher) prefetch(version string, repo *reconciler.Repository) error {
+	payload, err := json.Marshal(&reconciler.PrefetchRequest{Version: version, Repository: repo})
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, url := range p.urls {
+		if err := p.send(url, payload); err != nil {
+			p.logger.Warnf("Prefetch request for Kyma version '%s' to component reconciler '%s' failed: %s",
+				version, url, err)
+			failed = append(failed, url)
+			continue
+		}
+		p.logger.Debugf("Component reconciler '%s' is prefetching workspace of Kyma version '%s'", url, version)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d component reconcilers did not accept the prefetch request", len(failed), len(p.urls))
+	}
+	return nil
+}
+
+func (p *Prefetcher) send(url string, payload []byte) error {
+	resp, err := p.httpClient.Post(url, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			p.logger.Warnf("Error while closing HTTP response body: %s", err)
+		}
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode > 299 {
+		return fmt.Errorf("component reconciler responded with HTTP code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+//referencedVersions returns the Kyma versions used by the cluster configuration (components can override
+//the Kyma version if they are not retrieved from an external URL)
+func referencedVersions(configuration *keb.KymaConfig) []string {
+	var versions []string
+	unique := make(map[string]bool)
+	add := func(version string) {
+		if version == "" || version == versionLocal || unique[version] {
+			return
+		}
+		unique[version] = true
+		versions = append(versions, version)
+	}
+
+	add(configuration.Version)
+	for _, component := range configuration.Components {
+		if component.URL == "" {
+			add(component.Version)
+		}
+	}
+	return versions
+}
+
+//kymaRepository returns the repository of the Kyma version the same way as it is passed to the component reconcilers
+//when the components are reconciled: the default repository is used and the token namespace is taken from the
+//configuration of the components which use this version
+func kymaRepository(configuration *keb.KymaConfig, version string) *reconciler.Repository {
+	repo := &reconciler.Repository{}
+	for _, component := range configuration.Components {
+		componentVersion := component.Version
+		if componentVersion == "" {
+			componentVersion = configuration.Version
+		}
+		if component.URL != "" || componentVersion != version {
+			continue
+		}
+		if tokenNamespace, ok := component.ConfigurationAsMap()[tokenNamespaceConfigKey]; ok && tokenNamespace != nil {
+			repo.TokenNamespace = fmt.Sprint(tokenNamespace)
+			break
+		}
+	}
+	return repo
+}
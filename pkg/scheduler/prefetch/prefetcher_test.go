@@ -0,0 +1,82 @@
+package prefetch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefetcher(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	repositories := make(map[string]*reconciler.Repository)
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, "/v1/prefetch", r.URL.Path)
+		req := &reconciler.PrefetchRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		requests = append(requests, req.Version)
+		repositories[req.Version] = req.Repository
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	prefetcher := NewPrefetcher(map[string]config.ComponentReconciler{
+		"base":  {URL: server.URL + "/v1/run"},
+		"istio": {URL: server.URL + "/v1/run"}, //same reconciler is called only once
+		"other": {URL: server.URL + "/unknown"},
+	}, logger.NewLogger(true))
+
+	t.Run("Prefetch unseen versions", func(t *testing.T) {
+		triggered := prefetcher.Notify(&keb.KymaConfig{
+			Version: "1.0.0",
+			Components: []keb.Component{
+				{Component: "comp1"},
+				{Component: "comp2", Version: "1.1.0", Configuration: []keb.Configuration{
+					{Key: "repo.token.namespace", Value: "tokens"},
+				}},
+				{Component: "comp3", Version: "2.0.0", URL: "https://github.com/kyma-project/comp3.git"},
+			},
+		})
+		prefetcher.Wait()
+		require.ElementsMatch(t, []string{"1.0.0", "1.1.0"}, triggered)
+		require.ElementsMatch(t, []string{"1.0.0", "1.1.0"}, requests)
+		require.Equal(t, &reconciler.Repository{}, repositories["1.0.0"])
+		require.Equal(t, &reconciler.Repository{TokenNamespace: "tokens"}, repositories["1.1.0"])
+
+		//versions were already seen
+		require.Empty(t, prefetcher.Notify(&keb.KymaConfig{Version: "1.0.0"}))
+		require.Empty(t, prefetcher.Notify(&keb.KymaConfig{Version: "local"}))
+	})
+
+	setFail := func(value bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		fail = value
+	}
+
+	t.Run("Retry failed prefetch", func(t *testing.T) {
+		setFail(true)
+		require.Equal(t, []string{"3.0.0"}, prefetcher.Notify(&keb.KymaConfig{Version: "3.0.0"}))
+		prefetcher.Wait()
+
+		setFail(false)
+		require.Equal(t, []string{"3.0.0"}, prefetcher.Notify(&keb.KymaConfig{Version: "3.0.0"}))
+		prefetcher.Wait()
+		require.Empty(t, prefetcher.Notify(&keb.KymaConfig{Version: "3.0.0"}))
+	})
+}
@@ -1,6 +1,7 @@
 package reconciliation
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -295,6 +296,59 @@ func (r *InMemoryReconciliationRepository) UpdateOperationPickedUp(schedulingID,
 	return nil
 }
 
+func (r *InMemoryReconciliationRepository) UpdateOperationEffectiveConfig(schedulingID, correlationID string, effectiveConfig *model.EffectiveConfig) error {
+	effectiveConfigJSON, err := json.Marshal(effectiveConfig)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.operations[schedulingID]
+	if !ok {
+		return &repository.EntityNotFoundError{}
+	}
+	op, ok := r.operations[schedulingID][correlationID]
+	if !ok {
+		return &repository.EntityNotFoundError{}
+	}
+
+	// copy the operation to avoid having data races while writing
+	opCopy := *op
+
+	opCopy.EffectiveConfig = string(effectiveConfigJSON)
+	r.operations[schedulingID][correlationID] = &opCopy
+
+	return nil
+}
+
+func (r *InMemoryReconciliationRepository) AddOperationTriggerResults(schedulingID, correlationID string, results []*model.TriggerResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.operations[schedulingID]
+	if !ok {
+		return &repository.EntityNotFoundError{}
+	}
+	op, ok := r.operations[schedulingID][correlationID]
+	if !ok {
+		return &repository.EntityNotFoundError{}
+	}
+
+	// copy the operation to avoid having data races while writing
+	opCopy := *op
+
+	triggerResultsJSON, err := appendTriggerResults(opCopy.TriggerResults, results)
+	if err != nil {
+		return err
+	}
+	opCopy.TriggerResults = triggerResultsJSON
+	r.operations[schedulingID][correlationID] = &opCopy
+
+	return nil
+}
+
 func (r *InMemoryReconciliationRepository) UpdateComponentOperationProcessingDuration(schedulingID, correlationID string, processingDuration int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
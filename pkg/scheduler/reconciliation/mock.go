@@ -20,6 +20,8 @@ type MockRepository struct {
 	UpdateOperationStateResult                          error
 	UpdateOperationRetryIDResult                        error
 	UpdateOperationPickedUpResult                       error
+	UpdateOperationEffectiveConfigResult                error
+	AddOperationTriggerResultsResult                    error
 	UpdateComponentOperationProcessingDurationResult    error
 	GetComponentOperationProcessingDurationResult       int64
 	GetComponentOperationProcessingDurationResultError  error
@@ -81,6 +83,14 @@ func (mr *MockRepository) UpdateOperationPickedUp(schedulingID, correlationID st
 	return mr.UpdateOperationPickedUpResult
 }
 
+func (mr *MockRepository) UpdateOperationEffectiveConfig(schedulingID, correlationID string, effectiveConfig *model.EffectiveConfig) error {
+	return mr.UpdateOperationEffectiveConfigResult
+}
+
+func (mr *MockRepository) AddOperationTriggerResults(schedulingID, correlationID string, results []*model.TriggerResult) error {
+	return mr.AddOperationTriggerResultsResult
+}
+
 func (mr *MockRepository) UpdateComponentOperationProcessingDuration(schedulingID, correlationID string, processingDuration int) error {
 	return mr.UpdateComponentOperationProcessingDurationResult
 }
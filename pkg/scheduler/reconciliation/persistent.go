@@ -3,6 +3,7 @@ package reconciliation
 import (
 	"bytes"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -497,6 +498,91 @@ func (r *PersistentReconciliationRepository) UpdateOperationPickedUp(schedulingI
 	return db.Transaction(r.Conn, dbOps, r.Logger)
 }
 
+func (r *PersistentReconciliationRepository) UpdateOperationEffectiveConfig(schedulingID, correlationID string, effectiveConfig *model.EffectiveConfig) error {
+	effectiveConfigJSON, err := json.Marshal(effectiveConfig)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to marshal effective configuration of operation "+
+			"(schedulingID:%s/correlationID:%s)", schedulingID, correlationID))
+	}
+
+	dbOps := func(tx *db.TxConnection) error {
+		rTx, err := r.WithTx(tx)
+		if err != nil {
+			return err
+		}
+		op, err := rTx.GetOperation(schedulingID, correlationID)
+		if err != nil {
+			if repository.IsNotFoundError(err) {
+				r.Logger.Warnf("ReconRepo could not find operation (schedulingID:%s/correlationID:%s)", schedulingID, correlationID)
+			}
+			return err
+		}
+
+		//update operation-entity
+		op.EffectiveConfig = string(effectiveConfigJSON)
+
+		//prepare update query
+		q, err := db.NewQuery(tx, op, r.Logger)
+		if err != nil {
+			return err
+		}
+		whereCond := map[string]interface{}{
+			"CorrelationID": correlationID,
+			"SchedulingID":  schedulingID,
+		}
+		cnt, err := q.Update().
+			Where(whereCond).
+			ExecCount()
+		if cnt == 0 {
+			return fmt.Errorf("update of operation '%s' effective configuration failed: no row was updated", op)
+		}
+		return err
+	}
+	return db.Transaction(r.Conn, dbOps, r.Logger)
+}
+
+func (r *PersistentReconciliationRepository) AddOperationTriggerResults(schedulingID, correlationID string, results []*model.TriggerResult) error {
+	dbOps := func(tx *db.TxConnection) error {
+		rTx, err := r.WithTx(tx)
+		if err != nil {
+			return err
+		}
+		op, err := rTx.GetOperation(schedulingID, correlationID)
+		if err != nil {
+			if repository.IsNotFoundError(err) {
+				r.Logger.Warnf("ReconRepo could not find operation (schedulingID:%s/correlationID:%s)", schedulingID, correlationID)
+			}
+			return err
+		}
+
+		//update operation-entity
+		triggerResultsJSON, err := appendTriggerResults(op.TriggerResults, results)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("failed to marshal trigger results of operation "+
+				"(schedulingID:%s/correlationID:%s)", schedulingID, correlationID))
+		}
+		op.TriggerResults = triggerResultsJSON
+
+		//prepare update query
+		q, err := db.NewQuery(tx, op, r.Logger)
+		if err != nil {
+			return err
+		}
+		whereCond := map[string]interface{}{
+			"CorrelationID": correlationID,
+			"SchedulingID":  schedulingID,
+		}
+		cnt, err := q.Update().
+			Where(whereCond).
+			ExecCount()
+		if cnt == 0 {
+			return fmt.Errorf("update of operation '%s' trigger results failed: no row was updated", op)
+		}
+		return err
+	}
+	return db.Transaction(r.Conn, dbOps, r.Logger)
+}
+
 func (r *PersistentReconciliationRepository) UpdateComponentOperationProcessingDuration(schedulingID, correlationID string, processingDuration int) error {
 	dbOps := func(tx *db.TxConnection) error {
 		rTx, err := r.WithTx(tx)
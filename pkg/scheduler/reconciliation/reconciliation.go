@@ -1,6 +1,7 @@
 package reconciliation
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/db"
@@ -33,6 +34,10 @@ type Repository interface {
 	WithTx(tx *db.TxConnection) (Repository, error)
 	UpdateOperationRetryID(schedulingID, correlationID, retryID string) error
 	UpdateOperationPickedUp(schedulingID, correlationID string) error
+	//UpdateOperationEffectiveConfig records the configuration which was sent to the component reconciler
+	UpdateOperationEffectiveConfig(schedulingID, correlationID string, effectiveConfig *model.EffectiveConfig) error
+	//AddOperationTriggerResults appends the results of executed key triggers to the operation
+	AddOperationTriggerResults(schedulingID, correlationID string, results []*model.TriggerResult) error
 	UpdateComponentOperationProcessingDuration(schedulingID, correlationID string, processingDuration int) error
 	GetComponentOperationProcessingDuration(component string, state model.OperationState) (int64, error)
 	GetMothershipOperationProcessingDuration(component string, state model.OperationState, startTime metricStartTime) (int64, error)
@@ -181,3 +186,16 @@ func IsAlreadyInStateError(err error) bool {
 	_, ok := err.(*alreadyInStateError)
 	return ok
 }
+
+//appendTriggerResults adds trigger results to the JSON list of trigger results stored in an operation
+func appendTriggerResults(triggerResultsJSON string, results []*model.TriggerResult) (string, error) {
+	var allResults []*model.TriggerResult
+	if triggerResultsJSON != "" {
+		if err := json.Unmarshal([]byte(triggerResultsJSON), &allResults); err != nil {
+			return "", err
+		}
+	}
+	allResults = append(allResults, results...)
+	result, err := json.Marshal(allResults)
+	return string(result), err
+}
@@ -777,11 +777,11 @@ func removeExistingReconciliations(t *testing.T, repos map[string]Repository) {
 
 func createClusterStates(t *testing.T, inventory cluster.Inventory) (*cluster.State, *cluster.State) {
 	clusterID1 := uuid.NewString()
-	stateMock1, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID1, 1, false, test.ThreeComponentsDummy))
+	stateMock1, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID1, 1, false, test.ThreeComponentsDummy), nil)
 	require.NoError(t, err)
 
 	clusterID2 := uuid.NewString()
-	stateMock2, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID2, 1, false, test.OneComponentDummy))
+	stateMock2, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID2, 1, false, test.OneComponentDummy), nil)
 	require.NoError(t, err)
 	return stateMock1, stateMock2
 }
@@ -839,9 +839,9 @@ func TestTransaction(t *testing.T) {
 			require.NoError(t, err)
 
 			//add clusters to inventory
-			clusterState, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "1", 1, false, test.OneComponentDummy))
+			clusterState, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "1", 1, false, test.OneComponentDummy), nil)
 			require.NoError(t, err)
-			clusterState2, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "2", 1, false, test.OneComponentDummy))
+			clusterState2, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "2", 1, false, test.OneComponentDummy), nil)
 			require.NoError(t, err)
 
 			//create two clusters
@@ -972,7 +972,7 @@ func TestReconciliationParallel(t *testing.T) {
 			require.NoError(t, err)
 
 			//add cluster to inventory
-			clusterState, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "1", 1, false, test.OneComponentDummy))
+			clusterState, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "1", 1, false, test.OneComponentDummy), nil)
 			require.NoError(t, err)
 
 			//trigger reconciliation for cluster
@@ -51,7 +51,7 @@ func TestBookkeepingTask(t *testing.T) {
 			}()
 
 			//add cluster to inventory
-			clusterState, err := inventory.CreateOrUpdate(1, testCluster)
+			clusterState, err := inventory.CreateOrUpdate(1, testCluster, nil)
 			require.NoError(t, err)
 
 			//trigger reconciliation for cluster
@@ -171,7 +171,7 @@ func TestBookkeepingTaskParallel(t *testing.T) {
 			require.NoError(t, err)
 
 			//add cluster to inventory
-			clusterState, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "random", 1, false, test.OneComponentDummy))
+			clusterState, err := inventory.CreateOrUpdate(1, test.NewCluster(t, "random", 1, false, test.OneComponentDummy), nil)
 			require.NoError(t, err)
 			//cleanup cluster at the end
 			defer func() {
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/repository"
+	"go.uber.org/zap"
+)
+
+//ConfigChangeRepository provides the configuration changes recorded when cached cluster configurations
+//got invalidated
+type ConfigChangeRepository interface {
+	ConfigChanges() ([]*model.ConfigChangeEntity, error)
+	DeleteConfigChanges(ids []int64) error
+}
+
+func newConfigChangeWatch(repo ConfigChangeRepository, inventory cluster.Inventory, logger *zap.SugaredLogger, config *SchedulerConfig) *configChangeWatcher {
+	return &configChangeWatcher{
+		repo:      repo,
+		inventory: inventory,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+type configChangeWatcher struct {
+	repo      ConfigChangeRepository
+	inventory cluster.Inventory
+	config    *SchedulerConfig
+	logger    *zap.SugaredLogger
+}
+
+func (w *configChangeWatcher) Run(ctx context.Context, queue inventoryQueue) error {
+	w.logger.Infof("Starting configuration change watcher with an watch-interval of %.1f secs "+
+		"and a debounce period of %.1f secs",
+		w.config.ConfigChangeWatchInterval.Seconds(), w.config.ConfigChangeDebounce.Seconds())
+
+	ticker := time.NewTicker(w.config.ConfigChangeWatchInterval)
+	for {
+		select {
+		case <-ticker.C:
+			w.processConfigChanges(queue)
+		case <-ctx.Done():
+			w.logger.Info("Stopping configuration change watcher because parent context got closed")
+			ticker.Stop()
+			return nil
+		}
+	}
+}
+
+//processConfigChanges enqueues clusters whose configuration didn't change within the debounce period:
+//a batch of changes results in one reconciliation per cluster
+func (w *configChangeWatcher) processConfigChanges(queue inventoryQueue) {
+	changes, err := w.repo.ConfigChanges()
+	if err != nil {
+		w.logger.Errorf("Configuration change watcher failed to fetch configuration changes: %s", err)
+		return
+	}
+
+	var runtimeIDs []string
+	changesByRuntimeID := make(map[string][]*model.ConfigChangeEntity)
+	for _, change := range changes {
+		if _, ok := changesByRuntimeID[change.RuntimeID]; !ok {
+			runtimeIDs = append(runtimeIDs, change.RuntimeID)
+		}
+		changesByRuntimeID[change.RuntimeID] = append(changesByRuntimeID[change.RuntimeID], change)
+	}
+
+	for _, runtimeID := range runtimeIDs {
+		runtimeChanges := changesByRuntimeID[runtimeID]
+		lastChange := runtimeChanges[len(runtimeChanges)-1].Created
+		if time.Since(lastChange) < w.config.ConfigChangeDebounce {
+			w.logger.Debugf("Configuration change watcher is waiting for further changes of cluster '%s' "+
+				"(last change %.1f secs ago)", runtimeID, time.Since(lastChange).Seconds())
+			continue
+		}
+		if !w.enqueue(runtimeID, queue) {
+			continue
+		}
+		if err := w.repo.DeleteConfigChanges(configChangeIDs(runtimeChanges)); err != nil {
+			w.logger.Errorf("Configuration change watcher failed to delete processed configuration changes "+
+				"of cluster '%s': %s", runtimeID, err)
+		}
+	}
+}
+
+//enqueue adds the cluster to the scheduling queue and returns false if the configuration changes have to be
+//processed again later (e.g. because the cluster is currently reconciled)
+func (w *configChangeWatcher) enqueue(runtimeID string, queue inventoryQueue) bool {
+	clusterState, err := w.inventory.GetLatest(runtimeID)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			w.logger.Infof("Configuration change watcher dropped changes of cluster '%s' "+
+				"because it doesn't exist in inventory", runtimeID)
+			return true
+		}
+		w.logger.Errorf("Configuration change watcher failed to retrieve cluster '%s' from inventory: %s",
+			runtimeID, err)
+		return false
+	}
+
+	status := clusterState.Status.Status
+	switch {
+	case status.IsInProgress():
+		//running reconciliation might not include the changes: reconcile again when it's finished
+		w.logger.Debugf("Configuration change watcher is waiting for cluster '%s' to finish its current "+
+			"operation (status: %s)", runtimeID, status)
+		return false
+	case !status.IsReconcileCandidate():
+		w.logger.Infof("Configuration change watcher dropped changes of cluster '%s' "+
+			"because it cannot be reconciled (status: %s)", runtimeID, status)
+		return true
+	}
+
+	w.logger.Infof("Configuration change watcher added runtime '%s' to scheduling queue "+
+		"(clusterVersion:%d/configVersion:%d/status:%s)",
+		runtimeID, clusterState.Cluster.Version, clusterState.Configuration.Version, status)
+	queue <- clusterState
+	return true
+}
+
+func configChangeIDs(changes []*model.ConfigChangeEntity) []int64 {
+	ids := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, change.ID)
+	}
+	return ids
+}
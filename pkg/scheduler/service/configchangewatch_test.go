@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfigChangeRepository struct {
+	changes []*model.ConfigChangeEntity
+}
+
+func (r *testConfigChangeRepository) ConfigChanges() ([]*model.ConfigChangeEntity, error) {
+	return r.changes, nil
+}
+
+func (r *testConfigChangeRepository) DeleteConfigChanges(ids []int64) error {
+	var remaining []*model.ConfigChangeEntity
+	for _, change := range r.changes {
+		deleted := false
+		for _, id := range ids {
+			deleted = deleted || change.ID == id
+		}
+		if !deleted {
+			remaining = append(remaining, change)
+		}
+	}
+	r.changes = remaining
+	return nil
+}
+
+func TestConfigChangeWatch(t *testing.T) {
+	newClusterState := func(status model.Status) *cluster.State {
+		return &cluster.State{
+			Cluster:       &model.ClusterEntity{Version: 1, RuntimeID: "testCluster"},
+			Configuration: &model.ClusterConfigurationEntity{Version: 1, RuntimeID: "testCluster", ClusterVersion: 1},
+			Status:        &model.ClusterStatusEntity{RuntimeID: "testCluster", Status: status},
+		}
+	}
+	newWatcher := func(repo ConfigChangeRepository, clusterState *cluster.State) *configChangeWatcher {
+		return newConfigChangeWatch(repo, &cluster.MockInventory{GetLatestResult: clusterState}, logger.NewLogger(true),
+			&SchedulerConfig{
+				ConfigChangeWatchInterval: 100 * time.Millisecond,
+				ConfigChangeDebounce:      time.Minute,
+			})
+	}
+
+	t.Run("Enqueue cluster once after debounce period", func(t *testing.T) {
+		repo := &testConfigChangeRepository{changes: []*model.ConfigChangeEntity{
+			{ID: 1, RuntimeID: "testCluster", Created: time.Now().Add(-3 * time.Minute)},
+			{ID: 2, RuntimeID: "testCluster", Created: time.Now().Add(-2 * time.Minute)},
+		}}
+		queue := make(chan *cluster.State, 2)
+		newWatcher(repo, newClusterState(model.ClusterStatusReady)).processConfigChanges(queue)
+
+		require.Len(t, queue, 1)
+		require.Equal(t, "testCluster", (<-queue).Cluster.RuntimeID)
+		require.Empty(t, repo.changes)
+	})
+
+	t.Run("Wait for debounce period", func(t *testing.T) {
+		repo := &testConfigChangeRepository{changes: []*model.ConfigChangeEntity{
+			{ID: 1, RuntimeID: "testCluster", Created: time.Now().Add(-3 * time.Minute)},
+			{ID: 2, RuntimeID: "testCluster", Created: time.Now()},
+		}}
+		queue := make(chan *cluster.State, 1)
+		newWatcher(repo, newClusterState(model.ClusterStatusReady)).processConfigChanges(queue)
+
+		require.Empty(t, queue)
+		require.Len(t, repo.changes, 2)
+	})
+
+	t.Run("Wait for running reconciliation", func(t *testing.T) {
+		repo := &testConfigChangeRepository{changes: []*model.ConfigChangeEntity{
+			{ID: 1, RuntimeID: "testCluster", Created: time.Now().Add(-3 * time.Minute)},
+		}}
+		queue := make(chan *cluster.State, 1)
+		newWatcher(repo, newClusterState(model.ClusterStatusReconciling)).processConfigChanges(queue)
+
+		require.Empty(t, queue)
+		require.Len(t, repo.changes, 1)
+	})
+
+	t.Run("Drop changes of clusters which cannot be reconciled", func(t *testing.T) {
+		repo := &testConfigChangeRepository{changes: []*model.ConfigChangeEntity{
+			{ID: 1, RuntimeID: "testCluster", Created: time.Now().Add(-3 * time.Minute)},
+		}}
+		queue := make(chan *cluster.State, 1)
+		newWatcher(repo, newClusterState(model.ClusterStatusDeletePending)).processConfigChanges(queue)
+
+		require.Empty(t, queue)
+		require.Empty(t, repo.changes)
+	})
+
+	t.Run("Stop on context close", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.TODO(), 500*time.Millisecond)
+		defer cancelFn()
+
+		startTime := time.Now()
+		watcher := newWatcher(&testConfigChangeRepository{}, newClusterState(model.ClusterStatusReady))
+		require.NoError(t, watcher.Run(ctx, make(chan *cluster.State)))
+		require.WithinDuration(t, startTime, time.Now(), 2*time.Second)
+	})
+}
@@ -55,7 +55,7 @@ func (rb *RuntimeBuilder) RunRemote(
 	inventory cluster.Inventory, occupancyRepo occupancy.Repository,
 	config *config.Config) *RunRemote {
 
-	runR := &RunRemote{rb, conn, inventory, occupancyRepo, config, &SchedulerConfig{}, &BookkeeperConfig{}, &CleanerConfig{}}
+	runR := &RunRemote{rb, conn, inventory, occupancyRepo, config, &SchedulerConfig{}, &BookkeeperConfig{}, &CleanerConfig{}, nil, nil, nil, nil}
 	return runR
 }
 
@@ -71,6 +71,7 @@ type RunLocal struct {
 	runtimeBuilder  *RuntimeBuilder
 	statusFunc      invoker.ReconcilerStatusFunc
 	schedulerConfig *SchedulerConfig
+	configSource    invoker.ConfigurationSource
 }
 
 func (l *RunLocal) logger() *zap.SugaredLogger { //convenient function
@@ -96,6 +97,11 @@ func (l *RunLocal) WithSchedulerConfig(cfg *SchedulerConfig) *RunLocal {
 	return l
 }
 
+func (l *RunLocal) WithConfigurationSource(source invoker.ConfigurationSource) *RunLocal {
+	l.configSource = source
+	return l
+}
+
 func (l *RunLocal) Run(ctx context.Context, clusterState *cluster.State) (*ReconciliationResult, error) {
 	if err := l.schedulerConfig.validate(); err != nil {
 		return nil, err
@@ -118,6 +124,7 @@ func (l *RunLocal) Run(ctx context.Context, clusterState *cluster.State) (*Recon
 		l.logger().Errorf("Failed to create worker pool: %s", err)
 		return nil, err
 	}
+	workerPool.WithConfigurationSource(l.configSource)
 	if err := workerPool.RunOnce(ctx); err == nil {
 		l.logger().Info("Worker pool finished successfully")
 	} else {
@@ -160,6 +167,10 @@ type RunRemote struct {
 	schedulerConfig  *SchedulerConfig
 	bookkeeperConfig *BookkeeperConfig
 	cleanerConfig    *CleanerConfig
+	profileValues    invoker.ProfileValuesSource
+	configSource     invoker.ConfigurationSource
+	configChanges    ConfigChangeRepository
+	triggerConfig    *TriggerConfig
 }
 
 func (r *RunRemote) logger() *zap.SugaredLogger { //convenient function
@@ -190,13 +201,35 @@ func (r *RunRemote) WithCleanerConfig(cfg *CleanerConfig) *RunRemote {
 	return r
 }
 
+func (r *RunRemote) WithProfileValuesSource(source invoker.ProfileValuesSource) *RunRemote {
+	r.profileValues = source
+	return r
+}
+
+func (r *RunRemote) WithConfigurationSource(source invoker.ConfigurationSource) *RunRemote {
+	r.configSource = source
+	return r
+}
+
+func (r *RunRemote) WithConfigChangeRepository(repo ConfigChangeRepository) *RunRemote {
+	r.configChanges = repo
+	return r
+}
+
+//WithTriggerConfig enables the execution of key triggers at the start and at the end of reconciliations
+func (r *RunRemote) WithTriggerConfig(cfg *TriggerConfig) *RunRemote {
+	r.triggerConfig = cfg
+	return r
+}
+
 func (r *RunRemote) Run(ctx context.Context) error {
 	if err := r.config.Validate(); err != nil {
 		return err
 	}
 	//start bookkeeper
 	go func() {
-		transition := newClusterStatusTransition(r.conn, r.inventory, r.reconciliationRepository(), r.logger())
+		transition := newClusterStatusTransition(r.conn, r.inventory, r.reconciliationRepository(), r.logger()).
+			WithTriggers(r.triggerConfig)
 		if err := newBookkeeper(transition.reconRepo, r.bookkeeperConfig, r.logger()).Run(ctx,
 			markOrphanOperation{transition: transition, logger: r.logger()},
 			finishOperation{transition: transition, logger: r.logger()}); err != nil {
@@ -206,13 +239,15 @@ func (r *RunRemote) Run(ctx context.Context) error {
 
 	//start worker pool
 	go func() {
-		remoteInvoker := invoker.NewRemoteReconcilerInvoker(r.reconciliationRepository(), r.config, r.logger())
+		remoteInvoker := invoker.NewRemoteReconcilerInvoker(r.reconciliationRepository(), r.config, r.logger()).
+			WithProfileValuesSource(r.profileValues)
 		workerPool, err := r.runtimeBuilder.newWorkerPool(&worker.InventoryRetriever{Inventory: r.inventory}, remoteInvoker, r.occupancyRepo)
 		if err == nil {
 			r.logger().Info("Worker pool created")
 		} else {
 			r.logger().Fatalf("Failed to create worker pool: %s", err)
 		}
+		workerPool.WithConfigurationSource(r.configSource)
 
 		if err := workerPool.Run(ctx); err != nil {
 			r.logger().Fatalf("Worker pool returned an error: %s", err)
@@ -221,8 +256,9 @@ func (r *RunRemote) Run(ctx context.Context) error {
 
 	//start scheduler
 	go func() {
-		transition := newClusterStatusTransition(r.conn, r.inventory, r.reconciliationRepository(), r.logger())
-		if err := r.runtimeBuilder.newScheduler().Run(ctx, transition, r.schedulerConfig); err != nil {
+		transition := newClusterStatusTransition(r.conn, r.inventory, r.reconciliationRepository(), r.logger()).
+			WithTriggers(r.triggerConfig)
+		if err := r.runtimeBuilder.newScheduler().WithConfigChangeRepository(r.configChanges).Run(ctx, transition, r.schedulerConfig); err != nil {
 			r.logger().Fatalf("Remote scheduler returned an error: %s", err)
 		}
 	}()
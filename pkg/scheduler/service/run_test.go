@@ -91,7 +91,7 @@ func runRemote(t *testing.T, expectedClusterStatus model.Status, timeout time.Du
 			Version: "1.2.3",
 		},
 		RuntimeID: uuid.NewString(),
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	//create occupancy repository
@@ -250,7 +250,7 @@ func runLocal(t *testing.T, timeout time.Duration) (*ReconciliationResult, []*re
 			Version: "1.2.3",
 		},
 		RuntimeID: uuid.NewString(),
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	//cleanup
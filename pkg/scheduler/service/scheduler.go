@@ -13,9 +13,11 @@ import (
 )
 
 const (
-	defaultQueueSize                = 50
-	defaultInventoryWatchInterval   = 1 * time.Minute
-	defaultClusterReconcileInterval = 15 * time.Minute
+	defaultQueueSize                 = 50
+	defaultInventoryWatchInterval    = 1 * time.Minute
+	defaultClusterReconcileInterval  = 15 * time.Minute
+	defaultConfigChangeWatchInterval = 30 * time.Second
+	defaultConfigChangeDebounce      = 1 * time.Minute
 
 	DeleteStrategySystem DeleteStrategy = "system"
 	DeleteStrategyAll    DeleteStrategy = "all"
@@ -37,11 +39,13 @@ func NewDeleteStrategy(s string) (DeleteStrategy, error) {
 }
 
 type SchedulerConfig struct {
-	PreComponents            [][]string
-	InventoryWatchInterval   time.Duration
-	ClusterReconcileInterval time.Duration
-	ClusterQueueSize         int
-	DeleteStrategy           DeleteStrategy
+	PreComponents             [][]string
+	InventoryWatchInterval    time.Duration
+	ClusterReconcileInterval  time.Duration
+	ClusterQueueSize          int
+	DeleteStrategy            DeleteStrategy
+	ConfigChangeWatchInterval time.Duration
+	ConfigChangeDebounce      time.Duration
 }
 
 func (wc *SchedulerConfig) validate() error {
@@ -63,6 +67,18 @@ func (wc *SchedulerConfig) validate() error {
 	if wc.ClusterQueueSize == 0 {
 		wc.ClusterQueueSize = defaultQueueSize
 	}
+	if wc.ConfigChangeWatchInterval < 0 {
+		return errors.New("configuration change watch interval cannot be < 0")
+	}
+	if wc.ConfigChangeWatchInterval == 0 {
+		wc.ConfigChangeWatchInterval = defaultConfigChangeWatchInterval
+	}
+	if wc.ConfigChangeDebounce < 0 {
+		return errors.New("configuration change debounce period cannot be < 0")
+	}
+	if wc.ConfigChangeDebounce == 0 {
+		wc.ConfigChangeDebounce = defaultConfigChangeDebounce
+	}
 	switch wc.DeleteStrategy {
 	case "": // set default if empty (should not happen)
 		wc.DeleteStrategy = DeleteStrategySystem
@@ -75,7 +91,8 @@ func (wc *SchedulerConfig) validate() error {
 }
 
 type scheduler struct {
-	logger *zap.SugaredLogger
+	logger        *zap.SugaredLogger
+	configChanges ConfigChangeRepository
 }
 
 func newScheduler(logger *zap.SugaredLogger) *scheduler {
@@ -84,6 +101,12 @@ func newScheduler(logger *zap.SugaredLogger) *scheduler {
 	}
 }
 
+//WithConfigChangeRepository enables the reconciliation of clusters whose configuration was changed
+func (s *scheduler) WithConfigChangeRepository(repo ConfigChangeRepository) *scheduler {
+	s.configChanges = repo
+	return s
+}
+
 func (s *scheduler) RunOnce(clusterState *cluster.State, reconRepo reconciliation.Repository, config *SchedulerConfig) error {
 	s.logger.Debugf("Starting local scheduler")
 	reconEntity, err := reconRepo.CreateReconciliation(clusterState, &model.ReconciliationSequenceConfig{
@@ -103,6 +126,9 @@ func (s *scheduler) Run(ctx context.Context, transition *ClusterStatusTransition
 
 	queue := make(chan *cluster.State, config.ClusterQueueSize)
 	s.startInventoryWatcher(ctx, transition.Inventory(), config, queue)
+	if s.configChanges != nil {
+		s.startConfigChangeWatcher(ctx, transition.Inventory(), config, queue)
+	}
 
 	for {
 		select {
@@ -142,3 +168,21 @@ func (s *scheduler) startInventoryWatcher(ctx context.Context, inventory cluster
 
 	}(ctx, inventory, s.logger, queue, config)
 }
+
+func (s *scheduler) startConfigChangeWatcher(ctx context.Context, inventory cluster.Inventory, config *SchedulerConfig, queue chan *cluster.State) {
+	s.logger.Infof("Starting configuration change watcher")
+
+	go func(ctx context.Context,
+		repo ConfigChangeRepository,
+		clInv cluster.Inventory,
+		logger *zap.SugaredLogger,
+		queue chan *cluster.State,
+		cfg *SchedulerConfig) {
+
+		watcher := newConfigChangeWatch(repo, clInv, logger, cfg)
+		if err := watcher.Run(ctx, queue); err != nil {
+			logger.Errorf("Configuration change watcher returned an error: %s", err)
+		}
+
+	}(ctx, s.configChanges, inventory, s.logger, queue, config)
+}
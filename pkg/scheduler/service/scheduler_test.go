@@ -123,11 +123,11 @@ func testClusterState(clusterID string, statusID int64) *cluster.State {
 
 func createClusterStates(t *testing.T, inventory cluster.Inventory) []string {
 	clusterID1 := uuid.NewString()
-	s1, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID1, 1, false, test.ThreeComponentsDummy))
+	s1, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID1, 1, false, test.ThreeComponentsDummy), nil)
 	require.NoError(t, err)
 
 	clusterID2 := uuid.NewString()
-	s2, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID2, 1, false, test.OneComponentDummy))
+	s2, err := inventory.CreateOrUpdate(1, test.NewCluster(t, clusterID2, 1, false, test.OneComponentDummy), nil)
 	require.NoError(t, err)
 
 	return []string{s1.Cluster.RuntimeID, s2.Cluster.RuntimeID}
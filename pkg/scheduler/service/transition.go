@@ -16,6 +16,7 @@ type ClusterStatusTransition struct {
 	inventory cluster.Inventory
 	reconRepo reconciliation.Repository
 	logger    *zap.SugaredLogger
+	triggers  *triggerRunner
 }
 
 func newClusterStatusTransition(
@@ -31,6 +32,14 @@ func newClusterStatusTransition(
 	}
 }
 
+//WithTriggers enables the execution of key triggers when reconciliations are started or finished
+func (t *ClusterStatusTransition) WithTriggers(config *TriggerConfig) *ClusterStatusTransition {
+	if config != nil {
+		t.triggers = newTriggerRunner(config, t.reconRepo, t.logger)
+	}
+	return t
+}
+
 func (t *ClusterStatusTransition) Inventory() cluster.Inventory {
 	return t.inventory
 }
@@ -42,6 +51,7 @@ func (t *ClusterStatusTransition) ReconciliationRepository() reconciliation.Repo
 func (t *ClusterStatusTransition) StartReconciliation(runtimeID string, configVersion int64, cfg *model.ReconciliationSequenceConfig) error {
 	var oldClusterState *cluster.State
 	var newClusterState *cluster.State
+	var schedulingID string
 	dbOp := func(tx *db.TxConnection) error {
 		inventoryTx, err := t.inventory.WithTx(tx)
 		if err != nil {
@@ -94,6 +104,7 @@ func (t *ClusterStatusTransition) StartReconciliation(runtimeID string, configVe
 		//create reconciliation entity
 		reconEntity, err := reconRepoTx.CreateReconciliation(newClusterState, cfg)
 		if err == nil {
+			schedulingID = reconEntity.SchedulingID
 			t.logger.Infof("Starting reconciliation for cluster '%s' succeeded: reconciliation successfully enqueued "+
 				"(scheudlingID: %s)", newClusterState.Cluster.RuntimeID, reconEntity.SchedulingID)
 			return nil
@@ -111,6 +122,9 @@ func (t *ClusterStatusTransition) StartReconciliation(runtimeID string, configVe
 		return err
 	}
 	err := db.Transaction(t.conn, dbOp, t.logger)
+	if err == nil && t.triggers != nil && newClusterState.Status.Status == model.ClusterStatusReconciling {
+		t.triggers.RunAsync(model.TriggerPhaseStart, schedulingID, newClusterState)
+	}
 	if reconciliation.IsEmptyComponentsReconciliationError(err) {
 		t.logger.Errorf("Cluster transition tried to add cluster '%s' to reconciliation queue but "+
 			"cluster has no components", newClusterState.Cluster.RuntimeID)
@@ -125,6 +139,7 @@ func (t *ClusterStatusTransition) StartReconciliation(runtimeID string, configVe
 }
 
 func (t *ClusterStatusTransition) FinishReconciliation(schedulingID string, status model.Status) error {
+	var finishedClusterState *cluster.State
 	dbOp := func(tx *db.TxConnection) error {
 		inventory, err := t.inventory.WithTx(tx)
 		if err != nil {
@@ -156,6 +171,7 @@ func (t *ClusterStatusTransition) FinishReconciliation(schedulingID string, stat
 			return err
 		}
 
+		reconciling := clusterState.Status.Status == model.ClusterStatusReconciling
 		if clusterState.Status.Status.IsInProgress() {
 			oldClusterStatus := clusterState.Status.Status
 			clusterState, err = inventory.UpdateStatus(clusterState, status)
@@ -171,6 +187,10 @@ func (t *ClusterStatusTransition) FinishReconciliation(schedulingID string, stat
 				schedulingID, clusterState.Cluster.Version, clusterState.Configuration.Version)
 		}
 
+		if reconciling {
+			finishedClusterState = clusterState
+		}
+
 		err = reconRepo.FinishReconciliation(schedulingID, clusterState.Status)
 		if err == nil {
 			t.logger.Infof("Finishing reconciliation for cluster '%s' succeeded "+
@@ -190,5 +210,9 @@ func (t *ClusterStatusTransition) FinishReconciliation(schedulingID string, stat
 		}
 		return nil
 	}
-	return db.Transaction(t.conn, dbOp, t.logger)
+	err := db.Transaction(t.conn, dbOp, t.logger)
+	if err == nil && t.triggers != nil && finishedClusterState != nil {
+		t.triggers.RunAsync(model.TriggerPhaseEnd, schedulingID, finishedClusterState)
+	}
+	return err
 }
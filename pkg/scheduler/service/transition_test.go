@@ -34,7 +34,7 @@ func TestTransition(t *testing.T) {
 			Version: "1.2.3",
 		},
 		RuntimeID: uuid.NewString(),
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	//create reconciliation entity for the cluster
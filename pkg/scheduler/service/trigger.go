@@ -0,0 +1,156 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/invoker"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation/operation"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTriggerTimeout     = 30 * time.Second
+	defaultTriggerConcurrency = 5
+)
+
+//TriggerKeyRepository provides the latest versions of the configuration keys
+type TriggerKeyRepository interface {
+	Keys() ([]*model.KeyEntity, error)
+}
+
+//TriggerConfig defines the sources used to execute the triggers of the configuration keys consumed by a reconciliation
+type TriggerConfig struct {
+	Keys          TriggerKeyRepository
+	Configuration invoker.ConfigurationSource
+	Timeout       time.Duration
+	//Concurrency limits how many reconciliations execute their triggers in parallel
+	Concurrency int
+}
+
+type triggerRunner struct {
+	config    *TriggerConfig
+	reconRepo reconciliation.Repository
+	logger    *zap.SugaredLogger
+	slots     chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newTriggerRunner(config *TriggerConfig, reconRepo reconciliation.Repository, logger *zap.SugaredLogger) *triggerRunner {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTriggerTimeout
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultTriggerConcurrency
+	}
+	return &triggerRunner{
+		config:    config,
+		reconRepo: reconRepo,
+		logger:    logger,
+		slots:     make(chan struct{}, config.Concurrency),
+	}
+}
+
+//RunAsync executes the triggers in the background, so that the status transition of a cluster isn't delayed
+//by the triggers. The number of concurrently executed trigger runs is limited by the configured concurrency.
+func (r *triggerRunner) RunAsync(phase model.TriggerPhase, schedulingID string, state *cluster.State) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.slots <- struct{}{}
+		defer func() { <-r.slots }()
+		r.Run(phase, schedulingID, state)
+	}()
+}
+
+//Wait blocks until all triggers started by RunAsync are finished
+func (r *triggerRunner) Wait() {
+	r.wg.Wait()
+}
+
+//Run executes the triggers of the given phase for all keys whose values are consumed by the reconciliation and
+//records the trigger results in the operations of the reconciliation. Failing triggers don't affect the
+//reconciliation.
+func (r *triggerRunner) Run(phase model.TriggerPhase, schedulingID string, state *cluster.State) {
+	values, _, err := r.config.Configuration.Values(schedulingID, state)
+	if err != nil {
+		r.logger.Errorf("Trigger runner failed to resolve configuration of cluster '%s' (schedulingID:%s): %s",
+			state.Cluster.RuntimeID, schedulingID, err)
+		return
+	}
+
+	keys, err := r.config.Keys.Keys()
+	if err != nil {
+		r.logger.Errorf("Trigger runner failed to retrieve configuration keys (schedulingID:%s): %s", schedulingID, err)
+		return
+	}
+
+	results := r.exec(phase, state, keys, values)
+	if len(results) == 0 {
+		return
+	}
+
+	ops, err := r.reconRepo.GetOperations(&operation.WithSchedulingID{SchedulingID: schedulingID})
+	if err != nil {
+		r.logger.Errorf("Trigger runner failed to retrieve operations of reconciliation '%s': %s", schedulingID, err)
+		return
+	}
+	for _, op := range ops {
+		if err := r.reconRepo.AddOperationTriggerResults(op.SchedulingID, op.CorrelationID, results); err != nil {
+			r.logger.Errorf("Trigger runner failed to record trigger results in operation '%s': %s", op, err)
+		}
+	}
+}
+
+func (r *triggerRunner) exec(phase model.TriggerPhase, state *cluster.State, keyEntities []*model.KeyEntity, values map[string]interface{}) []*model.TriggerResult {
+	sort.Slice(keyEntities, func(i, j int) bool {
+		return keyEntities[i].Key < keyEntities[j].Key
+	})
+
+	var results []*model.TriggerResult
+	for _, keyEntity := range keyEntities {
+		key := keyEntity.Key
+		if _, consumed := values[key]; !consumed || keyEntity.Trigger == "" || keyEntity.EffectiveTriggerPhase() != phase {
+			continue
+		}
+
+		result := &model.TriggerResult{
+			Key:      key,
+			Phase:    phase,
+			Executed: time.Now().UTC(),
+		}
+		output, err := keyEntity.ExecTrigger(triggerBindings(phase, state, key, values[key]), r.config.Timeout)
+		result.Duration = time.Since(result.Executed).Milliseconds()
+		if err == nil {
+			result.Result = output
+			r.logger.Debugf("Trigger of key '%s' executed for cluster '%s' (phase: %s): %s",
+				key, state.Cluster.RuntimeID, phase, output)
+		} else {
+			result.Error = err.Error()
+			r.logger.Warnf("Trigger of key '%s' failed for cluster '%s' (phase: %s): %s",
+				key, state.Cluster.RuntimeID, phase, err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+//triggerBindings returns the variables available in the code of a trigger
+func triggerBindings(phase model.TriggerPhase, state *cluster.State, key string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"phase":          string(phase),
+		"runtimeID":      state.Cluster.RuntimeID,
+		"clusterVersion": state.Cluster.Version,
+		"configVersion":  state.Configuration.Version,
+		"kymaVersion":    state.Configuration.KymaVersion,
+		"kymaProfile":    state.Configuration.KymaProfile,
+		"clusterStatus":  string(state.Status.Status),
+		"key":            key,
+		"value":          value,
+		"it":             value,
+	}
+}
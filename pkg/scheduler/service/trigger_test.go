@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation/operation"
+	"github.com/stretchr/testify/require"
+)
+
+type testTriggerKeys []*model.KeyEntity
+
+func (k testTriggerKeys) Keys() ([]*model.KeyEntity, error) {
+	return k, nil
+}
+
+type testConfigurationSource struct {
+	values map[string]interface{}
+	err    error
+}
+
+func (s *testConfigurationSource) Values(_ string, state *cluster.State) (map[string]interface{}, []string, error) {
+	return s.values, nil, s.err
+}
+
+func TestTriggerRunner(t *testing.T) {
+	keys := testTriggerKeys{
+		{Key: "key.start", DataType: model.String, Trigger: `import "fmt"
+fmt.Sprintf("%s:%s:%v", phase, runtimeID, value)`, TriggerPhase: model.TriggerPhaseStart},
+		{Key: "key.end", DataType: model.String, Trigger: `"end"`},
+		{Key: "key.failing", DataType: model.String, Trigger: `import "os"
+os.Getenv("HOME")`, TriggerPhase: model.TriggerPhaseStart},
+		{Key: "key.notconsumed", DataType: model.String, Trigger: `"x"`, TriggerPhase: model.TriggerPhaseStart},
+		{Key: "key.notrigger", DataType: model.String, TriggerPhase: model.TriggerPhaseStart},
+	}
+	values := map[string]interface{}{
+		"key.start":     "abc",
+		"key.end":       "xyz",
+		"key.failing":   "123",
+		"key.notrigger": "def",
+	}
+
+	t.Run("Record results of triggers of the phase", func(t *testing.T) {
+		reconRepo := reconciliation.NewInMemoryReconciliationRepository()
+		clusterState := testClusterState("testCluster", 1)
+		reconEntity := newReconciliation(t, reconRepo, clusterState)
+
+		newTriggerRunner(&TriggerConfig{
+			Keys:          keys,
+			Configuration: &testConfigurationSource{values: values},
+		}, reconRepo, logger.NewLogger(true)).Run(model.TriggerPhaseStart, reconEntity.SchedulingID, clusterState)
+
+		ops, err := reconRepo.GetOperations(&operation.WithSchedulingID{SchedulingID: reconEntity.SchedulingID})
+		require.NoError(t, err)
+		require.NotEmpty(t, ops)
+		for _, op := range ops {
+			var results []*model.TriggerResult
+			require.NoError(t, json.Unmarshal([]byte(op.TriggerResults), &results))
+			require.Len(t, results, 2)
+
+			require.Equal(t, "key.failing", results[0].Key)
+			require.Equal(t, model.TriggerPhaseStart, results[0].Phase)
+			require.Empty(t, results[0].Result)
+			require.NotEmpty(t, results[0].Error)
+
+			require.Equal(t, "key.start", results[1].Key)
+			require.Equal(t, "start:testCluster:abc", results[1].Result)
+			require.Empty(t, results[1].Error)
+		}
+
+		//results of the end phase are appended
+		newTriggerRunner(&TriggerConfig{
+			Keys:          keys,
+			Configuration: &testConfigurationSource{values: values},
+		}, reconRepo, logger.NewLogger(true)).Run(model.TriggerPhaseEnd, reconEntity.SchedulingID, clusterState)
+
+		ops, err = reconRepo.GetOperations(&operation.WithSchedulingID{SchedulingID: reconEntity.SchedulingID})
+		require.NoError(t, err)
+		for _, op := range ops {
+			var results []*model.TriggerResult
+			require.NoError(t, json.Unmarshal([]byte(op.TriggerResults), &results))
+			require.Len(t, results, 3)
+			require.Equal(t, "key.end", results[2].Key)
+			require.Equal(t, model.TriggerPhaseEnd, results[2].Phase)
+			require.Equal(t, "end", results[2].Result)
+		}
+	})
+
+	t.Run("Skip triggers if configuration cannot be resolved", func(t *testing.T) {
+		reconRepo := reconciliation.NewInMemoryReconciliationRepository()
+		clusterState := testClusterState("testCluster", 1)
+		reconEntity := newReconciliation(t, reconRepo, clusterState)
+
+		newTriggerRunner(&TriggerConfig{
+			Keys:          keys,
+			Configuration: &testConfigurationSource{err: errors.New("bucket not found")},
+		}, reconRepo, logger.NewLogger(true)).Run(model.TriggerPhaseStart, reconEntity.SchedulingID, clusterState)
+
+		ops, err := reconRepo.GetOperations(&operation.WithSchedulingID{SchedulingID: reconEntity.SchedulingID})
+		require.NoError(t, err)
+		for _, op := range ops {
+			require.Empty(t, op.TriggerResults)
+		}
+	})
+	t.Run("Record results of asynchronously executed triggers", func(t *testing.T) {
+		reconRepo := reconciliation.NewInMemoryReconciliationRepository()
+		clusterState := testClusterState("testCluster", 1)
+		reconEntity := newReconciliation(t, reconRepo, clusterState)
+
+		runner := newTriggerRunner(&TriggerConfig{
+			Keys:          keys,
+			Configuration: &testConfigurationSource{values: values},
+			Concurrency:   1,
+		}, reconRepo, logger.NewLogger(true))
+		runner.RunAsync(model.TriggerPhaseStart, reconEntity.SchedulingID, clusterState)
+		runner.RunAsync(model.TriggerPhaseEnd, reconEntity.SchedulingID, clusterState)
+		runner.Wait()
+
+		ops, err := reconRepo.GetOperations(&operation.WithSchedulingID{SchedulingID: reconEntity.SchedulingID})
+		require.NoError(t, err)
+		require.NotEmpty(t, ops)
+		for _, op := range ops {
+			var results []*model.TriggerResult
+			require.NoError(t, json.Unmarshal([]byte(op.TriggerResults), &results))
+			require.Len(t, results, 3)
+		}
+	})
+}
@@ -11,15 +11,17 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/invoker"
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation"
 	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation/operation"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
 type worker struct {
-	reconRepo  reconciliation.Repository
-	invoker    invoker.Invoker
-	logger     *zap.SugaredLogger
-	maxRetries int
-	retryDelay time.Duration
+	reconRepo    reconciliation.Repository
+	invoker      invoker.Invoker
+	configSource invoker.ConfigurationSource
+	logger       *zap.SugaredLogger
+	maxRetries   int
+	retryDelay   time.Duration
 }
 
 func (w *worker) run(ctx context.Context, clusterState *cluster.State, op *model.OperationEntity, maxOpRetries int) error {
@@ -42,17 +44,22 @@ func (w *worker) run(ctx context.Context, clusterState *cluster.State, op *model
 			clusterState.Cluster.RuntimeID, op.Component)
 	}
 
+	params := &invoker.Params{
+		ComponentToReconcile: comp,
+		ComponentsReady:      compsReady,
+		SchedulingID:         op.SchedulingID,
+		CorrelationID:        op.CorrelationID,
+		ClusterState:         clusterState,
+		MaxOperationRetries:  maxOpRetries,
+		Type:                 op.Type,
+	}
+	if err := w.resolveBucketValues(params); err != nil {
+		return err
+	}
+
 	retryable := func() error {
 		w.logger.Debugf("Worker calls invoker for operation '%s' (in retryable function)", op)
-		return w.invoker.Invoke(ctx, &invoker.Params{
-			ComponentToReconcile: comp,
-			ComponentsReady:      compsReady,
-			SchedulingID:         op.SchedulingID,
-			CorrelationID:        op.CorrelationID,
-			ClusterState:         clusterState,
-			MaxOperationRetries:  maxOpRetries,
-			Type:                 op.Type,
-		})
+		return w.invoker.Invoke(ctx, params)
 	}
 
 	//retry calling the invoker if error was returned
@@ -71,6 +78,22 @@ func (w *worker) run(ctx context.Context, clusterState *cluster.State, op *model
 	return err
 }
 
+//resolveBucketValues adds the merged values of the KV buckets of the cluster to the invoker parameters
+//(used by all invokers to build the configuration of the component)
+func (w *worker) resolveBucketValues(params *invoker.Params) error {
+	if w.configSource == nil {
+		return nil
+	}
+	bucketValues, buckets, err := w.configSource.Values(params.SchedulingID, params.ClusterState)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to resolve configuration of cluster '%s'",
+			params.ClusterState.Cluster.RuntimeID))
+	}
+	params.Buckets = buckets
+	params.BucketValues = bucketValues
+	return nil
+}
+
 func (w *worker) componentsReady(op *model.OperationEntity) ([]string, error) {
 	opsReady, err := w.reconRepo.GetOperations(&operation.FilterMixer{
 		Filters: []operation.Filter{
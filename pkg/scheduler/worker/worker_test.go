@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/invoker"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler/reconciliation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfigurationSource struct {
+	values  map[string]interface{}
+	buckets []string
+	err     error
+}
+
+func (s *testConfigurationSource) Values(_ string, _ *cluster.State) (map[string]interface{}, []string, error) {
+	return s.values, s.buckets, s.err
+}
+
+type paramsRecorder struct {
+	params []*invoker.Params
+}
+
+func (i *paramsRecorder) Invoke(_ context.Context, params *invoker.Params) error {
+	i.params = append(i.params, params)
+	return nil
+}
+
+func TestWorkerBucketValues(t *testing.T) {
+	clusterState := &cluster.State{
+		Cluster: &model.ClusterEntity{RuntimeID: "runtime"},
+		Configuration: &model.ClusterConfigurationEntity{
+			RuntimeID:  "runtime",
+			Components: []*keb.Component{{Component: "comp"}},
+		},
+	}
+	op := &model.OperationEntity{
+		SchedulingID:  "scheduling",
+		CorrelationID: "correlation",
+		Component:     "comp",
+		State:         model.OperationStateNew,
+	}
+
+	t.Run("Invoker receives the merged bucket values", func(t *testing.T) {
+		recorder := &paramsRecorder{}
+		w := &worker{
+			reconRepo: reconciliation.NewInMemoryReconciliationRepository(),
+			invoker:   recorder,
+			configSource: &testConfigurationSource{
+				values:  map[string]interface{}{"bucket.key": "bucketValue"},
+				buckets: []string{"default", "landscape-dev"},
+			},
+			logger:     logger.NewLogger(true),
+			maxRetries: 1,
+		}
+		require.NoError(t, w.run(context.Background(), clusterState, op, 1))
+		require.Len(t, recorder.params, 1)
+		require.Equal(t, []string{"default", "landscape-dev"}, recorder.params[0].Buckets)
+		require.Equal(t, map[string]interface{}{"bucket.key": "bucketValue"}, recorder.params[0].BucketValues)
+	})
+
+	t.Run("Invoker isn't called if bucket values can't be resolved", func(t *testing.T) {
+		recorder := &paramsRecorder{}
+		w := &worker{
+			reconRepo:    reconciliation.NewInMemoryReconciliationRepository(),
+			invoker:      recorder,
+			configSource: &testConfigurationSource{err: errors.New("bucket not found")},
+			logger:       logger.NewLogger(true),
+			maxRetries:   1,
+		}
+		require.Error(t, w.run(context.Background(), clusterState, op, 1))
+		require.Empty(t, recorder.params)
+	})
+}
@@ -22,6 +22,7 @@ type Pool struct {
 	reconRepo     reconciliation.Repository
 	occupancyRepo occupancy.Repository
 	invoker       invoker.Invoker
+	configSource  invoker.ConfigurationSource
 	config        *Config
 	logger        *zap.SugaredLogger
 	poolID        string
@@ -47,6 +48,13 @@ func NewWorkerPool(retriever ClusterStateRetriever, reconRepo reconciliation.Rep
 	}, nil
 }
 
+//WithConfigurationSource merges the values of the KV buckets of a cluster into the configuration of each
+//invoked component
+func (w *Pool) WithConfigurationSource(source invoker.ConfigurationSource) *Pool {
+	w.configSource = source
+	return w
+}
+
 func (w *Pool) RunOnce(ctx context.Context) error {
 	return w.run(ctx, true)
 }
@@ -136,11 +144,12 @@ func (w *Pool) assignWorker(ctx context.Context, opEntity *model.OperationEntity
 	w.logger.Debugf("Worker pool is assigning operation '%s' to worker", opEntity)
 	maxOpRetries := w.config.MaxOperationRetries - int(opEntity.Retries)
 	err = (&worker{
-		reconRepo:  w.reconRepo,
-		invoker:    w.invoker,
-		logger:     w.logger,
-		maxRetries: w.config.InvokerMaxRetries,
-		retryDelay: w.config.InvokerRetryDelay,
+		reconRepo:    w.reconRepo,
+		invoker:      w.invoker,
+		configSource: w.configSource,
+		logger:       w.logger,
+		maxRetries:   w.config.InvokerMaxRetries,
+		retryDelay:   w.config.InvokerRetryDelay,
 	}).run(ctx, clusterState, opEntity, maxOpRetries)
 	if err != nil {
 		w.logger.Warnf("Worker pool received an error from worker assigned to operation '%s': %s", opEntity, err)
@@ -69,7 +69,7 @@ func TestWorkerPool(t *testing.T) {
 	require.NoError(t, err)
 
 	//add cluster to inventory
-	clusterState, err := inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "1", 1, false, kebTest.OneComponentDummy))
+	clusterState, err := inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "1", 1, false, kebTest.OneComponentDummy), nil)
 	require.NoError(t, err)
 
 	//create test invoker to be able to verify invoker calls
@@ -129,7 +129,7 @@ func TestWorkerPoolMaxOpRetriesReached(t *testing.T) {
 	require.NoError(t, err)
 
 	//add cluster to inventory
-	clusterState, err := inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "2", 1, false, kebTest.OneComponentDummy))
+	clusterState, err := inventory.CreateOrUpdate(1, kebTest.NewCluster(t, "2", 1, false, kebTest.OneComponentDummy), nil)
 	require.NoError(t, err)
 
 	//create test invoker to be able to verify invoker calls
@@ -234,7 +234,7 @@ func TestWorkerPoolParallel(t *testing.T) {
 		//add clusters to inventory
 		var clusterStates [countKebClusters]*cluster.State
 		for i := range kebClusters {
-			clusterStates[i], err = inventory.CreateOrUpdate(1, kebClusters[i])
+			clusterStates[i], err = inventory.CreateOrUpdate(1, kebClusters[i], nil)
 		}
 		require.NoError(t, err)
 